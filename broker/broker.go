@@ -0,0 +1,35 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package broker provides message brokers that allow multiple
+// server replicas to share build events and live log streams.
+package broker
+
+import "context"
+
+// Broker publishes opaque payloads to named channels and
+// delivers them to every subscriber of the channel, including
+// subscribers on the publishing replica.
+type Broker interface {
+	// Publish publishes the payload to the named channel.
+	Publish(ctx context.Context, channel string, data []byte) error
+
+	// Subscribe subscribes to the named channel. The returned
+	// channel is closed when the context is cancelled.
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+// this is the amount of payloads that are buffered per
+// subscriber before newer payloads are dropped.
+const bufferSize = 1000
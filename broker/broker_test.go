@@ -0,0 +1,126 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewMemory()
+	sub1, _ := b.Subscribe(ctx, "foo")
+	sub2, _ := b.Subscribe(ctx, "foo")
+	sub3, _ := b.Subscribe(ctx, "bar")
+
+	b.Publish(ctx, "foo", []byte("hello"))
+
+	for _, sub := range []<-chan []byte{sub1, sub2} {
+		select {
+		case data := <-sub:
+			if got, want := string(data), "hello"; got != want {
+				t.Errorf("Want payload %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Want payload delivered to subscriber")
+		}
+	}
+	select {
+	case <-sub3:
+		t.Errorf("Want payload not delivered to other channels")
+	default:
+	}
+
+	cancel()
+	select {
+	case _, ok := <-sub1:
+		if ok {
+			t.Errorf("Want subscription closed")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Want subscription closed when context cancelled")
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	chunks := split("abc", data, 64)
+	if got, want := len(chunks), 21; got != want {
+		t.Fatalf("Want %d chunks, got %d", want, got)
+	}
+
+	// chunks from interleaved payloads and out of order
+	// chunks must be reassembled.
+	other := split("def", []byte("hello"), 64)
+	chunks[0], chunks[1] = chunks[1], chunks[0]
+
+	now := time.Now()
+	a := newAssembler()
+	if got, done, err := a.add(other[0], now); err != nil || !done || string(got) != "hello" {
+		t.Errorf("Want single chunk payload decoded, got %q %v %v", got, done, err)
+	}
+	for i, chunk := range chunks {
+		got, done, err := a.add(chunk, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i < len(chunks)-1 {
+			if done {
+				t.Fatalf("Want payload incomplete at chunk %d", i)
+			}
+			continue
+		}
+		if !done {
+			t.Fatalf("Want payload complete")
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("Want reassembled payload to match")
+		}
+	}
+	if len(a.partials) != 0 {
+		t.Errorf("Want partial payloads removed")
+	}
+}
+
+func TestAssembler_Expired(t *testing.T) {
+	chunks := split("abc", []byte("hello world"), 4)
+	now := time.Now()
+	a := newAssembler()
+	a.add(chunks[0], now)
+	a.add(split("def", []byte("hello world"), 4)[0], now.Add(chunkExpiry+time.Second))
+	if _, ok := a.partials["abc"]; ok {
+		t.Errorf("Want expired partial payload removed")
+	}
+}
+
+func TestAssembler_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"abc:0:1",
+		"abc:x:1:aGVsbG8=",
+		"abc:0:x:aGVsbG8=",
+		"abc:1:1:aGVsbG8=",
+	}
+	for _, test := range tests {
+		if _, _, err := newAssembler().add(test, time.Now()); err != errChunkInvalid {
+			t.Errorf("Want invalid chunk error for %q, got %v", test, err)
+		}
+	}
+}
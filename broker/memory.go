@@ -0,0 +1,76 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+type memory struct {
+	sync.Mutex
+
+	subs map[string]map[chan []byte]struct{}
+}
+
+// NewMemory returns a new in-memory broker. The in-memory
+// broker does not distribute payloads across replicas and
+// is intended as a local stand-in for a shared broker.
+func NewMemory() Broker {
+	return &memory{
+		subs: map[string]map[chan []byte]struct{}{},
+	}
+}
+
+func (b *memory) Publish(ctx context.Context, channel string, data []byte) error {
+	b.Lock()
+	for sub := range b.subs[channel] {
+		deliver(sub, data)
+	}
+	b.Unlock()
+	return nil
+}
+
+func (b *memory) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := make(chan []byte, bufferSize)
+	b.Lock()
+	if b.subs[channel] == nil {
+		b.subs[channel] = map[chan []byte]struct{}{}
+	}
+	b.subs[channel][sub] = struct{}{}
+	b.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.Lock()
+		delete(b.subs[channel], sub)
+		if len(b.subs[channel]) == 0 {
+			delete(b.subs, channel)
+		}
+		close(sub)
+		b.Unlock()
+	}()
+	return sub, nil
+}
+
+// deliver sends the payload to the subscriber without
+// blocking. If there is a slow consumer the buffered channel
+// fills and newer payloads are ignored.
+func deliver(sub chan []byte, data []byte) {
+	select {
+	case sub <- data:
+	default:
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// postgres limits notification payloads to 8000 bytes.
+// Larger payloads are split into chunks that are reassembled
+// by the receiving replicas.
+const chunkSize = 7000
+
+// partial payloads that are not completed within the expiry
+// window are discarded.
+const chunkExpiry = time.Minute
+
+// error returned when a notification cannot be parsed.
+var errChunkInvalid = errors.New("broker: invalid payload chunk")
+
+type postgres struct {
+	sync.Mutex
+
+	db       *sql.DB
+	listener *pq.Listener
+	parts    *assembler
+	subs     map[string]map[chan []byte]struct{}
+}
+
+// NewPostgres returns a new broker that distributes payloads
+// using the postgres LISTEN and NOTIFY commands.
+func NewPostgres(datasource string) (Broker, error) {
+	db, err := sql.Open("postgres", datasource)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	b := &postgres{
+		db:    db,
+		parts: newAssembler(),
+		subs:  map[string]map[chan []byte]struct{}{},
+		listener: pq.NewListener(datasource, 10*time.Second, time.Minute,
+			func(event pq.ListenerEventType, err error) {
+				if err != nil {
+					logrus.WithError(err).Warnln("broker: postgres listener error")
+				}
+			},
+		),
+	}
+	go b.listen()
+	return b, nil
+}
+
+func (b *postgres) Publish(ctx context.Context, channel string, data []byte) error {
+	for _, chunk := range split(uniuri.NewLen(16), data, chunkSize) {
+		_, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, chunk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *postgres) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := make(chan []byte, bufferSize)
+	b.Lock()
+	if b.subs[channel] == nil {
+		if err := b.listener.Listen(channel); err != nil {
+			b.Unlock()
+			return nil, err
+		}
+		b.subs[channel] = map[chan []byte]struct{}{}
+	}
+	b.subs[channel][sub] = struct{}{}
+	b.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.Lock()
+		delete(b.subs[channel], sub)
+		if len(b.subs[channel]) == 0 {
+			delete(b.subs, channel)
+			b.listener.Unlisten(channel)
+		}
+		close(sub)
+		b.Unlock()
+	}()
+	return sub, nil
+}
+
+func (b *postgres) listen() {
+	for {
+		select {
+		case n, ok := <-b.listener.Notify:
+			if !ok {
+				return
+			}
+			// a nil notification is sent when the listener
+			// re-establishes the database connection.
+			if n == nil {
+				continue
+			}
+			data, done, err := b.parts.add(n.Extra, time.Now())
+			if err != nil {
+				logrus.WithError(err).
+					WithField("channel", n.Channel).
+					Warnln("broker: cannot decode notification")
+				continue
+			}
+			if !done {
+				continue
+			}
+			b.Lock()
+			for sub := range b.subs[n.Channel] {
+				deliver(sub, data)
+			}
+			b.Unlock()
+		case <-time.After(90 * time.Second):
+			go b.listener.Ping()
+		}
+	}
+}
+
+// split encodes the payload and splits it into chunks in the
+// format id:index:total:data, where data is base64 encoded.
+func split(id string, data []byte, size int) []string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	total := (len(encoded) + size - 1) / size
+	if total == 0 {
+		total = 1
+	}
+	chunks := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * size
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		chunks = append(chunks, strings.Join([]string{
+			id,
+			strconv.Itoa(i),
+			strconv.Itoa(total),
+			encoded[i*size : end],
+		}, ":"))
+	}
+	return chunks
+}
+
+// assembler reassembles payloads that were split into
+// chunks by the publishing replica.
+type assembler struct {
+	partials map[string]*partial
+}
+
+type partial struct {
+	chunks  []string
+	count   int
+	created time.Time
+}
+
+func newAssembler() *assembler {
+	return &assembler{
+		partials: map[string]*partial{},
+	}
+}
+
+// add adds the chunk and returns the decoded payload once all
+// chunks of the payload have been received.
+func (a *assembler) add(chunk string, now time.Time) ([]byte, bool, error) {
+	parts := strings.SplitN(chunk, ":", 4)
+	if len(parts) != 4 {
+		return nil, false, errChunkInvalid
+	}
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, false, errChunkInvalid
+	}
+	total, err := strconv.Atoi(parts[2])
+	if err != nil || total < 1 || index < 0 || index >= total {
+		return nil, false, errChunkInvalid
+	}
+	if total == 1 {
+		data, err := base64.StdEncoding.DecodeString(parts[3])
+		return data, err == nil, err
+	}
+
+	for id, p := range a.partials {
+		if now.Sub(p.created) > chunkExpiry {
+			delete(a.partials, id)
+		}
+	}
+
+	id := parts[0]
+	p, ok := a.partials[id]
+	if !ok {
+		p = &partial{
+			chunks:  make([]string, total),
+			created: now,
+		}
+		a.partials[id] = p
+	}
+	if len(p.chunks) != total {
+		delete(a.partials, id)
+		return nil, false, errChunkInvalid
+	}
+	if p.chunks[index] == "" {
+		p.count++
+	}
+	p.chunks[index] = parts[3]
+	if p.count < total {
+		return nil, false, nil
+	}
+	delete(a.partials, id)
+	data, err := base64.StdEncoding.DecodeString(strings.Join(p.chunks, ""))
+	return data, err == nil, err
+}
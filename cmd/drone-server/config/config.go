@@ -17,6 +17,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -49,7 +50,9 @@ type (
 
 		Authn        Authentication
 		Agent        Agent
+		Artifacts    Artifacts
 		AzureBlob    AzureBlob
+		BuildLogs    BuildLogs
 		Convert      Convert
 		Cleanup      Cleanup
 		Cron         Cron
@@ -61,20 +64,25 @@ type (
 		Jsonnet      Jsonnet
 		Starlark     Starlark
 		Logging      Logging
+		OIDC         OIDC
 		Prometheus   Prometheus
 		Proxy        Proxy
+		Pubsub       Pubsub
 		Registration Registration
 		Registries   Registries
 		Repository   Repository
+		Retention    Retention
 		Runner       Runner
 		RPC          RPC
 		S3           S3
+		Scheduler    Scheduler
 		Secrets      Secrets
 		Server       Server
 		Session      Session
 		Status       Status
 		Users        Users
 		Validate     Validate
+		Watchdog     Watchdog
 		Webhook      Webhook
 		Yaml         Yaml
 
@@ -96,6 +104,20 @@ type (
 		Pull       string `envconfig:"DRONE_GIT_IMAGE_PULL" default:"IfNotExists"`
 	}
 
+	// Artifacts provides the build artifact storage configuration.
+	Artifacts struct {
+		Path string `envconfig:"DRONE_ARTIFACTS_PATH"`
+
+		// MaxSize is the maximum size of an uploaded artifact.
+		// Uploads that exceed the limit are rejected.
+		MaxSize Bytes `envconfig:"DRONE_ARTIFACTS_MAX_SIZE" default:"100MB"`
+	}
+
+	// BuildLogs provides the build log storage configuration.
+	BuildLogs struct {
+		Compression string `envconfig:"DRONE_BUILD_LOGS_COMPRESSION"`
+	}
+
 	Cleanup struct {
 		Disabled bool          `envconfig:"DRONE_CLEANUP_DISABLED"`
 		Interval time.Duration `envconfig:"DRONE_CLEANUP_INTERVAL"         default:"24h"`
@@ -103,6 +125,13 @@ type (
 		Pending  time.Duration `envconfig:"DRONE_CLEANUP_DEADLINE_PENDING" default:"24h"`
 	}
 
+	// Pubsub provides the configuration of the message broker
+	// used to share events and logs across server replicas.
+	Pubsub struct {
+		Driver     string `envconfig:"DRONE_PUBSUB_DRIVER"`
+		Datasource string `envconfig:"DRONE_PUBSUB_DATASOURCE"`
+	}
+
 	// Cron provides the cron configuration.
 	Cron struct {
 		Disabled bool          `envconfig:"DRONE_CRON_DISABLED"`
@@ -116,6 +145,13 @@ type (
 		Secret         string `envconfig:"DRONE_DATABASE_SECRET"`
 		MaxConnections int    `envconfig:"DRONE_DATABASE_MAX_CONNECTIONS" default:"0"`
 
+		// Keyring provides named encryption keys. New data is
+		// encrypted with the active key, and data encrypted with
+		// any key in the keyring, or the legacy secret, can be
+		// decrypted.
+		Keyring       map[string]string `envconfig:"DRONE_DATABASE_KEYRING"`
+		KeyringActive string            `envconfig:"DRONE_DATABASE_KEYRING_ACTIVE"`
+
 		// Feature flag
 		LegacyBatch bool `envconfig:"DRONE_DATABASE_LEGACY_BATCH"`
 
@@ -161,6 +197,18 @@ type (
 		Text   bool `envconfig:"DRONE_LOGS_TEXT"`
 	}
 
+	// OIDC provides the OpenID Connect identity token
+	// configuration. Signing keys are named paths to PEM
+	// encoded RSA private keys. Tokens are signed with the
+	// active key, and all keys are published so that keys
+	// can be rotated without invalidating issued tokens.
+	OIDC struct {
+		Audience  string            `envconfig:"DRONE_OIDC_AUDIENCE"`
+		Expiry    time.Duration     `envconfig:"DRONE_OIDC_TOKEN_EXPIRY" default:"1h"`
+		Keys      map[string]string `envconfig:"DRONE_OIDC_SIGNING_KEYS"`
+		KeyActive string            `envconfig:"DRONE_OIDC_SIGNING_KEY_ACTIVE"`
+	}
+
 	// Prometheus provides the prometheus configuration.
 	Prometheus struct {
 		EnableAnonymousAccess bool `envconfig:"DRONE_PROMETHEUS_ANONYMOUS_ACCESS" default:"false"`
@@ -177,6 +225,16 @@ type (
 		Ignore []string `envconfig:"DRONE_REPOSITORY_IGNORE"`
 	}
 
+	// Retention provides the build and log retention
+	// configuration.
+	Retention struct {
+		Disabled bool          `envconfig:"DRONE_RETENTION_DISABLED"`
+		Interval time.Duration `envconfig:"DRONE_RETENTION_INTERVAL"      default:"24h"`
+		Builds   int           `envconfig:"DRONE_RETENTION_KEEP_BUILDS"`
+		Days     int           `envconfig:"DRONE_RETENTION_KEEP_DAYS"`
+		LogDays  int           `envconfig:"DRONE_RETENTION_KEEP_LOG_DAYS"`
+	}
+
 	// Registries provides the registry configuration.
 	Registries struct {
 		Endpoint   string `envconfig:"DRONE_REGISTRY_ENDPOINT"`
@@ -232,6 +290,15 @@ type (
 		}
 	}
 
+	// Scheduler provides the scheduler configuration.
+	Scheduler struct {
+		Interval  time.Duration  `envconfig:"DRONE_SCHEDULER_INTERVAL"         default:"1m"`
+		Lease     time.Duration  `envconfig:"DRONE_SCHEDULER_LEASE"            default:"1m"`
+		FairShare string         `envconfig:"DRONE_SCHEDULER_FAIR_SHARE"`
+		Shares    map[string]int `envconfig:"DRONE_SCHEDULER_SHARES"`
+		Decay     time.Duration  `envconfig:"DRONE_SCHEDULER_FAIR_SHARE_DECAY" default:"1h"`
+	}
+
 	// Server provides the server configuration.
 	Server struct {
 		Addr  string `envconfig:"-"`
@@ -285,12 +352,24 @@ type (
 		MinAge time.Duration `envconfig:"DRONE_MIN_AGE"`
 	}
 
+	// Watchdog provides the build, queue and step timeout
+	// enforcement configuration.
+	Watchdog struct {
+		Disabled bool          `envconfig:"DRONE_WATCHDOG_DISABLED"`
+		Interval time.Duration `envconfig:"DRONE_WATCHDOG_INTERVAL" default:"1m"`
+	}
+
 	// Webhook provides the webhook configuration.
 	Webhook struct {
 		Events     []string `envconfig:"DRONE_WEBHOOK_EVENTS"`
 		Endpoint   []string `envconfig:"DRONE_WEBHOOK_ENDPOINT"`
 		Secret     string   `envconfig:"DRONE_WEBHOOK_SECRET"`
 		SkipVerify bool     `envconfig:"DRONE_WEBHOOK_SKIP_VERIFY"`
+
+		Retries  int           `envconfig:"DRONE_WEBHOOK_RETRIES"        default:"8"`
+		Backoff  time.Duration `envconfig:"DRONE_WEBHOOK_BACKOFF"        default:"30s"`
+		History  time.Duration `envconfig:"DRONE_WEBHOOK_HISTORY"        default:"720h"`
+		Interval time.Duration `envconfig:"DRONE_WEBHOOK_RETRY_INTERVAL" default:"30s"`
 	}
 
 	// Yaml provides the yaml webhook configuration.
@@ -411,6 +490,12 @@ type (
 		ContentTypeNosniff    bool              `envconfig:"DRONE_HTTP_CONTENT_TYPE_NO_SNIFF"`
 		ContentSecurityPolicy string            `envconfig:"DRONE_HTTP_CONTENT_SECURITY_POLICY"`
 		ReferrerPolicy        string            `envconfig:"DRONE_HTTP_REFERRER_POLICY"`
+
+		// TrustedProxies is a list of proxy addresses or CIDR
+		// ranges (e.g. 10.0.0.0/8) that are trusted to set the
+		// X-Forwarded-For and X-Real-IP headers. The headers are
+		// ignored for requests received from any other address.
+		TrustedProxies []string `envconfig:"DRONE_HTTP_TRUSTED_PROXIES"`
 	}
 )
 
@@ -427,6 +512,12 @@ func Environ() (Config, error) {
 	if err := kubernetesServiceConflict(&cfg); err != nil {
 		return cfg, err
 	}
+	if err := validateFairShare(&cfg); err != nil {
+		return cfg, err
+	}
+	if err := validateTrustedProxies(&cfg); err != nil {
+		return cfg, err
+	}
 	return cfg, err
 }
 
@@ -542,6 +633,27 @@ func kubernetesServiceConflict(c *Config) error {
 	return nil
 }
 
+func validateFairShare(c *Config) error {
+	switch c.Scheduler.FairShare {
+	case "", "repository", "namespace":
+		return nil
+	default:
+		return fmt.Errorf("Invalid fair-share mode %q. Valid modes are repository and namespace", c.Scheduler.FairShare)
+	}
+}
+
+func validateTrustedProxies(c *Config) error {
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err == nil {
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			return fmt.Errorf("Invalid trusted proxy %q. Expect an ip address or cidr range", proxy)
+		}
+	}
+	return nil
+}
+
 // Bytes stores number bytes (e.g. megabytes)
 type Bytes int64
 
@@ -38,6 +38,7 @@ var pluginSet = wire.NewSet(
 	provideSecretPlugin,
 	provideValidatePlugin,
 	provideWebhookPlugin,
+	provideWebhookRetrier,
 )
 
 // provideAdmissionPlugin is a Wire provider function that
@@ -154,11 +155,28 @@ func provideValidatePlugin(conf spec.Config) core.ValidateService {
 
 // provideWebhookPlugin is a Wire provider function that returns
 // a webhook plugin based on the environment configuration.
-func provideWebhookPlugin(config spec.Config, system *core.System) core.WebhookSender {
-	return webhook.New(webhook.Config{
-		Events:   config.Webhook.Events,
-		Endpoint: config.Webhook.Endpoint,
-		Secret:   config.Webhook.Secret,
-		System:   system,
-	})
+func provideWebhookPlugin(config spec.Config, system *core.System, webhooks core.WebhookStore, deliveries core.WebhookDeliveryStore) core.WebhookSender {
+	return webhook.New(provideWebhookConfig(config, system, webhooks, deliveries))
+}
+
+// provideWebhookRetrier is a Wire provider function that returns
+// a webhook retrier based on the environment configuration.
+func provideWebhookRetrier(config spec.Config, system *core.System, webhooks core.WebhookStore, deliveries core.WebhookDeliveryStore) *webhook.Retrier {
+	return webhook.NewRetrier(provideWebhookConfig(config, system, webhooks, deliveries))
+}
+
+// helper function returns the webhook configuration.
+func provideWebhookConfig(config spec.Config, system *core.System, webhooks core.WebhookStore, deliveries core.WebhookDeliveryStore) webhook.Config {
+	return webhook.Config{
+		Events:     config.Webhook.Events,
+		Endpoint:   config.Webhook.Endpoint,
+		Secret:     config.Webhook.Secret,
+		SkipVerify: config.Webhook.SkipVerify,
+		System:     system,
+		Webhooks:   webhooks,
+		Deliveries: deliveries,
+		Retries:    config.Webhook.Retries,
+		Backoff:    config.Webhook.Backoff,
+		History:    config.Webhook.History,
+	}
 }
@@ -29,6 +29,18 @@ var schedulerSet = wire.NewSet(
 
 // provideScheduler is a Wire provider function that returns a
 // scheduler based on the environment configuration.
-func provideScheduler(store core.StageStore, config config.Config) core.Scheduler {
-	return queue.New(store)
+func provideScheduler(
+	store core.StageStore,
+	quotas core.QuotaStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	config config.Config,
+) core.Scheduler {
+	return queue.New(store, quotas, repos, builds, queue.Config{
+		Interval:  config.Scheduler.Interval,
+		Lease:     config.Scheduler.Lease,
+		FairShare: config.Scheduler.FairShare,
+		Shares:    config.Scheduler.Shares,
+		Decay:     config.Scheduler.Decay,
+	})
 }
@@ -59,8 +59,9 @@ var serverSet = wire.NewSet(
 
 // provideRouter is a Wire provider function that returns a
 // router that is serves the provided handlers.
-func provideRouter(api api.Server, web web.Server, rpcv1 rpcHandlerV1, rpcv2 rpcHandlerV2, healthz healthzHandler, metrics *metric.Server, pprof pprofHandler) *chi.Mux {
+func provideRouter(api api.Server, web web.Server, rpcv1 rpcHandlerV1, rpcv2 rpcHandlerV2, healthz healthzHandler, metrics *metric.Server, pprof pprofHandler, config config.Config) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(server.RealIP(config.HTTP.TrustedProxies))
 	r.Mount("/healthz", healthz)
 	r.Mount("/metrics", metrics)
 	r.Mount("/api", api.Handler())
@@ -100,14 +101,14 @@ func providePprof(config config.Config) pprofHandler {
 // provideRPC is a Wire provider function that returns an rpc
 // handler that exposes the build manager to a remote agent.
 func provideRPC(m manager.BuildManager, config config.Config) rpcHandlerV1 {
-	v := rpc.NewServer(m, config.RPC.Secret)
+	v := rpc.NewServer(m, config.RPC.Secret, config.Artifacts.MaxSize.Int64())
 	return rpcHandlerV1(v)
 }
 
 // provideRPC2 is a Wire provider function that returns an rpc
 // handler that exposes the build manager to a remote agent.
 func provideRPC2(m manager.BuildManager, config config.Config) rpcHandlerV2 {
-	v := rpc2.NewServer(m, config.RPC.Secret)
+	v := rpc2.NewServer(m, config.RPC.Secret, config.Artifacts.MaxSize.Int64())
 	return rpcHandlerV2(v)
 }
 
@@ -15,8 +15,11 @@
 package main
 
 import (
+	"errors"
+	"io/ioutil"
 	"time"
 
+	"github.com/drone/drone/broker"
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/livelog"
@@ -24,6 +27,7 @@ import (
 	"github.com/drone/drone/pubsub"
 	"github.com/drone/drone/service/canceler"
 	"github.com/drone/drone/service/canceler/reaper"
+	"github.com/drone/drone/service/canceler/watchdog"
 	"github.com/drone/drone/service/commit"
 	contents "github.com/drone/drone/service/content"
 	"github.com/drone/drone/service/content/cache"
@@ -31,8 +35,11 @@ import (
 	"github.com/drone/drone/service/hook/parser"
 	"github.com/drone/drone/service/linker"
 	"github.com/drone/drone/service/netrc"
+	"github.com/drone/drone/service/oidc"
 	orgs "github.com/drone/drone/service/org"
 	"github.com/drone/drone/service/repo"
+	"github.com/drone/drone/service/resetter"
+	"github.com/drone/drone/service/retention"
 	"github.com/drone/drone/service/status"
 	"github.com/drone/drone/service/syncer"
 	"github.com/drone/drone/service/token"
@@ -52,10 +59,9 @@ var serviceSet = wire.NewSet(
 	canceler.New,
 	commit.New,
 	cron.New,
-	livelog.New,
 	linker.New,
 	parser.New,
-	pubsub.New,
+	resetter.New,
 	token.Renewer,
 	transfer.New,
 	trigger.New,
@@ -63,11 +69,17 @@ var serviceSet = wire.NewSet(
 
 	provideRepositoryService,
 	provideContentService,
+	provideBroker,
 	provideDatadog,
 	provideHookService,
+	provideIdentityService,
+	provideLogStream,
 	provideNetrcService,
 	provideOrgService,
+	providePubsub,
 	provideReaper,
+	provideWatchdog,
+	provideRetention,
 	provideSession,
 	provideStatusService,
 	provideSyncer,
@@ -88,6 +100,27 @@ func provideHookService(client *scm.Client, renewer core.Renewer, config config.
 	return hook.New(client, config.Proxy.Addr, renewer)
 }
 
+// provideIdentityService is a Wire provider function that
+// returns an identity service based on the environment
+// configuration.
+func provideIdentityService(config config.Config) (core.IdentityService, error) {
+	keys := map[string][]byte{}
+	for id, path := range config.OIDC.Keys {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys[id] = data
+	}
+	return oidc.New(oidc.Config{
+		Issuer:   config.Server.Addr,
+		Audience: config.OIDC.Audience,
+		Expiry:   config.OIDC.Expiry,
+		Keys:     keys,
+		Active:   config.OIDC.KeyActive,
+	})
+}
+
 // provideNetrcService is a Wire provider function that returns
 // a netrc service based on the environment configuration.
 func provideNetrcService(client *scm.Client, renewer core.Renewer, config config.Config) core.NetrcService {
@@ -119,9 +152,9 @@ func provideRepositoryService(client *scm.Client, renewer core.Renewer, config c
 
 // provideSession is a Wire provider function that returns a
 // user session based on the environment configuration.
-func provideSession(store core.UserStore, config config.Config) (core.Session, error) {
+func provideSession(store core.UserStore, tokens core.AccessTokenStore, config config.Config) (core.Session, error) {
 	if config.Session.MappingFile != "" {
-		return session.Legacy(store, session.Config{
+		return session.Legacy(store, tokens, session.Config{
 			Secure:      config.Session.Secure,
 			Secret:      config.Session.Secret,
 			Timeout:     config.Session.Timeout,
@@ -129,7 +162,7 @@ func provideSession(store core.UserStore, config config.Config) (core.Session, e
 		})
 	}
 
-	return session.New(store, session.NewConfig(
+	return session.New(store, tokens, session.NewConfig(
 		config.Session.Secret,
 		config.Session.Timeout,
 		config.Session.Secure),
@@ -193,6 +226,87 @@ func provideReaper(
 	)
 }
 
+// provideWatchdog is a Wire provider function that returns the
+// build, queue and step timeout watchdog.
+func provideWatchdog(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	canceler core.Canceler,
+) *watchdog.Watchdog {
+	return watchdog.New(
+		repos,
+		builds,
+		stages,
+		canceler,
+	)
+}
+
+// provideRetention is a Wire provider function that returns
+// the build and log retention service.
+func provideRetention(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	logs core.LogStore,
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
+	policies core.RetentionStore,
+	config config.Config,
+) *retention.Retention {
+	return retention.New(
+		repos,
+		builds,
+		stages,
+		logs,
+		artifacts,
+		blobs,
+		policies,
+		core.Retention{
+			Builds:  config.Retention.Builds,
+			Days:    config.Retention.Days,
+			LogDays: config.Retention.LogDays,
+		},
+	)
+}
+
+// provideBroker is a Wire provider function that returns the
+// message broker shared by server replicas, or nil if the
+// server is configured to use the in-memory pubsub.
+func provideBroker(config config.Config) (broker.Broker, error) {
+	switch config.Pubsub.Driver {
+	case "":
+		return nil, nil
+	case "postgres":
+		datasource := config.Pubsub.Datasource
+		if datasource == "" && config.Database.Driver == "postgres" {
+			datasource = config.Database.Datasource
+		}
+		return broker.NewPostgres(datasource)
+	default:
+		return nil, errors.New("main: unsupported pubsub driver")
+	}
+}
+
+// providePubsub is a Wire provider function that returns the
+// pubsub, distributed through the message broker if configured.
+func providePubsub(b broker.Broker) (core.Pubsub, error) {
+	if b == nil {
+		return pubsub.New(), nil
+	}
+	return pubsub.NewDistributed(b)
+}
+
+// provideLogStream is a Wire provider function that returns
+// the live log stream, distributed through the message broker
+// if configured.
+func provideLogStream(b broker.Broker) (core.LogStream, error) {
+	if b == nil {
+		return livelog.New(), nil
+	}
+	return livelog.NewDistributed(b)
+}
+
 // provideDatadog is a Wire provider function that returns the
 // datadog sink.
 func provideDatadog(
@@ -18,21 +18,34 @@ import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/metric"
+	"github.com/drone/drone/store/artifact"
+	"github.com/drone/drone/store/artifact/blob"
+	"github.com/drone/drone/store/audit"
 	"github.com/drone/drone/store/batch"
 	"github.com/drone/drone/store/batch2"
 	"github.com/drone/drone/store/build"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/delivery"
+	"github.com/drone/drone/store/environment"
 	"github.com/drone/drone/store/logs"
 	"github.com/drone/drone/store/perm"
+	"github.com/drone/drone/store/quota"
 	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/retention"
+	"github.com/drone/drone/store/retry"
 	"github.com/drone/drone/store/secret"
 	"github.com/drone/drone/store/secret/global"
+	"github.com/drone/drone/store/secret/usage"
+	"github.com/drone/drone/store/secret/version"
 	"github.com/drone/drone/store/shared/db"
 	"github.com/drone/drone/store/shared/encrypt"
 	"github.com/drone/drone/store/stage"
 	"github.com/drone/drone/store/step"
 	"github.com/drone/drone/store/template"
+	"github.com/drone/drone/store/test"
+	"github.com/drone/drone/store/token"
 	"github.com/drone/drone/store/user"
+	"github.com/drone/drone/store/webhook"
 
 	"github.com/google/wire"
 	"github.com/sirupsen/logrus"
@@ -42,6 +55,7 @@ import (
 var storeSet = wire.NewSet(
 	provideDatabase,
 	provideEncrypter,
+	provideArtifactBlobStore,
 	provideBuildStore,
 	provideLogStore,
 	provideRepoStore,
@@ -49,12 +63,24 @@ var storeSet = wire.NewSet(
 	provideUserStore,
 	provideBatchStore,
 	// batch.New,
+	artifact.New,
+	audit.New,
 	cron.New,
+	delivery.New,
+	environment.New,
 	perm.New,
+	quota.New,
+	retention.New,
+	retry.New,
 	secret.New,
 	global.New,
+	version.New,
+	usage.New,
 	step.New,
 	template.New,
+	test.New,
+	token.New,
+	webhook.New,
 )
 
 // provideDatabase is a Wire provider function that provides a
@@ -70,6 +96,13 @@ func provideDatabase(config config.Config) (*db.DB, error) {
 // provideEncrypter is a Wire provider function that provides a
 // database encrypter, configured from the environment.
 func provideEncrypter(config config.Config) (encrypt.Encrypter, error) {
+	if len(config.Database.Keyring) != 0 {
+		keyring, err := provideKeyring(config)
+		if err != nil {
+			return nil, err
+		}
+		return keyring, nil
+	}
 	enc, err := encrypt.New(config.Database.Secret)
 	// mixed-content mode should be set to true if the database
 	// originally had encryption disabled and therefore has
@@ -86,6 +119,34 @@ func provideEncrypter(config config.Config) (encrypt.Encrypter, error) {
 	return enc, err
 }
 
+// provideKeyring is a Wire provider function that provides a
+// database encryption keyring, configured from the environment.
+// The legacy database secret, if configured, is used to decrypt
+// data encrypted before the keyring was enabled.
+func provideKeyring(config config.Config) (*encrypt.Keyring, error) {
+	active := config.Database.KeyringActive
+	if active == "" && len(config.Database.Keyring) == 1 {
+		for id := range config.Database.Keyring {
+			active = id
+		}
+	}
+	keyring, err := encrypt.NewKeyring(
+		active,
+		config.Database.Keyring,
+		config.Database.Secret,
+	)
+	if err != nil {
+		return nil, err
+	}
+	logrus.WithField("key", active).
+		Debugln("main: database encryption keyring enabled")
+	if config.Database.EncryptMixedContent {
+		logrus.Debugln("main: database encryption mixed-mode enabled")
+		keyring.Compat = true
+	}
+	return keyring, nil
+}
+
 // provideBuildStore is a Wire provider function that provides a
 // build datastore, configured from the environment, with metrics
 // enabled.
@@ -99,26 +160,76 @@ func provideBuildStore(db *db.DB) core.BuildStore {
 
 // provideLogStore is a Wire provider function that provides a
 // log datastore, configured from the environment.
-func provideLogStore(db *db.DB, config config.Config) core.LogStore {
+func provideLogStore(db *db.DB, config config.Config) (core.LogStore, error) {
 	s := logs.New(db)
+	if p := provideExternalLogStore(config); p != nil {
+		s = logs.NewCombined(p, s)
+	}
+	// logs are always read through the compressed store, even
+	// when compression is disabled, so that previously
+	// compressed logs can be read.
+	return logs.NewCompressed(s, config.BuildLogs.Compression)
+}
+
+// provideExternalLogStore is a helper function that provides
+// the s3 or azure blob log store, configured from the
+// environment. A nil value is returned if neither store is
+// configured.
+func provideExternalLogStore(config config.Config) core.LogStore {
+	if config.S3.Bucket != "" {
+		return logs.NewS3Env(
+			config.S3.Bucket,
+			config.S3.Prefix,
+			config.S3.Endpoint,
+			config.S3.PathStyle,
+		)
+	}
+	if config.AzureBlob.ContainerName != "" {
+		return logs.NewAzureBlobEnv(
+			config.AzureBlob.ContainerName,
+			config.AzureBlob.StorageAccountName,
+			config.AzureBlob.StorageAccessKey,
+		)
+	}
+	return nil
+}
+
+// provideArtifactBlobStore is a Wire provider function that
+// provides the artifact contents store, configured from the
+// environment. Artifacts are written to the s3 or azure blob
+// storage configured for build logs, if available, followed
+// by the local filesystem, and finally the database.
+func provideArtifactBlobStore(db *db.DB, config config.Config) core.ArtifactBlobStore {
+	if p := provideExternalArtifactBlobStore(config); p != nil {
+		return p
+	}
+	if config.Artifacts.Path != "" {
+		return blob.NewFileSystem(config.Artifacts.Path)
+	}
+	return blob.New(db)
+}
+
+// provideExternalArtifactBlobStore is a helper function that
+// provides the s3 or azure blob artifact store, configured
+// from the environment. A nil value is returned if neither
+// store is configured.
+func provideExternalArtifactBlobStore(config config.Config) core.ArtifactBlobStore {
 	if config.S3.Bucket != "" {
-		p := logs.NewS3Env(
+		return blob.NewS3Env(
 			config.S3.Bucket,
 			config.S3.Prefix,
 			config.S3.Endpoint,
 			config.S3.PathStyle,
 		)
-		return logs.NewCombined(p, s)
 	}
 	if config.AzureBlob.ContainerName != "" {
-		p := logs.NewAzureBlobEnv(
+		return blob.NewAzureBlobEnv(
 			config.AzureBlob.ContainerName,
 			config.AzureBlob.StorageAccountName,
 			config.AzureBlob.StorageAccessKey,
 		)
-		return logs.NewCombined(p, s)
 	}
-	return s
+	return nil
 }
 
 // provideStageStore is a Wire provider function that provides a
@@ -24,8 +24,11 @@ import (
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/metric/sink"
 	"github.com/drone/drone/operator/runner"
-	"github.com/drone/drone/service/canceler/reaper"
+	"github.com/drone/drone/plugin/webhook"
 	"github.com/drone/drone/server"
+	"github.com/drone/drone/service/canceler/reaper"
+	"github.com/drone/drone/service/canceler/watchdog"
+	"github.com/drone/drone/service/retention"
 	"github.com/drone/drone/trigger/cron"
 	"github.com/drone/signal"
 
@@ -40,7 +43,11 @@ import (
 
 func main() {
 	var envfile string
+	var recompress bool
+	var rotate bool
 	flag.StringVar(&envfile, "env-file", ".env", "Read in a file of environment variables")
+	flag.BoolVar(&recompress, "recompress-logs", false, "Compress existing logs and exit")
+	flag.BoolVar(&rotate, "rotate-encryption-key", false, "Re-encrypt existing data with the active encryption key and exit")
 	flag.Parse()
 
 	godotenv.Load(envfile)
@@ -61,6 +68,29 @@ func main() {
 		fmt.Println(config.String())
 	}
 
+	// optionally compress the existing logs and exit. The
+	// migration can be run while the server is running, and
+	// can be safely interrupted and resumed.
+	if recompress {
+		if err := recompressLogs(ctx, config); err != nil {
+			logger := logrus.WithError(err)
+			logger.Fatalln("main: cannot recompress logs")
+		}
+		return
+	}
+
+	// optionally re-encrypt the existing data with the active
+	// encryption key and exit. The migration can be run while
+	// the server is running, and can be safely interrupted and
+	// resumed.
+	if rotate {
+		if err := rotateKeys(ctx, config); err != nil {
+			logger := logrus.WithError(err)
+			logger.Fatalln("main: cannot rotate encryption key")
+		}
+		return
+	}
+
 	app, err := InitializeApplication(config)
 	if err != nil {
 		logger := logrus.WithError(err)
@@ -127,6 +157,37 @@ func main() {
 		return app.reaper.Start(ctx, config.Cleanup.Interval)
 	})
 
+	// launches the watchdog in a goroutine. If the watchdog
+	// is disabled, the goroutine exits immediately without
+	// error.
+	g.Go(func() (err error) {
+		if config.Watchdog.Disabled {
+			return nil
+		}
+		logrus.WithField("interval", config.Watchdog.Interval.String()).
+			Infoln("starting the timeout watchdog")
+		return app.watchdog.Start(ctx, config.Watchdog.Interval)
+	})
+
+	// launches the retention process in a goroutine. If the
+	// retention process is disabled, the goroutine exits
+	// immediately without error.
+	g.Go(func() (err error) {
+		if config.Retention.Disabled {
+			return nil
+		}
+		logrus.WithField("interval", config.Retention.Interval.String()).
+			Infoln("starting the build retention process")
+		return app.retention.Start(ctx, config.Retention.Interval)
+	})
+
+	// launches the webhook retrier in a goroutine.
+	g.Go(func() (err error) {
+		logrus.WithField("interval", config.Webhook.Interval.String()).
+			Infoln("starting the webhook retrier")
+		return app.webhooks.Start(ctx, config.Webhook.Interval)
+	})
+
 	// launches the build runner in a goroutine. If the local
 	// runner is disabled (because nomad or kubernetes is enabled)
 	// then the goroutine exits immediately without error.
@@ -166,28 +227,37 @@ func initLogging(c config.Config) {
 
 // application is the main struct for the Drone server.
 type application struct {
-	cron   *cron.Scheduler
-	reaper *reaper.Reaper
-	sink   *sink.Datadog
-	runner *runner.Runner
-	server *server.Server
-	users  core.UserStore
+	cron      *cron.Scheduler
+	reaper    *reaper.Reaper
+	retention *retention.Retention
+	sink      *sink.Datadog
+	runner    *runner.Runner
+	server    *server.Server
+	users     core.UserStore
+	watchdog  *watchdog.Watchdog
+	webhooks  *webhook.Retrier
 }
 
 // newApplication creates a new application struct.
 func newApplication(
 	cron *cron.Scheduler,
 	reaper *reaper.Reaper,
+	retention *retention.Retention,
 	sink *sink.Datadog,
 	runner *runner.Runner,
 	server *server.Server,
-	users core.UserStore) application {
+	users core.UserStore,
+	watchdog *watchdog.Watchdog,
+	webhooks *webhook.Retrier) application {
 	return application{
-		users:  users,
-		cron:   cron,
-		sink:   sink,
-		server: server,
-		runner: runner,
-		reaper: reaper,
+		users:     users,
+		cron:      cron,
+		sink:      sink,
+		server:    server,
+		runner:    runner,
+		reaper:    reaper,
+		retention: retention,
+		watchdog:  watchdog,
+		webhooks:  webhooks,
 	}
 }
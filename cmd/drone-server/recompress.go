@@ -0,0 +1,61 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"github.com/drone/drone/cmd/drone-server/config"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/logs"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recompressLogs compresses the existing logs in the database
+// and, if configured, the s3 or azure blob log store. Each store
+// is migrated separately so that logs are compressed in place.
+func recompressLogs(ctx context.Context, config config.Config) error {
+	db, err := provideDatabase(config)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stores := map[string]core.LogStore{
+		"database": logs.New(db),
+	}
+	if s := provideExternalLogStore(config); s != nil {
+		stores["external"] = s
+	}
+	for _, name := range []string{"database", "external"} {
+		store, ok := stores[name]
+		if !ok {
+			continue
+		}
+		logger := logrus.WithField("store", name)
+		logger.Infoln("recompressing logs")
+
+		result, err := logs.Recompress(ctx, db, store)
+		if err != nil {
+			return err
+		}
+		logger.WithField("compressed", result.Compressed).
+			WithField("skipped", result.Skipped).
+			WithField("failed", result.Failed).
+			Infoln("recompressed logs")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drone/drone/cmd/drone-server/config"
+	"github.com/drone/drone/store/shared/rekey"
+
+	"github.com/sirupsen/logrus"
+)
+
+var errKeyringNotConfigured = errors.New("database encryption keyring is not configured")
+
+// rotateKeys re-encrypts the encrypted database columns with
+// the active key of the encryption keyring. The user table is
+// only migrated if user table encryption is enabled.
+func rotateKeys(ctx context.Context, config config.Config) error {
+	if len(config.Database.Keyring) == 0 {
+		return errKeyringNotConfigured
+	}
+	keyring, err := provideKeyring(config)
+	if err != nil {
+		return err
+	}
+	db, err := provideDatabase(config)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables := append([]rekey.Table{}, rekey.Secrets...)
+	if config.Database.EncryptUserTable {
+		tables = append(tables, rekey.Users)
+	}
+	for _, table := range tables {
+		logger := logrus.
+			WithField("table", table.Name).
+			WithField("key", keyring.Active())
+		logger.Infoln("re-encrypting table")
+
+		result, err := rekey.Rotate(ctx, db, keyring, table)
+		if err != nil {
+			return err
+		}
+		logger.WithField("rotated", result.Rotated).
+			WithField("skipped", result.Skipped).
+			WithField("failed", result.Failed).
+			Infoln("re-encrypted table")
+	}
+	return nil
+}
@@ -9,23 +9,34 @@ import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/handler/api"
 	"github.com/drone/drone/handler/web"
-	"github.com/drone/drone/livelog"
 	"github.com/drone/drone/operator/manager"
-	"github.com/drone/drone/pubsub"
 	"github.com/drone/drone/service/canceler"
 	"github.com/drone/drone/service/commit"
 	"github.com/drone/drone/service/hook/parser"
 	"github.com/drone/drone/service/license"
 	"github.com/drone/drone/service/linker"
+	"github.com/drone/drone/service/resetter"
 	"github.com/drone/drone/service/token"
 	"github.com/drone/drone/service/transfer"
 	"github.com/drone/drone/service/user"
+	"github.com/drone/drone/store/artifact"
+	"github.com/drone/drone/store/audit"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/delivery"
+	"github.com/drone/drone/store/environment"
 	"github.com/drone/drone/store/perm"
+	"github.com/drone/drone/store/quota"
+	"github.com/drone/drone/store/retention"
+	"github.com/drone/drone/store/retry"
 	"github.com/drone/drone/store/secret"
 	"github.com/drone/drone/store/secret/global"
+	"github.com/drone/drone/store/secret/usage"
+	"github.com/drone/drone/store/secret/version"
 	"github.com/drone/drone/store/step"
 	"github.com/drone/drone/store/template"
+	"github.com/drone/drone/store/test"
+	token2 "github.com/drone/drone/store/token"
+	"github.com/drone/drone/store/webhook"
 	"github.com/drone/drone/trigger"
 	cron2 "github.com/drone/drone/trigger/cron"
 )
@@ -55,14 +66,24 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	cronStore := cron.New(db)
 	repositoryStore := provideRepoStore(db)
 	buildStore := provideBuildStore(db)
-	corePubsub := pubsub.New()
+	broker, err := provideBroker(config2)
+	if err != nil {
+		return application{}, err
+	}
+	pubsub, err := providePubsub(broker)
+	if err != nil {
+		return application{}, err
+	}
 	stageStore := provideStageStore(db)
-	scheduler := provideScheduler(stageStore, config2)
+	quotaStore := quota.New(db)
+	scheduler := provideScheduler(stageStore, quotaStore, repositoryStore, buildStore, config2)
 	statusService := provideStatusService(client, renewer, config2)
 	stepStore := step.New(db)
 	system := provideSystem(config2)
-	webhookSender := provideWebhookPlugin(config2, system)
-	coreCanceler := canceler.New(buildStore, corePubsub, repositoryStore, scheduler, stageStore, statusService, stepStore, userStore, webhookSender)
+	webhookStore := webhook.New(db, encrypter)
+	webhookDeliveryStore := delivery.New(db)
+	webhookSender := provideWebhookPlugin(config2, system, webhookStore, webhookDeliveryStore)
+	coreCanceler := canceler.New(buildStore, pubsub, repositoryStore, scheduler, stageStore, statusService, stepStore, userStore, webhookSender)
 	fileService := provideContentService(client, renewer)
 	configService := provideConfigPlugin(client, fileService, config2)
 	templateStore := template.New(db)
@@ -71,23 +92,44 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	triggerer := trigger.New(coreCanceler, configService, convertService, commitService, statusService, buildStore, scheduler, repositoryStore, userStore, validateService, webhookSender)
 	cronScheduler := cron2.New(commitService, cronStore, repositoryStore, userStore, triggerer)
 	reaper := provideReaper(repositoryStore, buildStore, stageStore, coreCanceler, config2)
+	logStore, err := provideLogStore(db, config2)
+	if err != nil {
+		return application{}, err
+	}
+	artifactStore := artifact.New(db)
+	artifactBlobStore := provideArtifactBlobStore(db, config2)
+	retentionStore := retention.New(db)
+	retentionRetention := provideRetention(repositoryStore, buildStore, stageStore, logStore, artifactStore, artifactBlobStore, retentionStore, config2)
 	coreLicense := provideLicense(client, config2)
 	datadog := provideDatadog(userStore, repositoryStore, buildStore, system, coreLicense, config2)
-	logStore := provideLogStore(db, config2)
-	logStream := livelog.New()
+	identityService, err := provideIdentityService(config2)
+	if err != nil {
+		return application{}, err
+	}
+	logStream, err := provideLogStream(broker)
+	if err != nil {
+		return application{}, err
+	}
 	netrcService := provideNetrcService(client, renewer, config2)
+	testStore := test.New(db)
+	coreResetter := resetter.New(artifactStore, artifactBlobStore, logStore, stepStore, testStore)
+	retryStore := retry.New(db)
 	secretStore := secret.New(db, encrypter)
 	globalSecretStore := global.New(db, encrypter)
-	buildManager := manager.New(buildStore, configService, convertService, corePubsub, logStore, logStream, netrcService, repositoryStore, scheduler, secretStore, globalSecretStore, statusService, stageStore, stepStore, system, userStore, webhookSender)
+	secretUsageStore := usage.New(db)
+	buildManager := manager.New(artifactStore, artifactBlobStore, buildStore, configService, convertService, pubsub, identityService, logStore, logStream, netrcService, repositoryStore, coreResetter, retryStore, scheduler, secretStore, globalSecretStore, statusService, stageStore, stepStore, system, testStore, userStore, secretUsageStore, webhookSender)
 	secretService := provideSecretPlugin(config2)
 	registryService := provideRegistryPlugin(config2)
 	runner := provideRunner(buildManager, secretService, registryService, config2)
+	auditStore := audit.New(db)
+	environmentStore := environment.New(db)
 	hookService := provideHookService(client, renewer, config2)
 	licenseService := license.NewService(userStore, repositoryStore, buildStore, coreLicense)
 	organizationService := provideOrgService(client, renewer)
 	permStore := perm.New(db)
 	repositoryService := provideRepositoryService(client, renewer, config2)
-	session, err := provideSession(userStore, config2)
+	accessTokenStore := token2.New(db)
+	session, err := provideSession(userStore, accessTokenStore, config2)
 	if err != nil {
 		return application{}, err
 	}
@@ -95,20 +137,23 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	syncer := provideSyncer(repositoryService, repositoryStore, userStore, batcher, config2)
 	transferer := transfer.New(repositoryStore, permStore)
 	userService := user.New(client, renewer)
-	server := api.New(buildStore, commitService, cronStore, corePubsub, globalSecretStore, hookService, logStore, coreLicense, licenseService, organizationService, permStore, repositoryStore, repositoryService, scheduler, secretStore, stageStore, stepStore, statusService, session, logStream, syncer, system, templateStore, transferer, triggerer, userStore, userService, webhookSender)
+	secretVersionStore := version.New(db, encrypter)
+	server := api.New(artifactStore, auditStore, artifactBlobStore, buildStore, commitService, cronStore, webhookDeliveryStore, environmentStore, pubsub, globalSecretStore, hookService, logStore, coreLicense, licenseService, organizationService, permStore, quotaStore, repositoryStore, repositoryService, coreResetter, retentionStore, retryStore, scheduler, secretStore, stageStore, stepStore, statusService, session, logStream, syncer, system, templateStore, testStore, accessTokenStore, transferer, triggerer, secretUsageStore, userStore, userService, secretVersionStore, webhookSender, webhookStore)
 	admissionService := provideAdmissionPlugin(client, organizationService, userService, config2)
 	hookParser := parser.New(client)
 	coreLinker := linker.New(client)
 	middleware := provideLogin(config2)
 	options := provideServerOptions(config2)
-	webServer := web.New(admissionService, buildStore, client, hookParser, coreLicense, licenseService, coreLinker, middleware, repositoryStore, session, syncer, triggerer, userStore, userService, webhookSender, options, system)
+	webServer := web.New(admissionService, buildStore, client, hookParser, identityService, coreLicense, licenseService, coreLinker, middleware, repositoryStore, session, syncer, triggerer, userStore, userService, webhookSender, options, system)
 	mainRpcHandlerV1 := provideRPC(buildManager, config2)
 	mainRpcHandlerV2 := provideRPC2(buildManager, config2)
 	mainHealthzHandler := provideHealthz()
 	metricServer := provideMetric(session, config2)
 	mainPprofHandler := providePprof(config2)
-	mux := provideRouter(server, webServer, mainRpcHandlerV1, mainRpcHandlerV2, mainHealthzHandler, metricServer, mainPprofHandler)
+	mux := provideRouter(server, webServer, mainRpcHandlerV1, mainRpcHandlerV2, mainHealthzHandler, metricServer, mainPprofHandler, config2)
 	serverServer := provideServer(mux, config2)
-	mainApplication := newApplication(cronScheduler, reaper, datadog, runner, serverServer, userStore)
+	watchdog := provideWatchdog(repositoryStore, buildStore, stageStore, coreCanceler)
+	retrier := provideWebhookRetrier(config2, system, webhookStore, webhookDeliveryStore)
+	mainApplication := newApplication(cronScheduler, reaper, retentionRetention, datadog, runner, serverServer, userStore, watchdog, retrier)
 	return mainApplication, nil
 }
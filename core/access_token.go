@@ -0,0 +1,140 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Access token scopes.
+const (
+	ScopeRead         = "read"
+	ScopeBuildsWrite  = "builds:write"
+	ScopeSecretsWrite = "secrets:write"
+	ScopeAdmin        = "admin"
+)
+
+var (
+	errTokenNameInvalid  = errors.New("Invalid Token Name")
+	errTokenScopeInvalid = errors.New("Invalid Token Scope")
+	errTokenRepoInvalid  = errors.New("Invalid Token Repository")
+)
+
+type (
+	// AccessToken represents a named personal access token
+	// with a limited set of scopes.
+	AccessToken struct {
+		ID       int64    `json:"id"`
+		UserID   int64    `json:"user_id"`
+		Name     string   `json:"name"`
+		Scopes   []string `json:"scopes"`
+		Repo     string   `json:"repo,omitempty"`
+		Hash     string   `json:"-"`
+		Expires  int64    `json:"expires,omitempty"`
+		LastUsed int64    `json:"last_used,omitempty"`
+		Created  int64    `json:"created"`
+	}
+
+	// AccessTokenStore persists personal access tokens.
+	AccessTokenStore interface {
+		// List returns a list of tokens owned by the user.
+		List(context.Context, int64) ([]*AccessToken, error)
+
+		// Find returns a token from the datastore.
+		Find(context.Context, int64) (*AccessToken, error)
+
+		// FindHash returns a token from the datastore by
+		// the hash of the token value.
+		FindHash(context.Context, string) (*AccessToken, error)
+
+		// Create persists a new token to the datastore.
+		Create(context.Context, *AccessToken) error
+
+		// Update persists an updated token to the datastore.
+		// The last used timestamp is not updated.
+		Update(context.Context, *AccessToken) error
+
+		// Touch persists the last used timestamp of the token
+		// to the datastore.
+		Touch(context.Context, *AccessToken) error
+
+		// Delete deletes a token from the datastore.
+		Delete(context.Context, *AccessToken) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (t *AccessToken) Validate() error {
+	switch {
+	case len(t.Name) == 0, len(t.Name) > 250:
+		return errTokenNameInvalid
+	case len(t.Scopes) == 0:
+		return errTokenScopeInvalid
+	case strings.Count(t.Repo, "/") > 1,
+		strings.HasPrefix(t.Repo, "/"),
+		strings.HasSuffix(t.Repo, "/"):
+		return errTokenRepoInvalid
+	}
+	for _, scope := range t.Scopes {
+		switch scope {
+		case ScopeRead, ScopeBuildsWrite, ScopeSecretsWrite, ScopeAdmin:
+		default:
+			return errTokenScopeInvalid
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the token is expired.
+func (t *AccessToken) Expired(now time.Time) bool {
+	return t.Expires != 0 && t.Expires <= now.Unix()
+}
+
+// Allows returns true if the token grants the scope. The
+// admin scope grants all scopes, and every scope grants
+// read access.
+func (t *AccessToken) Allows(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == ScopeAdmin || s == scope {
+			return true
+		}
+	}
+	return scope == ScopeRead
+}
+
+// Match returns true if the token can access the repository.
+// A token restricted to a namespace can access all of the
+// repositories in the namespace.
+func (t *AccessToken) Match(namespace, name string) bool {
+	if t.Repo == "" {
+		return true
+	}
+	if strings.Contains(t.Repo, "/") {
+		return strings.EqualFold(t.Repo, namespace+"/"+name)
+	}
+	return strings.EqualFold(t.Repo, namespace)
+}
+
+// HashToken returns the hash of the personal access token
+// value. Only the hash of the token value is persisted.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccessTokenValidate(t *testing.T) {
+	tests := []struct {
+		token *AccessToken
+		error error
+	}{
+		{
+			token: &AccessToken{Name: "ci", Scopes: []string{ScopeRead}},
+			error: nil,
+		},
+		{
+			token: &AccessToken{Name: "ci", Scopes: []string{ScopeBuildsWrite}, Repo: "octocat/hello-world"},
+			error: nil,
+		},
+		{
+			token: &AccessToken{Name: "", Scopes: []string{ScopeRead}},
+			error: errTokenNameInvalid,
+		},
+		{
+			token: &AccessToken{Name: "ci"},
+			error: errTokenScopeInvalid,
+		},
+		{
+			token: &AccessToken{Name: "ci", Scopes: []string{"repo:write"}},
+			error: errTokenScopeInvalid,
+		},
+		{
+			token: &AccessToken{Name: "ci", Scopes: []string{ScopeRead}, Repo: "octocat/hello/world"},
+			error: errTokenRepoInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.token.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestAccessTokenAllows(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{[]string{ScopeRead}, ScopeRead, true},
+		{[]string{ScopeRead}, ScopeBuildsWrite, false},
+		{[]string{ScopeBuildsWrite}, ScopeRead, true},
+		{[]string{ScopeBuildsWrite}, ScopeBuildsWrite, true},
+		{[]string{ScopeBuildsWrite}, ScopeSecretsWrite, false},
+		{[]string{ScopeBuildsWrite}, ScopeAdmin, false},
+		{[]string{ScopeAdmin}, ScopeSecretsWrite, true},
+		{[]string{ScopeAdmin}, ScopeAdmin, true},
+	}
+	for i, test := range tests {
+		token := &AccessToken{Scopes: test.scopes}
+		if got, want := token.Allows(test.scope), test.want; got != want {
+			t.Errorf("Want allows %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestAccessTokenMatch(t *testing.T) {
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{"", true},
+		{"octocat", true},
+		{"Octocat", true},
+		{"octocat/hello-world", true},
+		{"octocat/spoon-knife", false},
+		{"spaceghost", false},
+	}
+	for i, test := range tests {
+		token := &AccessToken{Repo: test.repo}
+		if got, want := token.Match("octocat", "hello-world"), test.want; got != want {
+			t.Errorf("Want match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestAccessTokenExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	if (&AccessToken{}).Expired(now) {
+		t.Errorf("Expect token without expiry is not expired")
+	}
+	if (&AccessToken{Expires: 2000}).Expired(now) {
+		t.Errorf("Expect token is not expired")
+	}
+	if !(&AccessToken{Expires: 1000}).Expired(now) {
+		t.Errorf("Expect token is expired")
+	}
+}
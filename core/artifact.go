@@ -0,0 +1,110 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var errArtifactNameInvalid = errors.New("Invalid Artifact Name")
+
+type (
+	// Artifact represents a file uploaded by a pipeline step,
+	// such as a binary, test report or coverage report.
+	Artifact struct {
+		ID      int64  `json:"id"`
+		RepoID  int64  `json:"repo_id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Name    string `json:"name"`
+		Size    int64  `json:"size"`
+		Created int64  `json:"created"`
+		Updated int64  `json:"updated"`
+	}
+
+	// ArtifactStore persists artifact metadata to storage.
+	ArtifactStore interface {
+		// List returns a list of artifacts for the stage.
+		List(context.Context, int64) ([]*Artifact, error)
+
+		// ListBuild returns a list of artifacts for the build.
+		ListBuild(context.Context, int64) ([]*Artifact, error)
+
+		// ListRepo returns a list of artifacts for the repository.
+		ListRepo(context.Context, int64) ([]*Artifact, error)
+
+		// ListPurge returns a list of artifacts for the repository
+		// builds with a build number lower than the provided number.
+		ListPurge(context.Context, int64, int64) ([]*Artifact, error)
+
+		// Find returns an artifact from the datastore.
+		Find(context.Context, int64) (*Artifact, error)
+
+		// FindName returns an artifact from the datastore
+		// by stage id and artifact name.
+		FindName(context.Context, int64, string) (*Artifact, error)
+
+		// Create persists a new artifact to the datastore.
+		Create(context.Context, *Artifact) error
+
+		// Update persists an updated artifact to the datastore.
+		Update(context.Context, *Artifact) error
+
+		// Delete deletes an artifact from the datastore.
+		Delete(context.Context, *Artifact) error
+	}
+
+	// ArtifactBlobStore persists artifact contents to storage.
+	ArtifactBlobStore interface {
+		// Find returns the artifact contents from the datastore.
+		Find(ctx context.Context, artifact int64) (io.ReadCloser, error)
+
+		// Create copies the artifact contents from Reader r to
+		// the datastore.
+		Create(ctx context.Context, artifact int64, r io.Reader) error
+
+		// Update copies the artifact contents from Reader r to
+		// the datastore.
+		Update(ctx context.Context, artifact int64, r io.Reader) error
+
+		// Delete purges the artifact contents from the datastore.
+		Delete(ctx context.Context, artifact int64) error
+	}
+)
+
+// Validate validates the artifact fields. The artifact name
+// must be a relative path that does not traverse outside the
+// artifact directory.
+func (a *Artifact) Validate() error {
+	switch {
+	case a.Name == "":
+		return errArtifactNameInvalid
+	case len(a.Name) > 500:
+		return errArtifactNameInvalid
+	case strings.HasPrefix(a.Name, "/"):
+		return errArtifactNameInvalid
+	case path.Clean(a.Name) != a.Name:
+		return errArtifactNameInvalid
+	case a.Name == ".." || strings.HasPrefix(a.Name, "../"):
+		return errArtifactNameInvalid
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestArtifactValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"coverage.out", nil},
+		{"dist/linux/amd64/drone", nil},
+		{"", errArtifactNameInvalid},
+		{"/etc/passwd", errArtifactNameInvalid},
+		{"../secret", errArtifactNameInvalid},
+		{"..", errArtifactNameInvalid},
+		{"dist/../../secret", errArtifactNameInvalid},
+		{"dist//drone", errArtifactNameInvalid},
+		{"./drone", errArtifactNameInvalid},
+	}
+	for _, test := range tests {
+		artifact := &Artifact{Name: test.name}
+		if got, want := artifact.Validate(), test.err; got != want {
+			t.Errorf("Want error %v for name %q, got %v", want, test.name, got)
+		}
+	}
+}
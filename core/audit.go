@@ -0,0 +1,83 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Audit action constants.
+const (
+	AuditRepoEnable        = "repo.enable"
+	AuditRepoDisable       = "repo.disable"
+	AuditRepoDelete        = "repo.delete"
+	AuditRepoUpdate        = "repo.update"
+	AuditRepoChown         = "repo.chown"
+	AuditSecretCreate      = "secret.create"
+	AuditSecretUpdate      = "secret.update"
+	AuditSecretDelete      = "secret.delete"
+	AuditSecretRollback    = "secret.rollback"
+	AuditStageApprove      = "stage.approve"
+	AuditStageDecline      = "stage.decline"
+	AuditBuildPromote      = "build.promote"
+	AuditBuildRollback     = "build.rollback"
+	AuditUserCreate        = "user.create"
+	AuditUserUpdate        = "user.update"
+	AuditUserDelete        = "user.delete"
+	AuditWebhookCreate     = "webhook.create"
+	AuditWebhookUpdate     = "webhook.update"
+	AuditWebhookDelete     = "webhook.delete"
+	AuditTokenCreate       = "token.create"
+	AuditTokenDelete       = "token.delete"
+	AuditEnvironmentCreate = "environment.create"
+	AuditEnvironmentUpdate = "environment.update"
+	AuditEnvironmentDelete = "environment.delete"
+)
+
+type (
+	// AuditEvent represents an administrative or security
+	// relevant action performed by a user.
+	AuditEvent struct {
+		ID      int64  `json:"id"`
+		Actor   string `json:"actor"`
+		Action  string `json:"action"`
+		Target  string `json:"target"`
+		Diff    string `json:"diff,omitempty"`
+		IP      string `json:"ip,omitempty"`
+		Created int64  `json:"created"`
+	}
+
+	// AuditFilter provides filters when listing audit
+	// events. Empty values are not used to filter results.
+	AuditFilter struct {
+		Actor  string
+		Action string
+		Target string
+		Since  int64
+		Until  int64
+		Limit  int
+		Offset int
+	}
+
+	// AuditStore persists audit events to storage. The store
+	// is append-only; audit events cannot be updated or
+	// deleted.
+	AuditStore interface {
+		// List returns a list of audit events, newest first,
+		// matching the filter from the datastore.
+		List(context.Context, *AuditFilter) ([]*AuditEvent, error)
+
+		// Create persists a new audit event to the datastore.
+		Create(context.Context, *AuditEvent) error
+	}
+)
@@ -47,6 +47,7 @@ type Build struct {
 	Deploy       string            `db:"build_deploy"         json:"deploy_to,omitempty"`
 	DeployID     int64             `db:"build_deploy_id"      json:"deploy_id,omitempty"`
 	Debug        bool              `db:"build_debug"          json:"debug,omitempty"`
+	Timeout      int64             `db:"build_timeout"        json:"timeout,omitempty"`
 	Started      int64             `db:"build_started"        json:"started"`
 	Finished     int64             `db:"build_finished"       json:"finished"`
 	Created      int64             `db:"build_created"        json:"created"`
@@ -72,6 +73,15 @@ type BuildStore interface {
 	// ListRef returns a list of builds from the datastore by ref.
 	ListRef(context.Context, int64, string, int, int) ([]*Build, error)
 
+	// ListBefore returns a list of builds from the datastore by
+	// repository id where the build number is less than n,
+	// ordered by build number in descending order.
+	ListBefore(ctx context.Context, repo, n int64, limit int) ([]*Build, error)
+
+	// ListDeploys returns a list of builds from the datastore
+	// by target deployment environment.
+	ListDeploys(context.Context, int64, string, int, int) ([]*Build, error)
+
 	// LatestBranches returns the latest builds from the
 	// datastore by branch.
 	LatestBranches(context.Context, int64) ([]*Build, error)
@@ -84,6 +94,11 @@ type BuildStore interface {
 	// datastore by deployment target.
 	LatestDeploys(context.Context, int64) ([]*Build, error)
 
+	// LatestDeploysNamespace returns the latest builds from
+	// the datastore by deployment target, for every repository
+	// in the namespace.
+	LatestDeploysNamespace(context.Context, string) ([]*Build, error)
+
 	// Pending returns a list of pending builds from the
 	// datastore by repository id (DEPRECATED).
 	Pending(context.Context) ([]*Build, error)
@@ -38,10 +38,10 @@ type (
 
 	// Change represents a file change in a commit.
 	Change struct {
-		Path    string
-		Added   bool
-		Renamed bool
-		Deleted bool
+		Path    string `json:"path"`
+		Added   bool   `json:"added"`
+		Renamed bool   `json:"renamed"`
+		Deleted bool   `json:"deleted"`
 	}
 
 	// CommitService provides access to the commit history from
@@ -55,5 +55,9 @@ type (
 
 		// ListChanges returns the files change by sha or reference.
 		ListChanges(ctx context.Context, user *User, repo, sha, ref string) ([]*Change, error)
+
+		// CompareChanges returns the files changed between the
+		// source and target commit.
+		CompareChanges(ctx context.Context, user *User, repo, source, target string) ([]*Change, error)
 	}
 )
@@ -0,0 +1,80 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Webhook delivery status types.
+const (
+	DeliveryPending = "pending"
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
+type (
+	// WebhookDelivery records an outgoing webhook delivery.
+	// A pending delivery is retried until it succeeds or
+	// the maximum number of attempts is reached. The WebhookID
+	// is zero for deliveries to the global endpoints.
+	WebhookDelivery struct {
+		ID        int64  `json:"id"`
+		WebhookID int64  `json:"webhook_id,omitempty"`
+		Endpoint  string `json:"endpoint"`
+		Event     string `json:"event"`
+		Action    string `json:"action"`
+		Status    string `json:"status"`
+		Attempts  int    `json:"attempts"`
+		Code      int    `json:"response_code"`
+		Error     string `json:"error,omitempty"`
+		Latency   int64  `json:"latency"`
+		Payload   string `json:"payload"`
+		NextRetry int64  `json:"next_retry,omitempty"`
+		Created   int64  `json:"created"`
+		Updated   int64  `json:"updated"`
+	}
+
+	// WebhookDeliveryStore persists webhook deliveries.
+	WebhookDeliveryStore interface {
+		// List returns the most recent deliveries.
+		List(ctx context.Context, limit int) ([]*WebhookDelivery, error)
+
+		// ListWebhook returns the most recent deliveries to
+		// the repository webhook.
+		ListWebhook(ctx context.Context, webhook int64, limit int) ([]*WebhookDelivery, error)
+
+		// ListPending returns pending deliveries that are due
+		// for retry at the given unix timestamp.
+		ListPending(ctx context.Context, now int64) ([]*WebhookDelivery, error)
+
+		// Find returns a delivery from the datastore.
+		Find(ctx context.Context, id int64) (*WebhookDelivery, error)
+
+		// Create persists a new delivery to the datastore.
+		Create(ctx context.Context, delivery *WebhookDelivery) error
+
+		// Update persists an updated delivery to the datastore.
+		Update(ctx context.Context, delivery *WebhookDelivery) error
+
+		// Claim claims the pending delivery until the given unix
+		// timestamp, so that it is not retried concurrently by
+		// another server. An error is returned if the delivery
+		// was already claimed.
+		Claim(ctx context.Context, delivery *WebhookDelivery, until int64) error
+
+		// Purge deletes deliveries created before the given
+		// unix timestamp.
+		Purge(ctx context.Context, before int64) error
+	}
+)
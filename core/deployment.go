@@ -0,0 +1,69 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+type (
+	// Deployment represents a build deployed to a target
+	// environment. It is a read-only view of the build
+	// history used to report what is deployed where.
+	Deployment struct {
+		Repo     string `json:"repo,omitempty"`
+		Target   string `json:"target"`
+		Number   int64  `json:"number"`
+		Parent   int64  `json:"parent"`
+		Event    string `json:"event"`
+		Commit   string `json:"commit"`
+		Ref      string `json:"ref"`
+		Branch   string `json:"branch"`
+		Link     string `json:"link"`
+		Trigger  string `json:"trigger"`
+		Status   string `json:"status"`
+		Created  int64  `json:"created"`
+		Started  int64  `json:"started"`
+		Finished int64  `json:"finished"`
+		Duration int64  `json:"duration"`
+	}
+
+	// DeploymentDiff represents the changes between two
+	// deployments to the same target environment.
+	DeploymentDiff struct {
+		From    *Deployment `json:"from"`
+		To      *Deployment `json:"to"`
+		Changes []*Change   `json:"changes"`
+	}
+)
+
+// NewDeployment returns the deployment view of the build.
+func NewDeployment(build *Build) *Deployment {
+	deployment := &Deployment{
+		Target:   build.Deploy,
+		Number:   build.Number,
+		Parent:   build.Parent,
+		Event:    build.Event,
+		Commit:   build.After,
+		Ref:      build.Ref,
+		Branch:   build.Target,
+		Link:     build.Link,
+		Trigger:  build.Trigger,
+		Status:   build.Status,
+		Created:  build.Created,
+		Started:  build.Started,
+		Finished: build.Finished,
+	}
+	if build.Started != 0 && build.Finished != 0 {
+		deployment.Duration = build.Finished - build.Started
+	}
+	return deployment
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewDeployment(t *testing.T) {
+	build := &Build{
+		Number:   42,
+		Parent:   41,
+		Event:    EventPromote,
+		Deploy:   "production",
+		After:    "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		Ref:      "refs/heads/master",
+		Target:   "master",
+		Trigger:  "octocat",
+		Status:   StatusPassing,
+		Created:  1000,
+		Started:  1010,
+		Finished: 1070,
+	}
+	want := &Deployment{
+		Target:   "production",
+		Number:   42,
+		Parent:   41,
+		Event:    EventPromote,
+		Commit:   "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		Ref:      "refs/heads/master",
+		Branch:   "master",
+		Trigger:  "octocat",
+		Status:   StatusPassing,
+		Created:  1000,
+		Started:  1010,
+		Finished: 1070,
+		Duration: 60,
+	}
+	if diff := cmp.Diff(NewDeployment(build), want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	build.Finished = 0
+	if got := NewDeployment(build).Duration; got != 0 {
+		t.Errorf("Want zero duration for running deployment, got %d", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	errEnvironmentNameInvalid   = errors.New("Invalid Environment Name")
+	errEnvironmentBranchInvalid = errors.New("Invalid Environment Branch")
+	errEnvironmentWaitInvalid   = errors.New("Invalid Environment Wait Timer")
+)
+
+type (
+	// Environment represents a repository deployment target
+	// with protection rules. Deployments to a protected
+	// environment are blocked until approved by one of the
+	// listed approvers, and cannot be approved until the
+	// wait timer has elapsed. Only one deployment to an
+	// environment can be in progress at a time.
+	Environment struct {
+		ID        int64    `json:"id"`
+		RepoID    int64    `json:"repo_id"`
+		Name      string   `json:"name"`
+		Branches  []string `json:"branches,omitempty"`
+		Approvers []string `json:"approvers,omitempty"`
+		Wait      int64    `json:"wait,omitempty"`
+		Created   int64    `json:"created"`
+		Updated   int64    `json:"updated"`
+	}
+
+	// EnvironmentStore persists repository environments.
+	EnvironmentStore interface {
+		// List returns a list of environments for the repository.
+		List(context.Context, int64) ([]*Environment, error)
+
+		// Find returns an environment from the datastore.
+		Find(context.Context, int64) (*Environment, error)
+
+		// FindName returns a named environment from the
+		// datastore for the repository.
+		FindName(context.Context, int64, string) (*Environment, error)
+
+		// Create persists a new environment to the datastore.
+		Create(context.Context, *Environment) error
+
+		// Update persists an updated environment to the datastore.
+		Update(context.Context, *Environment) error
+
+		// Delete deletes an environment from the datastore.
+		Delete(context.Context, *Environment) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (e *Environment) Validate() error {
+	switch {
+	case len(e.Name) == 0, len(e.Name) > 250:
+		return errEnvironmentNameInvalid
+	case slugRE.MatchString(e.Name):
+		return errEnvironmentNameInvalid
+	case e.Wait < 0:
+		return errEnvironmentWaitInvalid
+	}
+	for _, pattern := range e.Branches {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return errEnvironmentBranchInvalid
+		}
+	}
+	return nil
+}
+
+// Protected returns true if deployments to the environment
+// require approval.
+func (e *Environment) Protected() bool {
+	return len(e.Approvers) != 0 || e.Wait != 0
+}
+
+// MatchBranch returns true if the branch can be deployed to
+// the environment. An empty branch list allows all branches.
+func (e *Environment) MatchBranch(branch string) bool {
+	if len(e.Branches) == 0 {
+		return true
+	}
+	for _, pattern := range e.Branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsApprover returns true if the user is listed as an
+// approver for the environment.
+func (e *Environment) IsApprover(login string) bool {
+	for _, approver := range e.Approvers {
+		if strings.EqualFold(approver, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// Waiting returns true if the wait timer for a deployment
+// created at the given unix timestamp has not elapsed.
+func (e *Environment) Waiting(created int64, now time.Time) bool {
+	return e.Wait != 0 && created+e.Wait > now.Unix()
+}
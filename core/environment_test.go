@@ -0,0 +1,99 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvironmentValidate(t *testing.T) {
+	tests := []struct {
+		env   *Environment
+		error error
+	}{
+		{
+			env:   &Environment{Name: "production", Branches: []string{"master", "release/*"}},
+			error: nil,
+		},
+		{
+			env:   &Environment{Name: ""},
+			error: errEnvironmentNameInvalid,
+		},
+		{
+			env:   &Environment{Name: "prod/eu"},
+			error: errEnvironmentNameInvalid,
+		},
+		{
+			env:   &Environment{Name: "production", Wait: -1},
+			error: errEnvironmentWaitInvalid,
+		},
+		{
+			env:   &Environment{Name: "production", Branches: []string{"["}},
+			error: errEnvironmentBranchInvalid,
+		},
+		{
+			env:   &Environment{Name: "production", Branches: []string{""}},
+			error: errEnvironmentBranchInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.env.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestEnvironmentMatchBranch(t *testing.T) {
+	env := &Environment{}
+	if !env.MatchBranch("feature") {
+		t.Errorf("Expect empty branch list matches all branches")
+	}
+	env.Branches = []string{"master", "release/*"}
+	if !env.MatchBranch("master") {
+		t.Errorf("Expect branch master matches")
+	}
+	if !env.MatchBranch("release/1.0") {
+		t.Errorf("Expect branch release/1.0 matches")
+	}
+	if env.MatchBranch("feature") {
+		t.Errorf("Expect branch feature does not match")
+	}
+}
+
+func TestEnvironmentProtected(t *testing.T) {
+	env := &Environment{}
+	if env.Protected() {
+		t.Errorf("Expect environment without rules is not protected")
+	}
+	env.Approvers = []string{"octocat"}
+	if !env.Protected() {
+		t.Errorf("Expect environment with approvers is protected")
+	}
+	if !env.IsApprover("OctoCat") {
+		t.Errorf("Expect approver match is case insensitive")
+	}
+	if env.IsApprover("spaceghost") {
+		t.Errorf("Expect spaceghost is not an approver")
+	}
+}
+
+func TestEnvironmentWaiting(t *testing.T) {
+	now := time.Unix(1000, 0)
+	env := &Environment{}
+	if env.Waiting(now.Unix(), now) {
+		t.Errorf("Expect environment without wait timer is not waiting")
+	}
+	env.Wait = 60
+	if !env.Waiting(now.Unix()-30, now) {
+		t.Errorf("Expect deployment is waiting before the timer elapses")
+	}
+	if env.Waiting(now.Unix()-60, now) {
+		t.Errorf("Expect deployment is not waiting after the timer elapses")
+	}
+}
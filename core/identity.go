@@ -0,0 +1,55 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// IdentityArgs provides the build details used to mint
+	// a pipeline identity token.
+	IdentityArgs struct {
+		Repo  *Repository
+		Build *Build
+		Stage *Stage
+	}
+
+	// JSONWebKey represents a public key used to verify
+	// pipeline identity tokens.
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+
+	// IdentityService mints short-lived OpenID Connect
+	// identity tokens for pipelines, allowing third-party
+	// services to trust a pipeline without stored secrets.
+	IdentityService interface {
+		// Issuer returns the token issuer.
+		Issuer() string
+
+		// Token returns a signed identity token for the
+		// pipeline. An empty token is returned if identity
+		// tokens are not enabled.
+		Token(context.Context, *IdentityArgs) (string, error)
+
+		// Keys returns the public keys used to verify
+		// identity tokens.
+		Keys(context.Context) ([]*JSONWebKey, error)
+	}
+)
@@ -16,9 +16,13 @@ package core
 
 import (
 	"context"
+	"errors"
 	"io"
+	"regexp"
 )
 
+var errLogQueryInvalid = errors.New("Invalid Log Query")
+
 // Line represents a line in the logs.
 type Line struct {
 	Number    int    `json:"pos"`
@@ -26,11 +30,51 @@ type Line struct {
 	Timestamp int64  `json:"time"`
 }
 
+// LogQuery defines the lines returned by a log query. The
+// lines are selected by search expression, and then limited
+// by range or tail.
+type LogQuery struct {
+	// Offset is the index of the first line returned.
+	Offset int
+
+	// Limit is the maximum number of lines returned. If zero,
+	// all lines are returned.
+	Limit int
+
+	// Tail returns the last N lines. If non-zero, Offset
+	// and Limit are ignored.
+	Tail int
+
+	// Search returns lines that contain the search string.
+	Search string
+
+	// Regexp indicates the search string is a regular
+	// expression.
+	Regexp bool
+}
+
+// Validate validates the query fields.
+func (q *LogQuery) Validate() error {
+	if q.Offset < 0 || q.Limit < 0 || q.Tail < 0 {
+		return errLogQueryInvalid
+	}
+	if q.Regexp {
+		if _, err := regexp.Compile(q.Search); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // LogStore persists build output to storage.
 type LogStore interface {
 	// Find returns a log stream from the datastore.
 	Find(ctx context.Context, stage int64) (io.ReadCloser, error)
 
+	// Query returns the log lines that match the query
+	// from the datastore.
+	Query(ctx context.Context, step int64, query *LogQuery) ([]*Line, error)
+
 	// Create writes copies the log stream from Reader r to the datastore.
 	Create(ctx context.Context, stage int64, r io.Reader) error
 
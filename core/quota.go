@@ -0,0 +1,87 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// Quota kinds.
+const (
+	QuotaGlobal    = "global"
+	QuotaNamespace = "namespace"
+	QuotaUser      = "user"
+)
+
+var (
+	errQuotaKindInvalid  = errors.New("Invalid Quota Kind")
+	errQuotaNameInvalid  = errors.New("Invalid Quota Name")
+	errQuotaLimitInvalid = errors.New("Invalid Quota Limit")
+)
+
+type (
+	// Quota limits the number of stages that can execute
+	// concurrently across the system, for a namespace, or
+	// for builds triggered by a user.
+	Quota struct {
+		ID      int64  `json:"id"`
+		Kind    string `json:"kind"`
+		Name    string `json:"name,omitempty"`
+		Limit   int    `json:"limit"`
+		Created int64  `json:"created"`
+		Updated int64  `json:"updated"`
+	}
+
+	// QuotaStore persists quota information to storage.
+	QuotaStore interface {
+		// List returns a quota list from the datastore.
+		List(context.Context) ([]*Quota, error)
+
+		// Find returns a quota from the datastore.
+		Find(context.Context, int64) (*Quota, error)
+
+		// FindName returns a quota from the datastore by
+		// kind and name.
+		FindName(ctx context.Context, kind, name string) (*Quota, error)
+
+		// Create persists a new quota to the datastore.
+		Create(context.Context, *Quota) error
+
+		// Update persists an updated quota to the datastore.
+		Update(context.Context, *Quota) error
+
+		// Delete deletes a quota from the datastore.
+		Delete(context.Context, *Quota) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (q *Quota) Validate() error {
+	switch {
+	case q.Kind != QuotaGlobal &&
+		q.Kind != QuotaNamespace &&
+		q.Kind != QuotaUser:
+		return errQuotaKindInvalid
+	case q.Kind == QuotaGlobal && q.Name != "":
+		return errQuotaNameInvalid
+	case q.Kind != QuotaGlobal && q.Name == "":
+		return errQuotaNameInvalid
+	case q.Limit < 1:
+		return errQuotaLimitInvalid
+	default:
+		return nil
+	}
+}
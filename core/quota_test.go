@@ -0,0 +1,59 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "testing"
+
+func TestQuotaValidate(t *testing.T) {
+	tests := []struct {
+		quota *Quota
+		err   error
+	}{
+		{
+			quota: &Quota{Kind: QuotaGlobal, Limit: 10},
+			err:   nil,
+		},
+		{
+			quota: &Quota{Kind: QuotaNamespace, Name: "octocat", Limit: 2},
+			err:   nil,
+		},
+		{
+			quota: &Quota{Kind: QuotaUser, Name: "octocat", Limit: 1},
+			err:   nil,
+		},
+		{
+			quota: &Quota{Kind: "repository", Name: "octocat", Limit: 1},
+			err:   errQuotaKindInvalid,
+		},
+		{
+			quota: &Quota{Kind: QuotaGlobal, Name: "octocat", Limit: 1},
+			err:   errQuotaNameInvalid,
+		},
+		{
+			quota: &Quota{Kind: QuotaNamespace, Limit: 1},
+			err:   errQuotaNameInvalid,
+		},
+		{
+			quota: &Quota{Kind: QuotaNamespace, Name: "octocat"},
+			err:   errQuotaLimitInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.quota.Validate(), test.err
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
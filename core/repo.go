@@ -55,6 +55,7 @@ type (
 		CancelPush  bool   `json:"auto_cancel_pushes"`
 		Timeout     int64  `json:"timeout"`
 		Throttle    int64  `json:"throttle,omitempty"`
+		Priority    int64  `json:"priority,omitempty"`
 		Counter     int64  `json:"counter"`
 		Synced      int64  `json:"synced"`
 		Created     int64  `json:"created"`
@@ -0,0 +1,26 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Resetter resets a stage so that it can be executed again.
+type Resetter interface {
+	// Reset purges the steps of the provided stage, including
+	// the step logs, artifacts and test results, and clears the
+	// stage execution state. The caller is responsible for
+	// setting the stage status and persisting the stage.
+	Reset(context.Context, *Stage) error
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// Retention policy kinds.
+const (
+	RetentionGlobal     = "global"
+	RetentionNamespace  = "namespace"
+	RetentionRepository = "repository"
+)
+
+var (
+	errRetentionKindInvalid  = errors.New("Invalid Retention Kind")
+	errRetentionNameInvalid  = errors.New("Invalid Retention Name")
+	errRetentionLimitInvalid = errors.New("Invalid Retention Limit")
+)
+
+type (
+	// Retention defines how long builds and logs are kept
+	// before they are automatically purged. A repository
+	// policy overrides a namespace policy, which overrides
+	// the global policy. A zero value limit is unlimited.
+	Retention struct {
+		ID      int64  `json:"id"`
+		Kind    string `json:"kind"`
+		Name    string `json:"name,omitempty"`
+		Builds  int    `json:"keep_builds"`
+		Days    int    `json:"keep_days"`
+		LogDays int    `json:"keep_log_days"`
+		Created int64  `json:"created"`
+		Updated int64  `json:"updated"`
+	}
+
+	// RetentionStore persists retention policies to storage.
+	RetentionStore interface {
+		// List returns a retention policy list from the datastore.
+		List(context.Context) ([]*Retention, error)
+
+		// Find returns a retention policy from the datastore.
+		Find(context.Context, int64) (*Retention, error)
+
+		// FindName returns a retention policy from the
+		// datastore by kind and name.
+		FindName(ctx context.Context, kind, name string) (*Retention, error)
+
+		// Create persists a new retention policy to the datastore.
+		Create(context.Context, *Retention) error
+
+		// Update persists an updated retention policy to the
+		// datastore.
+		Update(context.Context, *Retention) error
+
+		// Delete deletes a retention policy from the datastore.
+		Delete(context.Context, *Retention) error
+	}
+)
+
+// IsZero returns true if the policy does not limit builds
+// or logs.
+func (r *Retention) IsZero() bool {
+	return r.Builds == 0 && r.Days == 0 && r.LogDays == 0
+}
+
+// Validate validates the required fields and formats.
+func (r *Retention) Validate() error {
+	switch {
+	case r.Kind != RetentionGlobal &&
+		r.Kind != RetentionNamespace &&
+		r.Kind != RetentionRepository:
+		return errRetentionKindInvalid
+	case r.Kind == RetentionGlobal && r.Name != "":
+		return errRetentionNameInvalid
+	case r.Kind != RetentionGlobal && r.Name == "":
+		return errRetentionNameInvalid
+	case r.Builds < 0 || r.Days < 0 || r.LogDays < 0:
+		return errRetentionLimitInvalid
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "testing"
+
+func TestRetentionValidate(t *testing.T) {
+	tests := []struct {
+		policy *Retention
+		err    error
+	}{
+		{
+			policy: &Retention{Kind: RetentionGlobal, Builds: 100},
+			err:    nil,
+		},
+		{
+			policy: &Retention{Kind: RetentionNamespace, Name: "octocat", Days: 30},
+			err:    nil,
+		},
+		{
+			policy: &Retention{Kind: RetentionRepository, Name: "octocat/hello-world", LogDays: 7},
+			err:    nil,
+		},
+		{
+			policy: &Retention{Kind: "user", Name: "octocat"},
+			err:    errRetentionKindInvalid,
+		},
+		{
+			policy: &Retention{Kind: RetentionGlobal, Name: "octocat"},
+			err:    errRetentionNameInvalid,
+		},
+		{
+			policy: &Retention{Kind: RetentionRepository},
+			err:    errRetentionNameInvalid,
+		},
+		{
+			policy: &Retention{Kind: RetentionGlobal, LogDays: -1},
+			err:    errRetentionLimitInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.policy.Validate(), test.err
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
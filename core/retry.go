@@ -0,0 +1,133 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+)
+
+// RetryLimit defines the maximum number of attempts that
+// can be configured by a pipeline retry policy.
+const RetryLimit = 5
+
+type (
+	// RetryPolicy defines when a failed stage is automatically
+	// re-scheduled. A stage is retried until it passes or the
+	// maximum number of attempts is reached. If exit codes or
+	// error strings are provided, the stage is only retried if
+	// a failed step matches.
+	RetryPolicy struct {
+		Attempts  int      `json:"attempts"`
+		ExitCodes []int    `json:"exit_codes,omitempty"`
+		Errors    []string `json:"errors,omitempty"`
+	}
+
+	// Retry represents a failed step attempt that was
+	// automatically retried.
+	Retry struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id"`
+		BuildID  int64  `json:"build_id"`
+		StageID  int64  `json:"stage_id"`
+		Stage    string `json:"stage"`
+		Step     string `json:"step"`
+		Attempt  int    `json:"attempt"`
+		ExitCode int    `json:"exit_code"`
+		Error    string `json:"error,omitempty"`
+		Passed   bool   `json:"passed"`
+		Created  int64  `json:"created"`
+	}
+
+	// FlakyStep summarizes the retry history of a step that
+	// failed and subsequently passed on retry.
+	FlakyStep struct {
+		Stage   string `json:"stage"`
+		Step    string `json:"step"`
+		Retries int64  `json:"retries"`
+		Passed  int64  `json:"passed"`
+		Last    int64  `json:"last"`
+	}
+
+	// RetryStore persists retried steps to storage.
+	RetryStore interface {
+		// List returns a list of retried steps for the stage.
+		List(context.Context, int64) ([]*Retry, error)
+
+		// ListFlaky returns a list of steps in the repository
+		// that passed after being retried.
+		ListFlaky(context.Context, int64) ([]*FlakyStep, error)
+
+		// Create persists a new retried step to the datastore.
+		Create(context.Context, *Retry) error
+
+		// Pass marks the retried step in the stage as passed.
+		Pass(context.Context, int64, string) error
+	}
+)
+
+// Match returns true if the failed stage should be retried
+// according to the retry policy.
+func (p *RetryPolicy) Match(stage *Stage) bool {
+	if p == nil {
+		return false
+	}
+	if stage.Status != StatusFailing && stage.Status != StatusError {
+		return false
+	}
+	attempt := stage.Attempt
+	if attempt == 0 {
+		attempt = 1
+	}
+	if attempt >= p.Attempts {
+		return false
+	}
+	if len(p.ExitCodes) == 0 && len(p.Errors) == 0 {
+		return true
+	}
+	if p.matchError(stage.Error) {
+		return true
+	}
+	for _, step := range stage.Steps {
+		if step.ErrIgnore || !step.IsFailed() {
+			continue
+		}
+		if p.matchExitCode(step.ExitCode) || p.matchError(step.Error) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) matchExitCode(code int) bool {
+	for _, want := range p.ExitCodes {
+		if want == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) matchError(err string) bool {
+	if err == "" {
+		return false
+	}
+	for _, want := range p.Errors {
+		if want != "" && strings.Contains(err, want) {
+			return true
+		}
+	}
+	return false
+}
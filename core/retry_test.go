@@ -0,0 +1,90 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestRetryPolicyMatch(t *testing.T) {
+	tests := []struct {
+		policy *RetryPolicy
+		stage  *Stage
+		match  bool
+	}{
+		// nil policy never matches
+		{
+			policy: nil,
+			stage:  &Stage{Status: StatusFailing},
+			match:  false,
+		},
+		// passing stage is never retried
+		{
+			policy: &RetryPolicy{Attempts: 3},
+			stage:  &Stage{Status: StatusPassing, Attempt: 1},
+			match:  false,
+		},
+		// failing stage without filters is retried
+		{
+			policy: &RetryPolicy{Attempts: 3},
+			stage:  &Stage{Status: StatusFailing, Attempt: 1},
+			match:  true,
+		},
+		// zero attempt is treated as the first attempt
+		{
+			policy: &RetryPolicy{Attempts: 2},
+			stage:  &Stage{Status: StatusError},
+			match:  true,
+		},
+		// attempts exhausted
+		{
+			policy: &RetryPolicy{Attempts: 3},
+			stage:  &Stage{Status: StatusFailing, Attempt: 3},
+			match:  false,
+		},
+		// matching step exit code
+		{
+			policy: &RetryPolicy{Attempts: 3, ExitCodes: []int{137}},
+			stage: &Stage{Status: StatusFailing, Attempt: 1, Steps: []*Step{
+				{Status: StatusPassing},
+				{Status: StatusFailing, ExitCode: 137},
+			}},
+			match: true,
+		},
+		// non-matching step exit code
+		{
+			policy: &RetryPolicy{Attempts: 3, ExitCodes: []int{137}},
+			stage: &Stage{Status: StatusFailing, Attempt: 1, Steps: []*Step{
+				{Status: StatusFailing, ExitCode: 1},
+			}},
+			match: false,
+		},
+		// ignored step failures are skipped
+		{
+			policy: &RetryPolicy{Attempts: 3, ExitCodes: []int{137}},
+			stage: &Stage{Status: StatusFailing, Attempt: 1, Steps: []*Step{
+				{Status: StatusFailing, ExitCode: 137, ErrIgnore: true},
+			}},
+			match: false,
+		},
+		// matching stage error
+		{
+			policy: &RetryPolicy{Attempts: 3, Errors: []string{"connection reset"}},
+			stage:  &Stage{Status: StatusError, Attempt: 1, Error: "read tcp: connection reset by peer"},
+			match:  true,
+		},
+		// matching step error
+		{
+			policy: &RetryPolicy{Attempts: 3, Errors: []string{"no space left"}},
+			stage: &Stage{Status: StatusFailing, Attempt: 1, Steps: []*Step{
+				{Status: StatusError, Error: "write /tmp: no space left on device"},
+			}},
+			match: true,
+		},
+	}
+	for i, test := range tests {
+		if got, want := test.policy.Match(test.stage), test.match; got != want {
+			t.Errorf("Want match %v at index %d, got %v", want, i, got)
+		}
+	}
+}
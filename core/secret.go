@@ -17,6 +17,7 @@ package core
 import (
 	"context"
 	"errors"
+	"path"
 	"regexp"
 
 	"github.com/drone/drone-yaml/yaml"
@@ -25,6 +26,7 @@ import (
 var (
 	errSecretNameInvalid = errors.New("Invalid Secret Name")
 	errSecretDataInvalid = errors.New("Invalid Secret Value")
+	errSecretRuleInvalid = errors.New("Invalid Secret Restriction")
 )
 
 type (
@@ -39,6 +41,22 @@ type (
 		Data            string `json:"data,omitempty"`
 		PullRequest     bool   `json:"pull_request,omitempty"`
 		PullRequestPush bool   `json:"pull_request_push,omitempty"`
+		Version         int64  `json:"version,omitempty"`
+
+		// Branches, Refs, Events and Images restrict the
+		// builds and pipeline steps that can access the
+		// secret. An empty list is not restricted. Steps
+		// that override the commands or entrypoint of an
+		// image never match an image restriction.
+		Branches []string `json:"branches,omitempty"`
+		Refs     []string `json:"refs,omitempty"`
+		Events   []string `json:"events,omitempty"`
+		Images   []string `json:"images,omitempty"`
+
+		// Author is the login of the user creating or updating
+		// the secret. It is recorded with the secret version
+		// and is not persisted with the secret itself.
+		Author string `json:"-"`
 	}
 
 	// SecretArgs provides arguments for requesting secrets
@@ -65,6 +83,8 @@ type (
 		Create(context.Context, *Secret) error
 
 		// Update persists an updated secret to the datastore.
+		// A new secret version is created if the secret value
+		// has changed.
 		Update(context.Context, *Secret) error
 
 		// Delete deletes a secret from the datastore.
@@ -91,6 +111,8 @@ type (
 		Create(ctx context.Context, secret *Secret) error
 
 		// Update persists an updated secret to the datastore.
+		// A new secret version is created if the secret value
+		// has changed.
 		Update(ctx context.Context, secret *Secret) error
 
 		// Delete deletes a secret from the datastore.
@@ -113,9 +135,28 @@ func (s *Secret) Validate() error {
 		return errSecretDataInvalid
 	case slugRE.MatchString(s.Name):
 		return errSecretNameInvalid
-	default:
-		return nil
 	}
+	for _, patterns := range [][]string{s.Branches, s.Refs, s.Images} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return errSecretRuleInvalid
+			}
+		}
+	}
+	for _, event := range s.Events {
+		switch event {
+		case EventCron,
+			EventCustom,
+			EventPush,
+			EventPullRequest,
+			EventTag,
+			EventPromote,
+			EventRollback:
+		default:
+			return errSecretRuleInvalid
+		}
+	}
+	return nil
 }
 
 // Copy makes a copy of the secret without the value.
@@ -128,6 +169,11 @@ func (s *Secret) Copy() *Secret {
 		Type:            s.Type,
 		PullRequest:     s.PullRequest,
 		PullRequestPush: s.PullRequestPush,
+		Version:         s.Version,
+		Branches:        s.Branches,
+		Refs:            s.Refs,
+		Events:          s.Events,
+		Images:          s.Images,
 	}
 }
 
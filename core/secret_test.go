@@ -33,6 +33,22 @@ func TestSecretValidate(t *testing.T) {
 			secret: &Secret{Name: "docker/password", Data: "correct-horse-battery-staple"},
 			error:  errSecretNameInvalid,
 		},
+		{
+			secret: &Secret{Name: "password", Data: "correct-horse-battery-staple", Branches: []string{"release/*"}, Refs: []string{"refs/tags/v*"}, Events: []string{EventPromote}, Images: []string{"plugins/docker"}},
+			error:  nil,
+		},
+		{
+			secret: &Secret{Name: "password", Data: "correct-horse-battery-staple", Branches: []string{"release/["}},
+			error:  errSecretRuleInvalid,
+		},
+		{
+			secret: &Secret{Name: "password", Data: "correct-horse-battery-staple", Images: []string{""}},
+			error:  errSecretRuleInvalid,
+		},
+		{
+			secret: &Secret{Name: "password", Data: "correct-horse-battery-staple", Events: []string{"deploy"}},
+			error:  errSecretRuleInvalid,
+		},
 	}
 	for i, test := range tests {
 		got, want := test.secret.Validate(), test.error
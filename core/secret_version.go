@@ -0,0 +1,80 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Secret kinds identify the datastore that owns a versioned
+// secret, since repository and organization secret identifiers
+// are not unique across stores.
+const (
+	SecretRepository   = "repository"
+	SecretOrganization = "organization"
+)
+
+type (
+	// SecretVersion represents an immutable revision of a
+	// repository or organization secret value.
+	SecretVersion struct {
+		ID        int64  `json:"id"`
+		Kind      string `json:"kind"`
+		SecretID  int64  `json:"secret_id"`
+		Version   int64  `json:"version"`
+		Data      string `json:"-"`
+		CreatedBy string `json:"created_by,omitempty"`
+		Created   int64  `json:"created"`
+	}
+
+	// SecretUsage records the secret version consumed by a
+	// build stage.
+	SecretUsage struct {
+		ID          int64  `json:"id"`
+		RepoID      int64  `json:"repo_id"`
+		BuildID     int64  `json:"build_id"`
+		BuildNumber int64  `json:"build_number"`
+		StageID     int64  `json:"stage_id"`
+		Kind        string `json:"kind"`
+		SecretID    int64  `json:"secret_id"`
+		Name        string `json:"name"`
+		Version     int64  `json:"version"`
+		Created     int64  `json:"created"`
+	}
+
+	// SecretVersionStore provides access to secret versions.
+	// Versions are created by the SecretStore and the
+	// GlobalSecretStore when the secret value changes.
+	SecretVersionStore interface {
+		// List returns a list of secret versions, newest
+		// first, from the datastore.
+		List(ctx context.Context, kind string, secret int64) ([]*SecretVersion, error)
+
+		// Find returns a secret version from the datastore.
+		Find(ctx context.Context, kind string, secret, version int64) (*SecretVersion, error)
+	}
+
+	// SecretUsageStore persists secret usage to storage.
+	SecretUsageStore interface {
+		// List returns a list of secrets consumed by the
+		// build from the datastore.
+		List(ctx context.Context, build int64) ([]*SecretUsage, error)
+
+		// ListVersion returns a list of build stages that
+		// consumed the secret version from the datastore.
+		ListVersion(ctx context.Context, kind string, secret, version int64) ([]*SecretUsage, error)
+
+		// Create persists a new secret usage to the datastore.
+		Create(ctx context.Context, usage *SecretUsage) error
+	}
+)
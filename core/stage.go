@@ -14,7 +14,24 @@
 
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// Stage priority defaults. Stages with a higher priority are
+// dispatched before stages with a lower priority.
+const (
+	PriorityLow    = -10
+	PriorityNormal = 0
+	PriorityHigh   = 10
+)
+
+// PriorityAging defines how long a stage waits in the queue
+// before its effective priority is raised by one. This ensures
+// low priority stages are not starved by a steady stream of
+// high priority stages.
+const PriorityAging = time.Minute
 
 type (
 	// Stage represents a stage of build execution.
@@ -41,11 +58,17 @@ type (
 		Stopped   int64             `json:"stopped"`
 		Created   int64             `json:"created"`
 		Updated   int64             `json:"updated"`
+		Expires   int64             `json:"expires,omitempty"`
+		Priority  int               `json:"priority,omitempty"`
+		Reason    string            `json:"reason,omitempty"`
 		Version   int64             `json:"version"`
 		OnSuccess bool              `json:"on_success"`
 		OnFailure bool              `json:"on_failure"`
 		DependsOn []string          `json:"depends_on,omitempty"`
 		Labels    map[string]string `json:"labels,omitempty"`
+		Attempt   int               `json:"attempt,omitempty"`
+		Retry     *RetryPolicy      `json:"retry,omitempty"`
+		Timeout   *TimeoutPolicy    `json:"timeout,omitempty"`
 		Steps     []*Step           `json:"steps,omitempty"`
 	}
 
@@ -93,6 +116,16 @@ func (s *Stage) IsDone() bool {
 	}
 }
 
+// EffectivePriority returns the stage priority adjusted for
+// the amount of time the stage has been waiting in the queue.
+func (s *Stage) EffectivePriority(now time.Time) int {
+	waited := now.Sub(time.Unix(s.Created, 0))
+	if s.Created == 0 || waited <= 0 {
+		return s.Priority
+	}
+	return s.Priority + int(waited/PriorityAging)
+}
+
 // IsFailed returns true if the step has failed
 func (s *Stage) IsFailed() bool {
 	switch s.Status {
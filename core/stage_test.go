@@ -6,7 +6,10 @@
 
 package core
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 var statusDone = []string{
 	StatusDeclined,
@@ -71,3 +74,21 @@ func TestStageIsFailed(t *testing.T) {
 		}
 	}
 }
+
+func TestStageEffectivePriority(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := []struct {
+		stage Stage
+		want  int
+	}{
+		{Stage{Priority: PriorityNormal}, PriorityNormal},
+		{Stage{Priority: PriorityHigh, Created: now.Unix()}, PriorityHigh},
+		{Stage{Priority: PriorityLow, Created: now.Add(-5 * PriorityAging).Unix()}, PriorityLow + 5},
+		{Stage{Priority: PriorityLow, Created: now.Add(time.Hour).Unix()}, PriorityLow},
+	}
+	for i, test := range tests {
+		if got, want := test.stage.EffectivePriority(now), test.want; got != want {
+			t.Errorf("Want effective priority %d at index %d, got %d", want, i, got)
+		}
+	}
+}
@@ -33,6 +33,7 @@ type (
 		DependsOn []string `json:"depends_on,omitempty"`
 		Image     string   `json:"image,omitempty"`
 		Detached  bool     `json:"detached,omitempty"`
+		Attempt   int      `json:"attempt,omitempty"`
 	}
 
 	// StepStore persists build step information to storage.
@@ -51,6 +52,9 @@ type (
 
 		// Update persists an updated stage to the datastore.
 		Update(context.Context, *Step) error
+
+		// Purge deletes all steps for the stage from the datastore.
+		Purge(context.Context, int64) error
 	}
 )
 
@@ -66,3 +70,15 @@ func (s *Step) IsDone() bool {
 		return true
 	}
 }
+
+// IsFailed returns true if the step has failed
+func (s *Step) IsFailed() bool {
+	switch s.Status {
+	case StatusFailing,
+		StatusKilled,
+		StatusError:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// TestCase represents a single test result parsed from a
+	// JUnit report uploaded by a pipeline step.
+	TestCase struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id"`
+		BuildID  int64  `json:"build_id"`
+		StageID  int64  `json:"stage_id"`
+		StepID   int64  `json:"step_id"`
+		Suite    string `json:"suite"`
+		Name     string `json:"name"`
+		Status   string `json:"status"`
+		Duration int64  `json:"duration"` // Duration in milliseconds
+		Message  string `json:"message,omitempty"`
+		Created  int64  `json:"created"`
+	}
+
+	// TestSummary provides the test result totals for a build.
+	TestSummary struct {
+		Total    int64 `json:"total"`
+		Passed   int64 `json:"passed"`
+		Failed   int64 `json:"failed"`
+		Errored  int64 `json:"errored"`
+		Skipped  int64 `json:"skipped"`
+		Duration int64 `json:"duration"` // Duration in milliseconds
+	}
+
+	// TestHistory represents the result of a single test in
+	// a previous build.
+	TestHistory struct {
+		BuildID  int64  `json:"build_id"`
+		Number   int64  `json:"build_number"`
+		Status   string `json:"status"`
+		Duration int64  `json:"duration"`
+		Message  string `json:"message,omitempty"`
+		Created  int64  `json:"created"`
+	}
+
+	// TestStore persists test results to storage.
+	TestStore interface {
+		// List returns a list of test cases for the build.
+		List(ctx context.Context, build int64) ([]*TestCase, error)
+
+		// ListFailed returns a list of failed and errored
+		// test cases for the build.
+		ListFailed(ctx context.Context, build int64) ([]*TestCase, error)
+
+		// Summary returns the test result totals for the build.
+		Summary(ctx context.Context, build int64) (*TestSummary, error)
+
+		// History returns the results of the named test for
+		// the most recent builds of the branch.
+		History(ctx context.Context, repo int64, branch, suite, name string, limit int) ([]*TestHistory, error)
+
+		// Create persists the test cases to the datastore. Test
+		// cases are appended to those already reported by the
+		// step, since a step may upload multiple reports.
+		Create(ctx context.Context, tests []*TestCase) error
+
+		// Delete deletes the test cases reported by the step.
+		Delete(ctx context.Context, step int64) error
+	}
+)
@@ -0,0 +1,56 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+// TimeoutPolicy defines the timeouts enforced by the server
+// for a pipeline stage. The policy is returned to runners as
+// part of the stage details. Timeouts are expressed in seconds.
+type TimeoutPolicy struct {
+	// Queue is the maximum amount of time the stage may
+	// remain pending before it is cancelled.
+	Queue int64 `json:"queue,omitempty"`
+
+	// Steps is the maximum amount of time each named step
+	// may run before the stage is cancelled.
+	Steps map[string]int64 `json:"steps,omitempty"`
+}
+
+// QueueExpired returns true if the pending stage exceeded the
+// queue timeout at the given unix time. The queue time is
+// measured from the last update, which must be set whenever
+// the stage is moved to the pending state, including when a
+// blocked stage is approved.
+func (p *TimeoutPolicy) QueueExpired(stage *Stage, now int64) bool {
+	if p == nil || p.Queue <= 0 {
+		return false
+	}
+	if stage.Status != StatusPending {
+		return false
+	}
+	return now > stage.Updated+p.Queue
+}
+
+// StepExpired returns true if the running step exceeded the
+// step timeout at the given unix time.
+func (p *TimeoutPolicy) StepExpired(step *Step, now int64) bool {
+	if p == nil || step.Status != StatusRunning || step.Started == 0 {
+		return false
+	}
+	timeout := p.Steps[step.Name]
+	if timeout <= 0 {
+		return false
+	}
+	return now > step.Started+timeout
+}
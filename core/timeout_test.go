@@ -0,0 +1,50 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestTimeoutPolicyQueueExpired(t *testing.T) {
+	var policy *TimeoutPolicy
+	stage := &Stage{Status: StatusPending, Updated: 1000}
+	if policy.QueueExpired(stage, 5000) {
+		t.Errorf("Want nil policy to never expire")
+	}
+
+	policy = &TimeoutPolicy{Queue: 600}
+	if policy.QueueExpired(stage, 1600) {
+		t.Errorf("Want stage within queue timeout not expired")
+	}
+	if !policy.QueueExpired(stage, 1601) {
+		t.Errorf("Want stage exceeding queue timeout expired")
+	}
+
+	stage.Status = StatusRunning
+	if policy.QueueExpired(stage, 5000) {
+		t.Errorf("Want running stage not expired")
+	}
+}
+
+func TestTimeoutPolicyStepExpired(t *testing.T) {
+	policy := &TimeoutPolicy{Steps: map[string]int64{"test": 600}}
+	step := &Step{Name: "test", Status: StatusRunning, Started: 1000}
+	if policy.StepExpired(step, 1600) {
+		t.Errorf("Want step within timeout not expired")
+	}
+	if !policy.StepExpired(step, 1601) {
+		t.Errorf("Want step exceeding timeout expired")
+	}
+
+	step.Name = "lint"
+	if policy.StepExpired(step, 5000) {
+		t.Errorf("Want step without timeout not expired")
+	}
+
+	step.Name = "test"
+	step.Status = StatusPassing
+	if policy.StepExpired(step, 5000) {
+		t.Errorf("Want completed step not expired")
+	}
+}
@@ -45,6 +45,11 @@ type (
 		Refresh   string `json:"-"`
 		Expiry    int64  `json:"-"`
 		Hash      string `json:"-"`
+
+		// Access is the personal access token used to
+		// authenticate the request, if any. It is not
+		// persisted to the datastore.
+		Access *AccessToken `json:"-"`
 	}
 
 	// UserParams defines user query parameters.
@@ -16,6 +16,11 @@ package core
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
 )
 
 // Webhook event types.
@@ -34,12 +39,53 @@ const (
 	WebhookActionDisabled = "disabled"
 )
 
+var (
+	errWebhookEndpointInvalid = errors.New("Invalid Webhook Endpoint")
+	errWebhookEventInvalid    = errors.New("Invalid Webhook Event Filter")
+	errWebhookEndpointPrivate = errors.New("Webhook Endpoint cannot be a loopback, private or link-local address")
+)
+
+// privateNetworks defines the private and shared address
+// ranges that webhooks cannot be delivered to.
+var privateNetworks = parseCIDRs(
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
 type (
-	// Webhook defines an integration endpoint.
+	// Webhook defines an integration endpoint registered by
+	// a repository administrator. The Signer is the secret
+	// used to sign deliveries to the endpoint.
 	Webhook struct {
-		Endpoint   string `json:"endpoint,omitempty"`
-		Signer     string `json:"-"`
-		SkipVerify bool   `json:"skip_verify,omitempty"`
+		ID         int64    `json:"id,omitempty"`
+		RepoID     int64    `json:"repo_id,omitempty"`
+		Endpoint   string   `json:"endpoint,omitempty"`
+		Signer     string   `json:"-"`
+		SkipVerify bool     `json:"skip_verify,omitempty"`
+		Events     []string `json:"events,omitempty"`
+		Created    int64    `json:"created,omitempty"`
+		Updated    int64    `json:"updated,omitempty"`
+	}
+
+	// WebhookStore persists repository webhooks.
+	WebhookStore interface {
+		// List returns a list of webhooks for the repository.
+		List(ctx context.Context, repo int64) ([]*Webhook, error)
+
+		// Find returns a webhook from the datastore.
+		Find(ctx context.Context, id int64) (*Webhook, error)
+
+		// Create persists a new webhook to the datastore.
+		Create(ctx context.Context, hook *Webhook) error
+
+		// Update persists an updated webhook to the datastore.
+		Update(ctx context.Context, hook *Webhook) error
+
+		// Delete deletes a webhook from the datastore.
+		Delete(ctx context.Context, hook *Webhook) error
 	}
 
 	// WebhookData provides the webhook data.
@@ -57,3 +103,60 @@ type (
 		Send(context.Context, *WebhookData) error
 	}
 )
+
+// Validate validates the webhook and returns an error if the
+// endpoint is not an http or https url, if the endpoint host is
+// a loopback, private or link-local address, or if an event
+// filter is not a valid pattern.
+func (w *Webhook) Validate() error {
+	uri, err := url.Parse(w.Endpoint)
+	if err != nil || uri.Host == "" ||
+		(uri.Scheme != "http" && uri.Scheme != "https") {
+		return errWebhookEndpointInvalid
+	}
+	host := strings.ToLower(uri.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errWebhookEndpointPrivate
+	}
+	if ip := net.ParseIP(host); ip != nil && IsPrivateAddress(ip) {
+		return errWebhookEndpointPrivate
+	}
+	for _, pattern := range w.Events {
+		if pattern == "" {
+			return errWebhookEventInvalid
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errWebhookEventInvalid
+		}
+	}
+	return nil
+}
+
+// IsPrivateAddress returns true if the ip address is an
+// unspecified, loopback, private or link-local address. The
+// webhook sender refuses to connect to these addresses when
+// delivering to repository webhooks.
+func IsPrivateAddress(ip net.IP) bool {
+	if ip.IsUnspecified() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() {
+		return true
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function parses the list of cidr ranges.
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}
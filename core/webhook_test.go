@@ -0,0 +1,87 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "testing"
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		hook *Webhook
+		err  error
+	}{
+		{
+			hook: &Webhook{Endpoint: "https://company.com/hooks"},
+			err:  nil,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://company.com/hooks", Events: []string{"build", "repo:*"}},
+			err:  nil,
+		},
+		{
+			hook: &Webhook{Endpoint: ""},
+			err:  errWebhookEndpointInvalid,
+		},
+		{
+			hook: &Webhook{Endpoint: "ftp://company.com/hooks"},
+			err:  errWebhookEndpointInvalid,
+		},
+		{
+			hook: &Webhook{Endpoint: "https:///hooks"},
+			err:  errWebhookEndpointInvalid,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://localhost:8080/hooks"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://127.0.0.1/hooks"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://10.0.0.1/hooks"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://169.254.169.254/latest/meta-data"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://[::1]/hooks"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "http://[::ffff:192.168.0.1]/hooks"},
+			err:  errWebhookEndpointPrivate,
+		},
+		{
+			hook: &Webhook{Endpoint: "https://8.8.8.8/hooks"},
+			err:  nil,
+		},
+		{
+			hook: &Webhook{Endpoint: "https://company.com/hooks", Events: []string{""}},
+			err:  errWebhookEventInvalid,
+		},
+		{
+			hook: &Webhook{Endpoint: "https://company.com/hooks", Events: []string{"build:["}},
+			err:  errWebhookEventInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.hook.Validate(), test.err
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
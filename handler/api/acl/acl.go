@@ -17,6 +17,7 @@ package acl
 import (
 	"net/http"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/errors"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
@@ -28,11 +29,15 @@ import (
 // are rejected with a 401 unauthorized error.
 func AuthorizeUser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, ok := request.UserFrom(r.Context())
+		user, ok := request.UserFrom(r.Context())
 		if !ok {
 			render.Unauthorized(w, errors.ErrUnauthorized)
 			logger.FromRequest(r).
 				Debugln("api: authentication required")
+		} else if !isSafe(r) && !checkTokenGlobal(user, core.ScopeAdmin) {
+			render.Forbidden(w, errors.ErrForbidden)
+			logger.FromRequest(r).
+				Debugln("api: token scope required")
 		} else {
 			next.ServeHTTP(w, r)
 		}
@@ -52,6 +57,10 @@ func AuthorizeAdmin(next http.Handler) http.Handler {
 			render.Forbidden(w, errors.ErrForbidden)
 			logger.FromRequest(r).
 				Debugln("api: administrative access required")
+		} else if !checkTokenGlobal(user, core.ScopeAdmin) {
+			render.Forbidden(w, errors.ErrForbidden)
+			logger.FromRequest(r).
+				Debugln("api: token scope required")
 		} else {
 			next.ServeHTTP(w, r)
 		}
@@ -64,6 +64,12 @@ func CheckAccess(read, write, admin bool) func(http.Handler) http.Handler {
 				WithField("name", name)
 
 			user, ok := request.UserFrom(ctx)
+			if ok && !checkToken(user, scopeOf(r, write, admin), owner, name) {
+				render.Forbidden(w, errors.ErrForbidden)
+				log.Debugln("api: token scope required")
+				return
+			}
+
 			switch {
 			case ok == false && write == true:
 				render.Unauthorized(w, errors.ErrUnauthorized)
@@ -144,3 +150,16 @@ func CheckAccess(read, write, admin bool) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// helper function returns the personal access token scope
+// required for the requested repository access.
+func scopeOf(r *http.Request, write, admin bool) string {
+	switch {
+	case admin:
+		return core.ScopeAdmin
+	case write:
+		return writeScope(r)
+	default:
+		return core.ScopeRead
+	}
+}
@@ -44,6 +44,16 @@ func CheckMembership(service core.OrganizationService, admin bool) func(http.Han
 			}
 			log = log.WithField("user.admin", user.Admin)
 
+			scope := core.ScopeRead
+			if admin {
+				scope = writeScope(r)
+			}
+			if !checkToken(user, scope, namespace, "") {
+				render.Forbidden(w, errors.ErrForbidden)
+				log.Debugln("api: token scope required")
+				return
+			}
+
 			// if the user is an administrator they are always
 			// granted access to the organization data.
 			if user.Admin {
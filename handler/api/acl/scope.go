@@ -0,0 +1,79 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+)
+
+// Scope returns an http.Handler middleware that sets the personal
+// access token scope required for write access to the resources
+// in the chain. If unset, write access requires the admin scope.
+func Scope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(
+				request.WithScope(r.Context(), scope),
+			))
+		})
+	}
+}
+
+// writeScope returns the personal access token scope required
+// for write access to the requested resource.
+func writeScope(r *http.Request) string {
+	scope, ok := request.ScopeFrom(r.Context())
+	if !ok {
+		return core.ScopeAdmin
+	}
+	return scope
+}
+
+// isSafe returns true if the http.Request method does not
+// modify the requested resource.
+func isSafe(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkToken returns true if the user is not authenticated with
+// a personal access token, or if the token grants the scope and
+// is not restricted from the repository or namespace. An empty
+// name matches tokens restricted to the namespace only.
+func checkToken(user *core.User, scope, namespace, name string) bool {
+	token := user.Access
+	if token == nil {
+		return true
+	}
+	return token.Allows(scope) && token.Match(namespace, name)
+}
+
+// checkTokenGlobal returns true if the user is not authenticated
+// with a personal access token, or if the token grants the scope
+// and is not restricted to a repository or namespace.
+func checkTokenGlobal(user *core.User, scope string) bool {
+	token := user.Access
+	if token == nil {
+		return true
+	}
+	return token.Allows(scope) && token.Repo == ""
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package acl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+
+	"github.com/go-chi/chi"
+)
+
+// this test verifies that repository access is granted or
+// denied based on the scopes and restrictions of the personal
+// access token used to authenticate the request.
+func TestCheckAccess_Token(t *testing.T) {
+	writeAccess := &core.Perm{
+		Synced: time.Now().Unix(),
+		Read:   true,
+		Write:  true,
+		Admin:  true,
+	}
+
+	tests := []struct {
+		token  *core.AccessToken
+		user   *core.User
+		scope  string
+		access func() func(http.Handler) http.Handler
+		want   int
+	}{
+		// read only token can read
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeRead}},
+			access: CheckReadAccess,
+			want:   http.StatusTeapot,
+		},
+		// read only token cannot write
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeRead}},
+			access: CheckWriteAccess,
+			want:   http.StatusForbidden,
+		},
+		// builds token can write builds
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeBuildsWrite}},
+			scope:  core.ScopeBuildsWrite,
+			access: CheckWriteAccess,
+			want:   http.StatusTeapot,
+		},
+		// builds token cannot write secrets
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeBuildsWrite}},
+			scope:  core.ScopeSecretsWrite,
+			access: CheckWriteAccess,
+			want:   http.StatusForbidden,
+		},
+		// builds token cannot write without a route scope
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeBuildsWrite}},
+			access: CheckWriteAccess,
+			want:   http.StatusForbidden,
+		},
+		// builds token does not grant repository admin access
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeBuildsWrite}},
+			scope:  core.ScopeBuildsWrite,
+			access: CheckAdminAccess,
+			want:   http.StatusForbidden,
+		},
+		// admin token grants repository admin access
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeAdmin}},
+			access: CheckAdminAccess,
+			want:   http.StatusTeapot,
+		},
+		// token restricted to the namespace can read
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeRead}, Repo: "octocat"},
+			access: CheckReadAccess,
+			want:   http.StatusTeapot,
+		},
+		// token restricted to another repository cannot read
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeRead}, Repo: "octocat/spoon-knife"},
+			access: CheckReadAccess,
+			want:   http.StatusForbidden,
+		},
+		// token scopes are enforced for system administrators
+		{
+			token:  &core.AccessToken{Scopes: []string{core.ScopeRead}},
+			user:   mockUserAdmin,
+			access: CheckWriteAccess,
+			want:   http.StatusForbidden,
+		},
+	}
+
+	for i, test := range tests {
+		user := mockUser
+		if test.user != nil {
+			user = test.user
+		}
+		copy := *user
+		copy.Access = test.token
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/api/repos/octocat/hello-world", nil)
+		r = r.WithContext(
+			request.WithPerm(
+				request.WithUser(
+					request.WithRepo(noContext, mockRepo),
+					&copy,
+				),
+				writeAccess,
+			),
+		)
+
+		router := chi.NewRouter()
+		router.Route("/api/repos/{owner}/{name}", func(router chi.Router) {
+			if test.scope != "" {
+				router.Use(Scope(test.scope))
+			}
+			router.Use(test.access())
+			router.Post("/", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})
+		})
+
+		router.ServeHTTP(w, r)
+
+		if got, want := w.Code, test.want; got != want {
+			t.Errorf("Want status code %d, got %d at index %d", want, got, i)
+		}
+	}
+}
+
+// this test verifies that system administrator access requires
+// a personal access token with the admin scope.
+func TestAuthorizeAdmin_Token(t *testing.T) {
+	tests := []struct {
+		token *core.AccessToken
+		want  int
+	}{
+		{&core.AccessToken{Scopes: []string{core.ScopeAdmin}}, http.StatusTeapot},
+		{&core.AccessToken{Scopes: []string{core.ScopeRead}}, http.StatusForbidden},
+		{&core.AccessToken{Scopes: []string{core.ScopeAdmin}, Repo: "octocat"}, http.StatusForbidden},
+	}
+	for i, test := range tests {
+		user := *mockUserAdmin
+		user.Access = test.token
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r = r.WithContext(
+			request.WithUser(r.Context(), &user),
+		)
+
+		AuthorizeAdmin(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}),
+		).ServeHTTP(w, r)
+
+		if got, want := w.Code, test.want; got != want {
+			t.Errorf("Want status code %d, got %d at index %d", want, got, i)
+		}
+	}
+}
+
+// this test verifies that a personal access token requires the
+// admin scope to modify the authenticated user account.
+func TestAuthorizeUser_Token(t *testing.T) {
+	tests := []struct {
+		method string
+		scopes []string
+		want   int
+	}{
+		{"GET", []string{core.ScopeRead}, http.StatusTeapot},
+		{"POST", []string{core.ScopeRead}, http.StatusForbidden},
+		{"POST", []string{core.ScopeBuildsWrite}, http.StatusForbidden},
+		{"POST", []string{core.ScopeAdmin}, http.StatusTeapot},
+	}
+	for i, test := range tests {
+		user := *mockUser
+		user.Access = &core.AccessToken{Scopes: test.scopes}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(test.method, "/", nil)
+		r = r.WithContext(
+			request.WithUser(r.Context(), &user),
+		)
+
+		AuthorizeUser(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}),
+		).ServeHTTP(w, r)
+
+		if got, want := w.Code, test.want; got != want {
+			t.Errorf("Want status code %d, got %d at index %d", want, got, i)
+		}
+	}
+}
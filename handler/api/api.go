@@ -20,24 +20,34 @@ import (
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/acl"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/auth"
 	"github.com/drone/drone/handler/api/badge"
 	globalbuilds "github.com/drone/drone/handler/api/builds"
 	"github.com/drone/drone/handler/api/ccmenu"
+	"github.com/drone/drone/handler/api/deliveries"
+	globaldeploys "github.com/drone/drone/handler/api/deploys"
 	"github.com/drone/drone/handler/api/events"
 	"github.com/drone/drone/handler/api/queue"
+	"github.com/drone/drone/handler/api/quotas"
 	"github.com/drone/drone/handler/api/repos"
 	"github.com/drone/drone/handler/api/repos/builds"
+	"github.com/drone/drone/handler/api/repos/builds/artifacts"
 	"github.com/drone/drone/handler/api/repos/builds/branches"
 	"github.com/drone/drone/handler/api/repos/builds/deploys"
 	"github.com/drone/drone/handler/api/repos/builds/logs"
 	"github.com/drone/drone/handler/api/repos/builds/pulls"
 	"github.com/drone/drone/handler/api/repos/builds/stages"
+	"github.com/drone/drone/handler/api/repos/builds/tests"
 	"github.com/drone/drone/handler/api/repos/collabs"
 	"github.com/drone/drone/handler/api/repos/crons"
 	"github.com/drone/drone/handler/api/repos/encrypt"
+	"github.com/drone/drone/handler/api/repos/environments"
+	"github.com/drone/drone/handler/api/repos/flaky"
 	"github.com/drone/drone/handler/api/repos/secrets"
 	"github.com/drone/drone/handler/api/repos/sign"
+	"github.com/drone/drone/handler/api/repos/webhooks"
+	"github.com/drone/drone/handler/api/retention"
 	globalsecrets "github.com/drone/drone/handler/api/secrets"
 	"github.com/drone/drone/handler/api/system"
 	"github.com/drone/drone/handler/api/template"
@@ -61,9 +71,14 @@ var corsOpts = cors.Options{
 }
 
 func New(
+	artifacts core.ArtifactStore,
+	audits core.AuditStore,
+	blobs core.ArtifactBlobStore,
 	builds core.BuildStore,
 	commits core.CommitService,
 	cron core.CronStore,
+	deliveries core.WebhookDeliveryStore,
+	environs core.EnvironmentStore,
 	events core.Pubsub,
 	globals core.GlobalSecretStore,
 	hooks core.HookService,
@@ -72,8 +87,12 @@ func New(
 	licenses core.LicenseService,
 	orgs core.OrganizationService,
 	perms core.PermStore,
+	quotas core.QuotaStore,
 	repos core.RepositoryStore,
 	repoz core.RepositoryService,
+	resetter core.Resetter,
+	retention core.RetentionStore,
+	retries core.RetryStore,
 	scheduler core.Scheduler,
 	secrets core.SecretStore,
 	stages core.StageStore,
@@ -84,16 +103,26 @@ func New(
 	syncer core.Syncer,
 	system *core.System,
 	template core.TemplateStore,
+	tests core.TestStore,
+	tokens core.AccessTokenStore,
 	transferer core.Transferer,
 	triggerer core.Triggerer,
+	usage core.SecretUsageStore,
 	users core.UserStore,
 	userz core.UserService,
+	versions core.SecretVersionStore,
 	webhook core.WebhookSender,
+	webhooks core.WebhookStore,
 ) Server {
 	return Server{
+		Artifacts:  artifacts,
+		Audits:     audits,
+		Blobs:      blobs,
 		Builds:     builds,
 		Cron:       cron,
 		Commits:    commits,
+		Deliveries: deliveries,
+		Environs:   environs,
 		Events:     events,
 		Globals:    globals,
 		Hooks:      hooks,
@@ -102,8 +131,12 @@ func New(
 		Licenses:   licenses,
 		Orgs:       orgs,
 		Perms:      perms,
+		Quotas:     quotas,
 		Repos:      repos,
 		Repoz:      repoz,
+		Resetter:   resetter,
+		Retention:  retention,
+		Retries:    retries,
 		Scheduler:  scheduler,
 		Secrets:    secrets,
 		Stages:     stages,
@@ -114,19 +147,29 @@ func New(
 		Syncer:     syncer,
 		System:     system,
 		Template:   template,
+		Tests:      tests,
+		Tokens:     tokens,
 		Transferer: transferer,
 		Triggerer:  triggerer,
+		Usage:      usage,
 		Users:      users,
 		Userz:      userz,
+		Versions:   versions,
 		Webhook:    webhook,
+		Webhooks:   webhooks,
 	}
 }
 
 // Server is a http.Handler which exposes drone functionality over HTTP.
 type Server struct {
+	Artifacts  core.ArtifactStore
+	Audits     core.AuditStore
+	Blobs      core.ArtifactBlobStore
 	Builds     core.BuildStore
 	Cron       core.CronStore
 	Commits    core.CommitService
+	Deliveries core.WebhookDeliveryStore
+	Environs   core.EnvironmentStore
 	Events     core.Pubsub
 	Globals    core.GlobalSecretStore
 	Hooks      core.HookService
@@ -135,8 +178,12 @@ type Server struct {
 	Licenses   core.LicenseService
 	Orgs       core.OrganizationService
 	Perms      core.PermStore
+	Quotas     core.QuotaStore
 	Repos      core.RepositoryStore
 	Repoz      core.RepositoryService
+	Resetter   core.Resetter
+	Retention  core.RetentionStore
+	Retries    core.RetryStore
 	Scheduler  core.Scheduler
 	Secrets    core.SecretStore
 	Stages     core.StageStore
@@ -147,11 +194,16 @@ type Server struct {
 	Syncer     core.Syncer
 	System     *core.System
 	Template   core.TemplateStore
+	Tests      core.TestStore
+	Tokens     core.AccessTokenStore
 	Transferer core.Transferer
 	Triggerer  core.Triggerer
+	Usage      core.SecretUsageStore
 	Users      core.UserStore
 	Userz      core.UserService
+	Versions   core.SecretVersionStore
 	Webhook    core.WebhookSender
+	Webhooks   core.WebhookStore
 	Private    bool
 }
 
@@ -165,6 +217,7 @@ func (s Server) Handler() http.Handler {
 
 	cors := cors.New(corsOpts)
 	r.Use(cors.Handler)
+	r.Use(audit.Middleware(s.Audits))
 
 	r.Route("/repos", func(r chi.Router) {
 		// temporary workaround to enable private mode
@@ -190,7 +243,7 @@ func (s Server) Handler() http.Handler {
 			).Post("/", repos.HandleEnable(s.Hooks, s.Repos, s.Webhook))
 			r.With(
 				acl.CheckAdminAccess(),
-			).Delete("/", repos.HandleDisable(s.Repos, s.Webhook))
+			).Delete("/", repos.HandleDisable(s.Repos, s.Webhook, s.Artifacts, s.Blobs))
 			r.With(
 				acl.CheckAdminAccess(),
 			).Post("/chown", repos.HandleChown(s.Repos))
@@ -199,6 +252,7 @@ func (s Server) Handler() http.Handler {
 			).Post("/repair", repos.HandleRepair(s.Hooks, s.Repoz, s.Repos, s.Users, s.System.Link))
 
 			r.Route("/builds", func(r chi.Router) {
+				r.Use(acl.Scope(core.ScopeBuildsWrite))
 				r.Get("/", builds.HandleList(s.Repos, s.Builds))
 				r.With(acl.CheckWriteAccess()).Post("/", builds.HandleCreate(s.Users, s.Repos, s.Commits, s.Triggerer))
 
@@ -214,30 +268,43 @@ func (s Server) Handler() http.Handler {
 				r.Get("/latest", builds.HandleLast(s.Repos, s.Builds, s.Stages))
 				r.Get("/{number}", builds.HandleFind(s.Repos, s.Builds, s.Stages))
 				r.Get("/{number}/logs/{stage}/{step}", logs.HandleFind(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs))
+				r.Get("/{number}/artifacts", artifacts.HandleList(s.Repos, s.Builds, s.Artifacts))
+				r.Get("/{number}/artifacts/{stage}", artifacts.HandleListStage(s.Repos, s.Builds, s.Stages, s.Artifacts))
+				r.Get("/{number}/artifacts/{stage}/*", artifacts.HandleDownload(s.Repos, s.Builds, s.Stages, s.Artifacts, s.Blobs))
+				r.Get("/{number}/tests", tests.HandleSummary(s.Repos, s.Builds, s.Tests))
+				r.Get("/{number}/tests/failed", tests.HandleListFailed(s.Repos, s.Builds, s.Tests))
 
 				r.With(
 					acl.CheckWriteAccess(),
 				).Post("/{number}", builds.HandleRetry(s.Repos, s.Builds, s.Triggerer))
 
+				r.With(
+					acl.CheckWriteAccess(),
+				).Post("/{number}/rerun", builds.HandleRerun(s.Repos, s.Builds, s.Stages, s.Resetter, s.Scheduler))
+
 				r.With(
 					acl.CheckWriteAccess(),
 				).Delete("/{number}", builds.HandleCancel(s.Users, s.Repos, s.Builds, s.Stages, s.Steps, s.Status, s.Scheduler, s.Webhook))
 
 				r.With(
 					acl.CheckWriteAccess(),
-				).Post("/{number}/promote", builds.HandlePromote(s.Repos, s.Builds, s.Triggerer))
+				).Post("/{number}/promote", builds.HandlePromote(s.Repos, s.Builds, s.Environs, s.Triggerer))
 
 				r.With(
 					acl.CheckWriteAccess(),
-				).Post("/{number}/rollback", builds.HandleRollback(s.Repos, s.Builds, s.Triggerer))
+				).Post("/{number}/rollback", builds.HandleRollback(s.Repos, s.Builds, s.Environs, s.Triggerer))
 
 				r.With(
-					acl.CheckAdminAccess(),
-				).Post("/{number}/decline/{stage}", stages.HandleDecline(s.Repos, s.Builds, s.Stages))
+					acl.CheckWriteAccess(),
+				).Post("/{number}/decline/{stage}", stages.HandleDecline(s.Repos, s.Builds, s.Stages, s.Environs))
 
 				r.With(
-					acl.CheckAdminAccess(),
-				).Post("/{number}/approve/{stage}", stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Scheduler))
+					acl.CheckWriteAccess(),
+				).Post("/{number}/approve/{stage}", stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Environs, s.Scheduler))
+
+				r.With(
+					acl.CheckWriteAccess(),
+				).Get("/{number}/secrets", secrets.HandleUsage(s.Repos, s.Builds, s.Usage))
 
 				r.With(
 					acl.CheckAdminAccess(),
@@ -245,16 +312,61 @@ func (s Server) Handler() http.Handler {
 
 				r.With(
 					acl.CheckAdminAccess(),
-				).Delete("/", builds.HandlePurge(s.Repos, s.Builds))
+				).Delete("/", builds.HandlePurge(s.Repos, s.Builds, s.Artifacts, s.Blobs))
 			})
 
 			r.Route("/secrets", func(r chi.Router) {
+				r.Use(acl.Scope(core.ScopeSecretsWrite))
 				r.Use(acl.CheckWriteAccess())
 				r.Get("/", secrets.HandleList(s.Repos, s.Secrets))
 				r.Post("/", secrets.HandleCreate(s.Repos, s.Secrets))
 				r.Get("/{secret}", secrets.HandleFind(s.Repos, s.Secrets))
 				r.Patch("/{secret}", secrets.HandleUpdate(s.Repos, s.Secrets))
 				r.Delete("/{secret}", secrets.HandleDelete(s.Repos, s.Secrets))
+				r.Get("/{secret}/versions", secrets.HandleVersions(s.Repos, s.Secrets, s.Versions))
+				r.Get("/{secret}/versions/{version}/builds", secrets.HandleVersionBuilds(s.Repos, s.Secrets, s.Usage))
+				r.With(
+					acl.CheckAdminAccess(),
+				).Post("/{secret}/versions/{version}/rollback", secrets.HandleRollback(s.Repos, s.Secrets, s.Versions))
+			})
+
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Use(acl.CheckAdminAccess())
+				r.Get("/", webhooks.HandleList(s.Repos, s.Webhooks))
+				r.Post("/", webhooks.HandleCreate(s.Repos, s.Webhooks))
+				r.Get("/{webhook}", webhooks.HandleFind(s.Repos, s.Webhooks))
+				r.Patch("/{webhook}", webhooks.HandleUpdate(s.Repos, s.Webhooks))
+				r.Delete("/{webhook}", webhooks.HandleDelete(s.Repos, s.Webhooks))
+				r.Get("/{webhook}/deliveries", webhooks.HandleDeliveries(s.Repos, s.Webhooks, s.Deliveries))
+			})
+
+			r.Route("/deployments", func(r chi.Router) {
+				r.Use(acl.CheckReadAccess())
+				r.Get("/{target}", deploys.HandleHistory(s.Repos, s.Builds))
+				r.Get("/{target}/compare", deploys.HandleCompare(s.Repos, s.Builds, s.Users, s.Commits))
+			})
+
+			r.With(
+				acl.CheckReadAccess(),
+			).Get("/flaky", flaky.HandleList(s.Repos, s.Retries))
+
+			r.With(
+				acl.CheckReadAccess(),
+			).Get("/tests/history", tests.HandleHistory(s.Repos, s.Tests))
+
+			r.Route("/environments", func(r chi.Router) {
+				r.Use(acl.CheckReadAccess())
+				r.Get("/", environments.HandleList(s.Repos, s.Environs))
+				r.Get("/{environment}", environments.HandleFind(s.Repos, s.Environs))
+				r.With(
+					acl.CheckAdminAccess(),
+				).Post("/", environments.HandleCreate(s.Repos, s.Environs))
+				r.With(
+					acl.CheckAdminAccess(),
+				).Patch("/{environment}", environments.HandleUpdate(s.Repos, s.Environs))
+				r.With(
+					acl.CheckAdminAccess(),
+				).Delete("/{environment}", environments.HandleDelete(s.Repos, s.Environs))
 			})
 
 			r.Route("/sign", func(r chi.Router) {
@@ -296,6 +408,19 @@ func (s Server) Handler() http.Handler {
 		).Get("/cc.xml", ccmenu.Handler(s.Repos, s.Builds, s.System.Link))
 	})
 
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Get("/", audit.HandleList(s.Audits))
+		r.Get("/export", audit.HandleExport(s.Audits))
+	})
+
+	r.Route("/deliveries", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Get("/", deliveries.HandleList(s.Deliveries))
+		r.Get("/{delivery}", deliveries.HandleFind(s.Deliveries))
+		r.Post("/{delivery}/redeliver", deliveries.HandleRedeliver(s.Deliveries))
+	})
+
 	r.Route("/queue", func(r chi.Router) {
 		r.Use(acl.AuthorizeAdmin)
 		r.Get("/", queue.HandleItems(s.Stages))
@@ -303,11 +428,32 @@ func (s Server) Handler() http.Handler {
 		r.Delete("/", queue.HandlePause(s.Scheduler))
 	})
 
+	r.Route("/quotas", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Get("/", quotas.HandleList(s.Quotas))
+		r.Post("/", quotas.HandleCreate(s.Quotas))
+		r.Get("/{quota}", quotas.HandleFind(s.Quotas))
+		r.Patch("/{quota}", quotas.HandleUpdate(s.Quotas))
+		r.Delete("/{quota}", quotas.HandleDelete(s.Quotas))
+	})
+
+	r.Route("/retention", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Get("/", retention.HandleList(s.Retention))
+		r.Post("/", retention.HandleCreate(s.Retention))
+		r.Get("/{retention}", retention.HandleFind(s.Retention))
+		r.Patch("/{retention}", retention.HandleUpdate(s.Retention))
+		r.Delete("/{retention}", retention.HandleDelete(s.Retention))
+	})
+
 	r.Route("/user", func(r chi.Router) {
 		r.Use(acl.AuthorizeUser)
 		r.Get("/", user.HandleFind())
 		r.Patch("/", user.HandleUpdate(s.Users))
 		r.Post("/token", user.HandleToken(s.Users))
+		r.Get("/tokens", user.HandleListTokens(s.Tokens))
+		r.Post("/tokens", user.HandleCreateToken(s.Tokens))
+		r.Delete("/tokens/{token}", user.HandleDeleteToken(s.Tokens))
 		r.Get("/repos", user.HandleRepos(s.Repos))
 		r.Post("/repos", user.HandleSync(s.Syncer, s.Repos))
 
@@ -342,12 +488,17 @@ func (s Server) Handler() http.Handler {
 		})
 	})
 
+	r.Route("/deployments", func(r chi.Router) {
+		r.With(acl.CheckMembership(s.Orgs, false)).Get("/{namespace}", globaldeploys.HandleNamespace(s.Repos, s.Builds))
+	})
+
 	r.Route("/builds", func(r chi.Router) {
 		r.Use(acl.AuthorizeAdmin)
 		r.Get("/incomplete", globalbuilds.HandleIncomplete(s.Repos))
 	})
 
 	r.Route("/secrets", func(r chi.Router) {
+		r.Use(acl.Scope(core.ScopeSecretsWrite))
 		r.With(acl.AuthorizeAdmin).Get("/", globalsecrets.HandleAll(s.Globals))
 		r.With(acl.CheckMembership(s.Orgs, false)).Get("/{namespace}", globalsecrets.HandleList(s.Globals))
 		r.With(acl.CheckMembership(s.Orgs, true)).Post("/{namespace}", globalsecrets.HandleCreate(s.Globals))
@@ -355,6 +506,9 @@ func (s Server) Handler() http.Handler {
 		r.With(acl.CheckMembership(s.Orgs, true)).Post("/{namespace}/{name}", globalsecrets.HandleUpdate(s.Globals))
 		r.With(acl.CheckMembership(s.Orgs, true)).Patch("/{namespace}/{name}", globalsecrets.HandleUpdate(s.Globals))
 		r.With(acl.CheckMembership(s.Orgs, true)).Delete("/{namespace}/{name}", globalsecrets.HandleDelete(s.Globals))
+		r.With(acl.CheckMembership(s.Orgs, true)).Get("/{namespace}/{name}/versions", globalsecrets.HandleVersions(s.Globals, s.Versions))
+		r.With(acl.CheckMembership(s.Orgs, true)).Get("/{namespace}/{name}/versions/{version}/builds", globalsecrets.HandleVersionBuilds(s.Globals, s.Usage))
+		r.With(acl.CheckMembership(s.Orgs, true)).Post("/{namespace}/{name}/versions/{version}/rollback", globalsecrets.HandleRollback(s.Globals, s.Versions))
 	})
 
 	r.Route("/templates", func(r chi.Router) {
@@ -0,0 +1,160 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records administrative and security relevant
+// actions performed through the api.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// redacted replaces sensitive values in the audit diff.
+const redacted = "[redacted]"
+
+// sensitive defines the json fields that are redacted from
+// the audit diff.
+var sensitive = map[string]bool{
+	"data":     true,
+	"hash":     true,
+	"password": true,
+	"refresh":  true,
+	"secret":   true,
+	"signer":   true,
+	"token":    true,
+}
+
+type key int
+
+const storeKey key = iota
+
+// Middleware returns an http middleware that makes the audit
+// store available to the request handlers.
+func Middleware(store core.AuditStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), storeKey, store)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Record records an audit event for the action performed on
+// the target by the authenticated user. The before and after
+// values are json-encoded and compared to produce the diff,
+// with sensitive values redacted. Record is a no-op if the
+// audit middleware is not installed.
+func Record(r *http.Request, action, target string, before, after interface{}) {
+	store, ok := r.Context().Value(storeKey).(core.AuditStore)
+	if !ok || store == nil {
+		return
+	}
+	event := &core.AuditEvent{
+		Action:  action,
+		Target:  target,
+		Diff:    diff(before, after),
+		IP:      remoteAddr(r),
+		Created: time.Now().Unix(),
+	}
+	if user, ok := request.UserFrom(r.Context()); ok {
+		event.Actor = user.Login
+	}
+	err := store.Create(r.Context(), event)
+	if err != nil {
+		logger.FromRequest(r).
+			WithError(err).
+			WithField("action", action).
+			WithField("target", target).
+			Warnln("api: cannot record audit event")
+	}
+}
+
+// helper function returns a json-encoded diff of the fields
+// that changed between the before and after values.
+func diff(before, after interface{}) string {
+	a, b := fields(before), fields(after)
+	out := map[string]interface{}{}
+	for k, v := range a {
+		if !reflect.DeepEqual(v, b[k]) {
+			out[k] = change(k, v, b[k])
+		}
+	}
+	for k, v := range b {
+		if _, ok := a[k]; !ok {
+			out[k] = change(k, nil, v)
+		}
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(out)
+	return string(raw)
+}
+
+// helper function returns the change for the named field,
+// redacting sensitive values.
+func change(name string, before, after interface{}) map[string]interface{} {
+	if sensitive[name] {
+		before, after = redact(before), redact(after)
+	}
+	return map[string]interface{}{
+		"before": before,
+		"after":  after,
+	}
+}
+
+// helper function redacts a non-empty value.
+func redact(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return redacted
+}
+
+// helper function returns the json-encoded fields of the
+// value. Values that do not encode to a json object return
+// an empty field map.
+func fields(v interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return out
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+	json.Unmarshal(raw, &out)
+	return out
+}
+
+// helper function returns the remote address of the request
+// without the port. Requests received from a trusted proxy have
+// the remote address set to the forwarded client address by the
+// server.RealIP middleware (see DRONE_HTTP_TRUSTED_PROXIES).
+func remoteAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
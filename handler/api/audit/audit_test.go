@@ -0,0 +1,108 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestRecord(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	before := &core.Secret{Name: "password", Data: "correct-horse-battery-staple"}
+	after := &core.Secret{Name: "password", Data: "tr0ub4dor&3", PullRequest: true}
+
+	store := mock.NewMockAuditStore(controller)
+	store.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *core.AuditEvent) error {
+		if got, want := event.Actor, "octocat"; got != want {
+			t.Errorf("Want actor %q, got %q", want, got)
+		}
+		if got, want := event.Action, core.AuditSecretUpdate; got != want {
+			t.Errorf("Want action %q, got %q", want, got)
+		}
+		if got, want := event.Target, "octocat/hello-world/password"; got != want {
+			t.Errorf("Want target %q, got %q", want, got)
+		}
+		if got, want := event.IP, "192.0.2.1"; got != want {
+			t.Errorf("Want ip %q, got %q", want, got)
+		}
+		want := `{"data":{"after":"[redacted]","before":"[redacted]"},"pull_request":{"after":true,"before":null}}`
+		if got := event.Diff; got != want {
+			t.Errorf("Want diff %s, got %s", want, got)
+		}
+		return nil
+	})
+
+	h := Middleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Record(r, core.AuditSecretUpdate, "octocat/hello-world/password", before, after)
+	}))
+
+	r := httptest.NewRequest("PATCH", "/", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{Login: "octocat"}),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+}
+
+// this test verifies that Record is a no-op when the audit
+// middleware is not installed, which is the case in handler
+// unit tests.
+func TestRecord_NoMiddleware(t *testing.T) {
+	r := httptest.NewRequest("PATCH", "/", nil)
+	Record(r, core.AuditRepoUpdate, "octocat/hello-world", nil, nil)
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		before, after interface{}
+		diff          string
+	}{
+		{
+			before: &core.Repository{Slug: "octocat/hello-world", Active: false},
+			after:  &core.Repository{Slug: "octocat/hello-world", Active: true},
+			diff:   `{"active":{"after":true,"before":false}}`,
+		},
+		{
+			before: &core.Repository{Slug: "octocat/hello-world"},
+			after:  &core.Repository{Slug: "octocat/hello-world"},
+			diff:   ``,
+		},
+		{
+			before: nil,
+			after:  &core.Secret{Name: "password", Data: "correct-horse-battery-staple"},
+			diff:   `{"data":{"after":"[redacted]","before":null},"name":{"after":"password","before":null}}`,
+		},
+		{
+			before: (*core.Secret)(nil),
+			after:  nil,
+			diff:   ``,
+		},
+	}
+	for i, test := range tests {
+		if got, want := diff(test.before, test.after), test.diff; got != want {
+			t.Errorf("Want diff %s, got %s at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// exportLimit is the number of audit events loaded from the
+// datastore in each batch when exporting.
+const exportLimit = 1000
+
+// HandleList returns an http.HandlerFunc that writes a
+// json-encoded list of audit events matching the query
+// parameters to the response body.
+func HandleList(audit core.AuditStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		list, err := audit.List(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleExport returns an http.HandlerFunc that writes all
+// audit events matching the query parameters to the response
+// body as json lines.
+func HandleExport(audit core.AuditStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		filter.Limit = exportLimit
+		filter.Offset = 0
+
+		list, err := audit.List(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+		enc := json.NewEncoder(w)
+		for {
+			for _, event := range list {
+				enc.Encode(event)
+			}
+			if len(list) < exportLimit {
+				return
+			}
+			filter.Offset += exportLimit
+			list, err = audit.List(r.Context(), filter)
+			if err != nil {
+				// the response status cannot be changed once
+				// the body is written, so the export is
+				// truncated.
+				return
+			}
+		}
+	}
+}
+
+// helper function returns the audit filter from the request
+// query parameters.
+func parseFilter(r *http.Request) (*core.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := &core.AuditFilter{
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+		Target: query.Get("target"),
+	}
+	var err error
+	for name, dst := range map[string]*int64{
+		"since": &filter.Since,
+		"until": &filter.Until,
+	} {
+		if v := query.Get(name); v != "" {
+			if *dst, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for name, dst := range map[string]*int{
+		"limit":  &filter.Limit,
+		"offset": &filter.Offset,
+	} {
+		if v := query.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return filter, nil
+}
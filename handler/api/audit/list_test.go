@@ -0,0 +1,92 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var dummyEvents = []*core.AuditEvent{
+	{ID: 2, Actor: "octocat", Action: core.AuditRepoDisable, Target: "octocat/hello-world", Created: 200},
+	{ID: 1, Actor: "octocat", Action: core.AuditRepoEnable, Target: "octocat/hello-world", Created: 100},
+}
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := &core.AuditFilter{Actor: "octocat", Target: "octocat/hello-world", Since: 100, Limit: 10}
+	store := mock.NewMockAuditStore(controller)
+	store.EXPECT().List(gomock.Any(), filter).Return(dummyEvents, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?actor=octocat&target=octocat/hello-world&since=100&limit=10", nil)
+
+	HandleList(store).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.AuditEvent{}, dummyEvents
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleList_BadRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?since=yesterday", nil)
+
+	HandleList(nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleExport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := &core.AuditFilter{Action: core.AuditRepoEnable, Limit: exportLimit}
+	store := mock.NewMockAuditStore(controller)
+	store.EXPECT().List(gomock.Any(), filter).Return(dummyEvents, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?action=repo.enable&offset=5", nil)
+
+	HandleExport(store).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/x-ndjson"; got != want {
+		t.Errorf("Want content type %q, got %q", want, got)
+	}
+
+	var got []*core.AuditEvent
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		event := new(core.AuditEvent)
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			t.Error(err)
+		}
+		got = append(got, event)
+	}
+	if diff := cmp.Diff(got, dummyEvents); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package deliveries
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// default number of deliveries returned by the list endpoint.
+const defaultLimit = 100
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of the most recent webhook deliveries to the response body.
+func HandleList(deliveries core.WebhookDeliveryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		if limit <= 0 || limit > defaultLimit {
+			limit = defaultLimit
+		}
+		list, err := deliveries.List(r.Context(), limit)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
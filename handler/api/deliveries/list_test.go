@@ -0,0 +1,93 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package deliveries
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	dummyDelivery = &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Action:   core.WebhookActionUpdated,
+		Status:   core.DeliveryFailed,
+		Attempts: 8,
+		Code:     502,
+		Error:    "webhook: unexpected status code 502",
+		Payload:  `{"event":"build"}`,
+	}
+
+	dummyDeliveryList = []*core.WebhookDelivery{
+		dummyDelivery,
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), 10).Return(dummyDeliveryList, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?limit=10", nil)
+
+	HandleList(deliveries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.WebhookDelivery{}, dummyDeliveryList
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleList_DefaultLimit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), defaultLimit).Return(dummyDeliveryList, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?limit=100000", nil)
+
+	HandleList(deliveries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleList_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), defaultLimit).Return(nil, errors.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleList(deliveries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusInternalServerError; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package deliveries
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleRedeliver returns an http.HandlerFunc that queues a new
+// delivery of the webhook payload. The new delivery is sent by
+// the retrier and is written to the response body.
+func HandleRedeliver(deliveries core.WebhookDeliveryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "delivery"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		prev, err := deliveries.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		now := time.Now().Unix()
+		delivery := &core.WebhookDelivery{
+			WebhookID: prev.WebhookID,
+			Endpoint:  prev.Endpoint,
+			Event:     prev.Event,
+			Action:    prev.Action,
+			Status:    core.DeliveryPending,
+			Payload:   prev.Payload,
+			NextRetry: now,
+			Created:   now,
+			Updated:   now,
+		}
+		err = deliveries.Create(r.Context(), delivery)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, delivery, 200)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package deliveries
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleRedeliver(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkDelivery := func(_ context.Context, in *core.WebhookDelivery) error {
+		if got, want := in.Status, core.DeliveryPending; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if got, want := in.Payload, dummyDelivery.Payload; got != want {
+			t.Errorf("Want payload %q, got %q", want, got)
+		}
+		if got, want := in.Endpoint, dummyDelivery.Endpoint; got != want {
+			t.Errorf("Want endpoint %q, got %q", want, got)
+		}
+		if in.Attempts != 0 || in.NextRetry == 0 {
+			t.Errorf("Want delivery queued for immediate retry")
+		}
+		in.ID = 2
+		return nil
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Find(gomock.Any(), dummyDelivery.ID).Return(dummyDelivery, nil)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(checkDelivery)
+
+	c := new(chi.Context)
+	c.URLParams.Add("delivery", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRedeliver(deliveries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.WebhookDelivery)
+	json.NewDecoder(w.Body).Decode(got)
+	if got.ID != 2 {
+		t.Errorf("Want new delivery returned, got id %d", got.ID)
+	}
+}
+
+func TestHandleRedeliver_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Find(gomock.Any(), dummyDelivery.ID).Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("delivery", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRedeliver(deliveries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
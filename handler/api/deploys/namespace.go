@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploys
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleNamespace returns an http.HandlerFunc that writes a
+// json-encoded list of the deployments currently active in each
+// environment, across all repositories in the namespace that are
+// visible to the user, to the response body.
+func HandleNamespace(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			user, _   = request.UserFrom(r.Context())
+		)
+		list, err := repos.List(r.Context(), user.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				Debugln("api: cannot list repositories")
+			return
+		}
+
+		// the deployments are fetched for the namespace in a
+		// single query, and then filtered to the repositories
+		// that are visible to the user.
+		visible := map[int64]*core.Repository{}
+		for _, repo := range list {
+			if repo.Namespace == namespace {
+				visible[repo.ID] = repo
+			}
+		}
+
+		deployments := []*core.Deployment{}
+		if len(visible) == 0 {
+			render.JSON(w, deployments, 200)
+			return
+		}
+		results, err := builds.LatestDeploysNamespace(r.Context(), namespace)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				Debugln("api: cannot list deployments")
+			return
+		}
+		for _, build := range results {
+			repo, ok := visible[build.RepoID]
+			if !ok {
+				continue
+			}
+			deployment := core.NewDeployment(build)
+			deployment.Repo = repo.Slug
+			deployments = append(deployments, deployment)
+		}
+		render.JSON(w, deployments, 200)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deploys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleNamespace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1, Login: "octocat"}
+	mockRepos := []*core.Repository{
+		{ID: 1, Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"},
+		{ID: 2, Namespace: "spaceghost", Name: "hello-world", Slug: "spaceghost/hello-world"},
+	}
+	mockBuild := &core.Build{
+		RepoID:   1,
+		Number:   2,
+		Event:    core.EventPromote,
+		Deploy:   "production",
+		After:    "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		Status:   core.StatusPassing,
+		Started:  1000,
+		Finished: 1060,
+	}
+
+	// build for a repository in the namespace that is not
+	// visible to the user.
+	hiddenBuild := &core.Build{
+		RepoID: 3,
+		Number: 1,
+		Event:  core.EventPromote,
+		Deploy: "production",
+		Status: core.StatusPassing,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().List(gomock.Any(), mockUser.ID).Return(mockRepos, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().LatestDeploysNamespace(gomock.Any(), "octocat").Return([]*core.Build{mockBuild, hiddenBuild}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleNamespace(repos, builds)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	deployment := core.NewDeployment(mockBuild)
+	deployment.Repo = "octocat/hello-world"
+
+	got, want := []*core.Deployment{}, []*core.Deployment{deployment}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
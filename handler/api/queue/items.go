@@ -8,24 +8,40 @@ package queue
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/logger"
 )
 
+// item represents a queue item, including the effective
+// priority used by the scheduler to order the queue.
+type item struct {
+	*core.Stage
+	EffectivePriority int `json:"effective_priority"`
+}
+
 // HandleItems returns an http.HandlerFunc that writes a
 // json-encoded list of queue items to the response body.
 func HandleItems(store core.StageStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		items, err := store.ListIncomplete(ctx)
+		stages, err := store.ListIncomplete(ctx)
 		if err != nil {
 			render.InternalError(w, err)
 			logger.FromRequest(r).WithError(err).
 				Warnln("api: cannot get running items")
 			return
 		}
+		now := time.Now()
+		items := make([]*item, len(stages))
+		for i, stage := range stages {
+			items[i] = &item{
+				Stage:             stage,
+				EffectivePriority: stage.EffectivePriority(now),
+			}
+		}
 		render.JSON(w, items, 200)
 	}
 }
@@ -7,11 +7,53 @@
 package queue
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
 
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
 )
 
 func init() {
 	logrus.SetOutput(ioutil.Discard)
 }
+
+func TestHandleItems(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStages := []*core.Stage{
+		{ID: 1, Priority: core.PriorityLow, Created: time.Now().Add(-time.Hour).Unix()},
+		{ID: 2, Priority: core.PriorityHigh, Created: time.Now().Unix()},
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListIncomplete(gomock.Any()).Return(mockStages, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleItems(stages).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := []*item{}
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 2 {
+		t.Errorf("Want 2 queue items, got %d", len(got))
+		return
+	}
+	if got, want := got[0].EffectivePriority, core.PriorityLow+60; got != want {
+		t.Errorf("Want effective priority %d, got %d", want, got)
+	}
+	if got, want := got[1].EffectivePriority, core.PriorityHigh; got != want {
+		t.Errorf("Want effective priority %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package quotas
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+)
+
+var errQuotaExists = errors.New("A quota with this kind and name already exists")
+
+type quotaInput struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new concurrency quota.
+func HandleCreate(quotas core.QuotaStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(quotaInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		quota := &core.Quota{
+			Kind:    in.Kind,
+			Name:    in.Name,
+			Limit:   in.Limit,
+			Created: time.Now().Unix(),
+			Updated: time.Now().Unix(),
+		}
+		err = quota.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		if _, err := quotas.FindName(r.Context(), quota.Kind, quota.Name); err == nil {
+			render.ErrorCode(w, errQuotaExists, http.StatusConflict)
+			return
+		}
+
+		err = quotas.Create(r.Context(), quota)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, quota, 200)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package quotas
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type quotaUpdate struct {
+	Limit *int `json:"limit"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a concurrency quota.
+func HandleUpdate(quotas core.QuotaStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "quota"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		in := new(quotaUpdate)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		quota, err := quotas.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Limit != nil {
+			quota.Limit = *in.Limit
+		}
+		err = quota.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		quota.Updated = time.Now().Unix()
+		err = quotas.Update(r.Context(), quota)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, quota, 200)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleDownload returns an http.HandlerFunc that writes the
+// artifact contents to the response body.
+func HandleDownload(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stage, ok := findStage(w, r, repos, builds, stages)
+		if !ok {
+			return
+		}
+		artifact, err := artifacts.FindName(r.Context(), stage.ID, chi.URLParam(r, "*"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		rc, err := blobs.Find(r.Context(), artifact.ID)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("artifact", artifact.Name).
+				Debugln("api: cannot find artifact contents")
+			return
+		}
+		defer rc.Close()
+
+		contentType := mime.TypeByExtension(path.Ext(artifact.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", path.Base(artifact.Name)))
+		io.Copy(w, rc)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of build artifacts to the response body.
+func HandleList(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	artifacts core.ArtifactStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		build, err := builds.FindNumber(r.Context(), repo.ID, number)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := artifacts.ListBuild(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleListStage returns an http.HandlerFunc that writes a
+// json-encoded list of stage artifacts to the response body.
+func HandleListStage(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	artifacts core.ArtifactStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stage, ok := findStage(w, r, repos, builds, stages)
+		if !ok {
+			return
+		}
+		list, err := artifacts.List(r.Context(), stage.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// helper function finds the build stage from the url
+// parameters, and writes an error to the response if the
+// stage cannot be found.
+func findStage(
+	w http.ResponseWriter,
+	r *http.Request,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+) (*core.Stage, bool) {
+	var (
+		namespace = chi.URLParam(r, "owner")
+		name      = chi.URLParam(r, "name")
+	)
+	number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+	if err != nil {
+		render.BadRequest(w, err)
+		return nil, false
+	}
+	stageNumber, err := strconv.Atoi(chi.URLParam(r, "stage"))
+	if err != nil {
+		render.BadRequest(w, err)
+		return nil, false
+	}
+	repo, err := repos.FindName(r.Context(), namespace, name)
+	if err != nil {
+		render.NotFound(w, err)
+		return nil, false
+	}
+	build, err := builds.FindNumber(r.Context(), repo.ID, number)
+	if err != nil {
+		render.NotFound(w, err)
+		return nil, false
+	}
+	stage, err := stages.FindNumber(r.Context(), build.ID, stageNumber)
+	if err != nil {
+		render.NotFound(w, err)
+		return nil, false
+	}
+	return stage, true
+}
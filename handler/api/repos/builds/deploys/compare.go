@@ -0,0 +1,111 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploys
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleCompare returns an http.HandlerFunc that writes a json-encoded
+// comparison of two deployments to the target environment, including
+// the files changed between the deployed commits, to the response body.
+// The changes are fetched using the credentials of the repository
+// owner, since the endpoint is available to anonymous users for
+// public repositories.
+func HandleCompare(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	users core.UserStore,
+	commits core.CommitService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			target    = chi.URLParam(r, "target")
+		)
+		from, err := strconv.ParseInt(r.FormValue("from"), 10, 64)
+		if err != nil {
+			render.BadRequestf(w, "Invalid source deployment number")
+			return
+		}
+		to, err := strconv.ParseInt(r.FormValue("to"), 10, 64)
+		if err != nil {
+			render.BadRequestf(w, "Invalid target deployment number")
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		source, err := builds.FindNumber(r.Context(), repo.ID, from)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		dest, err := builds.FindNumber(r.Context(), repo.ID, to)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		if source.Deploy != target || dest.Deploy != target {
+			render.BadRequestf(w, "Build is not a deployment to %s", target)
+			return
+		}
+
+		owner, err := users.Find(r.Context(), repo.UserID)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository owner")
+			return
+		}
+
+		changes, err := commits.CompareChanges(r.Context(), owner, repo.Slug, source.After, dest.After)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot compare deployments")
+			return
+		}
+		if changes == nil {
+			changes = []*core.Change{}
+		}
+		render.JSON(w, &core.DeploymentDiff{
+			From:    core.NewDeployment(source),
+			To:      core.NewDeployment(dest),
+			Changes: changes,
+		}, 200)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deploys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleCompare(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 2, Login: "spaceghost"}
+	mockOwner := &core.User{ID: 1, Login: "octocat"}
+	mockChanges := []*core.Change{
+		{Path: "main.go"},
+		{Path: "README.md", Added: true},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(2)).Return(mockDeploys[0], nil)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(4)).Return(mockDeploys[1], nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(mockOwner, nil)
+
+	commits := mock.NewMockCommitService(controller)
+	commits.EXPECT().CompareChanges(gomock.Any(), mockOwner, mockRepo.Slug, mockDeploys[0].After, mockDeploys[1].After).Return(mockChanges, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "production")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?from=2&to=4", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleCompare(repos, builds, users, commits)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.DeploymentDiff), &core.DeploymentDiff{
+		From:    core.NewDeployment(mockDeploys[0]),
+		To:      core.NewDeployment(mockDeploys[1]),
+		Changes: mockChanges,
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that the deployments of a public
+// repository can be compared by an anonymous user, using
+// the credentials of the repository owner.
+func TestHandleCompare_Anonymous(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockOwner := &core.User{ID: 1, Login: "octocat"}
+	mockChanges := []*core.Change{
+		{Path: "main.go"},
+		{Path: "README.md", Added: true},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(2)).Return(mockDeploys[0], nil)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(4)).Return(mockDeploys[1], nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(mockOwner, nil)
+
+	commits := mock.NewMockCommitService(controller)
+	commits.EXPECT().CompareChanges(gomock.Any(), mockOwner, mockRepo.Slug, mockDeploys[0].After, mockDeploys[1].After).Return(mockChanges, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "production")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?from=2&to=4", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleCompare(repos, builds, users, commits)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.DeploymentDiff), &core.DeploymentDiff{
+		From:    core.NewDeployment(mockDeploys[0]),
+		To:      core.NewDeployment(mockDeploys[1]),
+		Changes: mockChanges,
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleCompare_WrongTarget(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(2)).Return(mockDeploys[0], nil)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, int64(4)).Return(mockDeploys[1], nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "staging")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?from=2&to=4", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleCompare(repos, builds, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), &errors.Error{Message: "Build is not a deployment to staging"}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleCompare_InvalidNumber(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "production")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?from=2", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleCompare(nil, nil, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploys
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleHistory returns an http.HandlerFunc that writes a json-encoded
+// list of deployments to the target environment to the response body.
+func HandleHistory(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			target    = chi.URLParam(r, "target")
+			page      = r.FormValue("page")
+			perPage   = r.FormValue("per_page")
+		)
+		offset, _ := strconv.Atoi(page)
+		limit, _ := strconv.Atoi(perPage)
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		switch offset {
+		case 0, 1:
+			offset = 0
+		default:
+			offset = (offset - 1) * limit
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+
+		results, err := builds.ListDeploys(r.Context(), repo.ID, target, limit, offset)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				WithField("target", target).
+				Debugln("api: cannot list deployments")
+			return
+		}
+
+		deployments := []*core.Deployment{}
+		for _, build := range results {
+			deployments = append(deployments, core.NewDeployment(build))
+		}
+		render.JSON(w, deployments, 200)
+	}
+}
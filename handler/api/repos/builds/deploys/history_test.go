@@ -0,0 +1,116 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deploys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		UserID:    1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+	}
+
+	mockDeploys = []*core.Build{
+		{
+			Number:   2,
+			Parent:   1,
+			Event:    core.EventPromote,
+			Deploy:   "production",
+			After:    "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+			Target:   "master",
+			Trigger:  "octocat",
+			Status:   core.StatusPassing,
+			Started:  1000,
+			Finished: 1060,
+		},
+		{
+			Number:  4,
+			Parent:  3,
+			Event:   core.EventPromote,
+			Deploy:  "production",
+			After:   "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e",
+			Target:  "master",
+			Trigger: "spaceghost",
+			Status:  core.StatusRunning,
+			Started: 2000,
+		},
+	}
+)
+
+func TestHandleHistory(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListDeploys(gomock.Any(), mockRepo.ID, "production", 25, 25).Return(mockDeploys, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "production")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?page=2", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleHistory(repos, builds)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.Deployment{}, []*core.Deployment{
+		core.NewDeployment(mockDeploys[0]),
+		core.NewDeployment(mockDeploys[1]),
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleHistory_RepoNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("target", "production")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleHistory(repos, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package builds
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+)
+
+var (
+	errEnvironmentUnknown = errors.New("Target environment is not defined for this repository")
+	errEnvironmentBranch  = errors.New("Branch is not permitted to deploy to the target environment")
+	errEnvironmentBusy    = errors.New("A deployment to the target environment is already in progress")
+)
+
+// helper function looks up the target environment and enforces
+// its branch and concurrency rules. If the repository does not
+// define any environments, the target is not restricted and a nil
+// environment is returned. If the deployment is rejected, an
+// error is written to the response and false is returned.
+func checkEnvironment(
+	w http.ResponseWriter,
+	r *http.Request,
+	environs core.EnvironmentStore,
+	builds core.BuildStore,
+	repo *core.Repository,
+	prev *core.Build,
+	target string,
+) (*core.Environment, bool) {
+	list, err := environs.List(r.Context(), repo.ID)
+	if err != nil {
+		render.InternalError(w, err)
+		return nil, false
+	}
+	if len(list) == 0 {
+		return nil, true
+	}
+
+	var env *core.Environment
+	for _, item := range list {
+		if item.Name == target {
+			env = item
+			break
+		}
+	}
+	if env == nil {
+		render.BadRequest(w, errEnvironmentUnknown)
+		return nil, false
+	}
+	if !matchEnvironment(env, repo, prev) {
+		render.Forbidden(w, errEnvironmentBranch)
+		return nil, false
+	}
+
+	// an environment accepts a single deployment at a time.
+	// the deployment is rejected if the most recent build
+	// for the target environment is still running. This is
+	// a courtesy check, since concurrent requests can both
+	// pass it; the scheduler holds a deployment until the
+	// previous deployment to the environment is complete.
+	deploys, err := builds.LatestDeploys(r.Context(), repo.ID)
+	if err != nil {
+		render.InternalError(w, err)
+		return nil, false
+	}
+	for _, deploy := range deploys {
+		if deploy.Deploy == target && !deploy.IsDone() {
+			render.ErrorCode(w, errEnvironmentBusy, http.StatusConflict)
+			return nil, false
+		}
+	}
+	return env, true
+}
+
+// helper function returns true if the build branch is permitted
+// to deploy to the environment. Pull requests are matched against
+// the source branch, and pull requests from a fork never match,
+// since the fork controls its branch names.
+func matchEnvironment(env *core.Environment, repo *core.Repository, build *core.Build) bool {
+	if len(env.Branches) == 0 {
+		return true
+	}
+	if build.Event != core.EventPullRequest {
+		return env.MatchBranch(build.Target)
+	}
+	if build.Fork != "" && build.Fork != repo.Slug {
+		return false
+	}
+	return env.MatchBranch(build.Source)
+}
@@ -15,6 +15,7 @@
 package logs
 
 import (
+	"database/sql"
 	"io"
 	"net/http"
 	"strconv"
@@ -54,6 +55,11 @@ func HandleFind(
 			render.BadRequest(w, err)
 			return
 		}
+		query, err := parseQuery(r)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
 		repo, err := repos.FindName(r.Context(), namespace, name)
 		if err != nil {
 			render.NotFound(w, err)
@@ -74,6 +80,22 @@ func HandleFind(
 			render.NotFound(w, err)
 			return
 		}
+		if query != nil {
+			// the query is validated before it is executed, so
+			// a failed query is either a missing log or a
+			// storage error. Every log store reports a missing
+			// log as sql.ErrNoRows.
+			lines, err := logs.Query(r.Context(), step.ID, query)
+			if err == sql.ErrNoRows {
+				render.NotFound(w, err)
+				return
+			} else if err != nil {
+				render.InternalError(w, err)
+				return
+			}
+			render.JSON(w, lines, 200)
+			return
+		}
 		rc, err := logs.Find(r.Context(), step.ID)
 		if err != nil {
 			render.NotFound(w, err)
@@ -88,3 +110,39 @@ func HandleFind(
 		// ELSE: JSON.parse('['+x.split('\n').join(',')+']')
 	}
 }
+
+// helper function parses the log query from the url query
+// parameters. A nil query is returned if the request does not
+// include query parameters, in which case the full log is
+// returned.
+func parseQuery(r *http.Request) (*core.LogQuery, error) {
+	var (
+		params = r.URL.Query()
+		query  = new(core.LogQuery)
+		err    error
+	)
+	if params.Get("offset") == "" &&
+		params.Get("limit") == "" &&
+		params.Get("tail") == "" &&
+		params.Get("search") == "" {
+		return nil, nil
+	}
+	if v := params.Get("offset"); v != "" {
+		if query.Offset, err = strconv.Atoi(v); err != nil {
+			return nil, err
+		}
+	}
+	if v := params.Get("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, err
+		}
+	}
+	if v := params.Get("tail"); v != "" {
+		if query.Tail, err = strconv.Atoi(v); err != nil {
+			return nil, err
+		}
+	}
+	query.Search = params.Get("search")
+	query.Regexp = params.Get("regexp") == "true"
+	return query, query.Validate()
+}
@@ -3,3 +3,149 @@
 // that can be found in the LICENSE file.
 
 package logs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleFind_Query(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Name: "hello-world"}
+	mockBuild := &core.Build{ID: 2, Number: 1}
+	mockStage := &core.Stage{ID: 3, Number: 1}
+	mockStep := &core.Step{ID: 4, Number: 1}
+	mockLines := []*core.Line{{Number: 9, Message: "FAIL"}}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().FindNumber(gomock.Any(), mockStage.ID, mockStep.Number).Return(mockStep, nil)
+
+	query := &core.LogQuery{Tail: 10, Search: "FAIL"}
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Query(gomock.Any(), mockStep.ID, query).Return(mockLines, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "1")
+	c.URLParams.Add("step", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?tail=10&search=FAIL", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleFind(repos, builds, stages, steps, logs).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.Line{}, mockLines
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleFind_QueryErr(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{err: sql.ErrNoRows, code: http.StatusNotFound},
+		{err: errors.New("connection refused"), code: http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		controller := gomock.NewController(t)
+
+		mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Name: "hello-world"}
+		mockBuild := &core.Build{ID: 2, Number: 1}
+		mockStage := &core.Stage{ID: 3, Number: 1}
+		mockStep := &core.Step{ID: 4, Number: 1}
+
+		repos := mock.NewMockRepositoryStore(controller)
+		repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+		builds := mock.NewMockBuildStore(controller)
+		builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+		stages := mock.NewMockStageStore(controller)
+		stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+
+		steps := mock.NewMockStepStore(controller)
+		steps.EXPECT().FindNumber(gomock.Any(), mockStage.ID, mockStep.Number).Return(mockStep, nil)
+
+		logs := mock.NewMockLogStore(controller)
+		logs.EXPECT().Query(gomock.Any(), mockStep.ID, gomock.Any()).Return(nil, test.err)
+
+		c := new(chi.Context)
+		c.URLParams.Add("owner", "octocat")
+		c.URLParams.Add("name", "hello-world")
+		c.URLParams.Add("number", "1")
+		c.URLParams.Add("stage", "1")
+		c.URLParams.Add("step", "1")
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/?tail=10", nil)
+		r = r.WithContext(
+			context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		)
+
+		HandleFind(repos, builds, stages, steps, logs).ServeHTTP(w, r)
+		if got, want := w.Code, test.code; want != got {
+			t.Errorf("Want response code %d for %v, got %d", want, test.err, got)
+		}
+		controller.Finish()
+	}
+}
+
+func TestHandleFind_QueryInvalid(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "1")
+	c.URLParams.Add("step", "1")
+
+	for _, target := range []string{
+		"/?tail=-1",
+		"/?limit=ten",
+		"/?search=(&regexp=true",
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", target, nil)
+		r = r.WithContext(
+			context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		)
+
+		HandleFind(nil, nil, nil, nil, nil).ServeHTTP(w, r)
+		if got, want := w.Code, http.StatusBadRequest; want != got {
+			t.Errorf("Want response code %d for %s, got %d", want, target, got)
+		}
+	}
+}
@@ -7,10 +7,12 @@
 package builds
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
 
@@ -22,6 +24,7 @@ import (
 func HandlePromote(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
+	environs core.EnvironmentStore,
 	triggerer core.Triggerer,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -50,6 +53,10 @@ func HandlePromote(
 			render.BadRequestf(w, "Missing target environment")
 			return
 		}
+		env, ok := checkEnvironment(w, r, environs, builds, repo, prev, environ)
+		if !ok {
+			return
+		}
 
 		hook := &core.Hook{
 			Parent:       prev.Number,
@@ -74,6 +81,7 @@ func HandlePromote(
 			Cron:         prev.Cron,
 			Sender:       prev.Sender,
 			Params:       map[string]string{},
+			Blocked:      env != nil && env.Protected(),
 		}
 
 		for k, v := range prev.Params {
@@ -96,8 +104,9 @@ func HandlePromote(
 		result, err := triggerer.Trigger(r.Context(), repo, hook)
 		if err != nil {
 			render.InternalError(w, err)
-		} else {
-			render.JSON(w, result, 200)
+			return
 		}
+		audit.Record(r, core.AuditBuildPromote, fmt.Sprintf("%s/%d", repo.Slug, prev.Number), nil, result)
+		render.JSON(w, result, 200)
 	}
 }
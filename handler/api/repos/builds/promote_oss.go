@@ -31,6 +31,7 @@ var notImplemented = func(w http.ResponseWriter, r *http.Request) {
 func HandlePromote(
 	core.RepositoryStore,
 	core.BuildStore,
+	core.EnvironmentStore,
 	core.Triggerer,
 ) http.HandlerFunc {
 	return notImplemented
@@ -81,6 +81,9 @@ func TestPromote(t *testing.T) {
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
 
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
 	triggerer := mock.NewMockTriggerer(controller)
 	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Return(mockBuild, nil).Do(checkBuild)
 
@@ -95,7 +98,7 @@ func TestPromote(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(repos, builds, triggerer)(w, r)
+	HandlePromote(repos, builds, environs, triggerer)(w, r)
 	if got, want := w.Code, 200; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -122,7 +125,7 @@ func TestPromote_InvalidBuildNumber(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(nil, nil, nil)(w, r)
+	HandlePromote(nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -154,7 +157,7 @@ func TestPromote_RepoNotFound(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(repos, nil, nil)(w, r)
+	HandlePromote(repos, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -187,7 +190,7 @@ func TestPromote_BuildNotFound(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(repos, builds, nil)(w, r)
+	HandlePromote(repos, builds, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -220,7 +223,7 @@ func TestPromote_MissingTargetError(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(repos, builds, nil)(w, r)
+	HandlePromote(repos, builds, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -242,6 +245,9 @@ func TestPromote_TriggerError(t *testing.T) {
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
 
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
 	triggerer := mock.NewMockTriggerer(controller)
 	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Return(nil, errors.ErrNotFound)
 
@@ -256,7 +262,7 @@ func TestPromote_TriggerError(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePromote(repos, builds, triggerer)(w, r)
+	HandlePromote(repos, builds, environs, triggerer)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -267,3 +273,245 @@ func TestPromote_TriggerError(t *testing.T) {
 		t.Errorf(diff)
 	}
 }
+
+func TestPromote_EnvironmentNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return([]*core.Environment{{Name: "staging"}}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?target=production", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandlePromote(repos, builds, environs, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errEnvironmentUnknown
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestPromote_EnvironmentBranchDenied(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return([]*core.Environment{
+		{Name: "production", Branches: []string{"release/*"}},
+	}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?target=production", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandlePromote(repos, builds, environs, nil)(w, r)
+	if got, want := w.Code, 403; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errEnvironmentBranch
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that a pull request build is matched
+// against the source branch, not the target branch, when
+// promoted to a branch restricted environment.
+func TestPromote_EnvironmentBranchDenied_PullRequest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockBuild := &core.Build{
+		ID:     1,
+		Number: 1,
+		RepoID: 1,
+		Event:  core.EventPullRequest,
+		Ref:    "refs/pull/42/head",
+		Source: "feature/x",
+		Target: "master",
+		Fork:   "octocat/hello-world",
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return([]*core.Environment{
+		{Name: "production", Branches: []string{"master"}},
+	}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?target=production", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandlePromote(repos, builds, environs, nil)(w, r)
+	if got, want := w.Code, 403; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errEnvironmentBranch
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestMatchEnvironment(t *testing.T) {
+	env := &core.Environment{Branches: []string{"master"}}
+	tests := []struct {
+		build *core.Build
+		match bool
+	}{
+		{
+			build: &core.Build{Event: core.EventPush, Target: "master"},
+			match: true,
+		},
+		{
+			build: &core.Build{Event: core.EventPullRequest, Source: "feature/x", Target: "master", Fork: "octocat/hello-world"},
+			match: false,
+		},
+		{
+			build: &core.Build{Event: core.EventPullRequest, Source: "master", Target: "develop", Fork: "octocat/hello-world"},
+			match: true,
+		},
+		{
+			build: &core.Build{Event: core.EventPullRequest, Source: "master", Target: "master", Fork: "spaceghost/hello-world"},
+			match: false,
+		},
+	}
+	for i, test := range tests {
+		if got, want := matchEnvironment(env, mockRepo, test.build), test.match; got != want {
+			t.Errorf("Want match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestPromote_EnvironmentBusy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+	builds.EXPECT().LatestDeploys(gomock.Any(), mockRepo.ID).Return([]*core.Build{
+		{Number: 2, Deploy: "production", Status: core.StatusRunning},
+	}, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return([]*core.Environment{
+		{Name: "production"},
+	}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?target=production", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandlePromote(repos, builds, environs, nil)(w, r)
+	if got, want := w.Code, 409; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errEnvironmentBusy
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestPromote_EnvironmentProtected(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkBuild := func(_ context.Context, _ *core.Repository, hook *core.Hook) error {
+		if !hook.Blocked {
+			t.Errorf("Want promotion to a protected environment blocked")
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+	builds.EXPECT().LatestDeploys(gomock.Any(), mockRepo.ID).Return([]*core.Build{
+		{Number: 2, Deploy: "production", Status: core.StatusPassing},
+	}, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().List(gomock.Any(), mockRepo.ID).Return([]*core.Environment{
+		{Name: "production", Branches: []string{"master"}, Approvers: []string{"octocat"}},
+	}, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Return(mockBuild, nil).Do(checkBuild)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?target=production", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandlePromote(repos, builds, environs, triggerer)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -18,7 +18,12 @@ import (
 
 // HandlePurge returns an http.HandlerFunc that purges the
 // build history. If successful a 204 status code is returned.
-func HandlePurge(repos core.RepositoryStore, builds core.BuildStore) http.HandlerFunc {
+func HandlePurge(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
 			namespace = chi.URLParam(r, "owner")
@@ -35,6 +40,25 @@ func HandlePurge(repos core.RepositoryStore, builds core.BuildStore) http.Handle
 			render.NotFound(w, err)
 			return
 		}
+
+		// the artifact contents may be stored outside of the
+		// database, and are deleted before the builds are purged.
+		list, err := artifacts.ListPurge(r.Context(), repo.ID, number)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		for _, artifact := range list {
+			if err := blobs.Delete(r.Context(), artifact.ID); err != nil {
+				render.InternalError(w, err)
+				return
+			}
+			if err := artifacts.Delete(r.Context(), artifact); err != nil {
+				render.InternalError(w, err)
+				return
+			}
+		}
+
 		err = builds.Purge(r.Context(), repo.ID, number)
 		if err != nil {
 			render.InternalError(w, err)
@@ -13,6 +13,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/errors"
 	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/mock"
@@ -31,6 +32,15 @@ func TestPurge(t *testing.T) {
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
 
+	mockArtifact := &core.Artifact{ID: 1, RepoID: mockRepo.ID, Name: "dist/drone"}
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListPurge(gomock.Any(), mockRepo.ID, int64(50)).Return([]*core.Artifact{mockArtifact}, nil)
+	artifacts.EXPECT().Delete(gomock.Any(), mockArtifact).Return(nil)
+
+	blobs := mock.NewMockArtifactBlobStore(controller)
+	blobs.EXPECT().Delete(gomock.Any(), mockArtifact.ID).Return(nil)
+
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
 	c.URLParams.Add("name", "hello-world")
@@ -41,7 +51,7 @@ func TestPurge(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, builds)(w, r)
+	HandlePurge(repos, builds, artifacts, blobs)(w, r)
 	if got, want := w.Code, http.StatusNoContent; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -66,7 +76,7 @@ func TestPurge_NotFound(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, nil)(w, r)
+	HandlePurge(repos, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -95,7 +105,7 @@ func TestPurge_BadRequest(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(nil, nil)(w, r)
+	HandlePurge(nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -121,6 +131,9 @@ func TestPurge_InternalError(t *testing.T) {
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(errors.ErrNotFound)
 
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListPurge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil, nil)
+
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
 	c.URLParams.Add("name", "hello-world")
@@ -131,7 +144,7 @@ func TestPurge_InternalError(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, builds)(w, r)
+	HandlePurge(repos, builds, artifacts, nil)(w, r)
 	if got, want := w.Code, http.StatusInternalServerError; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
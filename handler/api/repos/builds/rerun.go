@@ -0,0 +1,193 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builds
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleRerun returns an http.HandlerFunc that processes http
+// requests to re-run the failed stages of a build, and the stages
+// downstream of the failed stages, within the same build.
+func HandleRerun(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	resetter core.Resetter,
+	scheduler core.Scheduler,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		build, err := builds.FindNumber(r.Context(), repo.ID, number)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		switch build.Status {
+		case core.StatusFailing, core.StatusError, core.StatusKilled:
+		default:
+			render.BadRequestf(w, "cannot re-run a %s build", build.Status)
+			return
+		}
+
+		stagez, err := stages.ListSteps(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		rerun := rerunStages(stagez)
+		if len(rerun) == 0 {
+			render.BadRequestf(w, "cannot re-run a build without failed stages")
+			return
+		}
+
+		// the start time is reset, since the build timeout
+		// of a pending build is measured from the start time.
+		build.Status = core.StatusPending
+		build.Started = 0
+		build.Finished = 0
+		build.Updated = time.Now().Unix()
+		err = builds.Update(r.Context(), build)
+		if err != nil {
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("build", build.Number).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Warnln("api: cannot update build status to pending")
+			render.ErrorCode(w, err, http.StatusConflict)
+			return
+		}
+
+		for _, stage := range stagez {
+			if !rerun[stage.Name] {
+				continue
+			}
+
+			err := resetter.Reset(r.Context(), stage)
+			if err != nil {
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("stage", stage.Number).
+					WithField("build", build.Number).
+					WithField("namespace", namespace).
+					WithField("name", name).
+					Warnln("api: cannot reset stage")
+				render.InternalError(w, err)
+				return
+			}
+
+			stage.Status = core.StatusPending
+			for _, dep := range stage.DependsOn {
+				if rerun[dep] {
+					stage.Status = core.StatusWaiting
+					break
+				}
+			}
+			if stage.Attempt > 0 {
+				stage.Attempt++
+			}
+			err = stages.Update(r.Context(), stage)
+			if err != nil {
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("stage", stage.Number).
+					WithField("build", build.Number).
+					WithField("namespace", namespace).
+					WithField("name", name).
+					Warnln("api: cannot reset stage status")
+				render.InternalError(w, err)
+				return
+			}
+		}
+
+		// stages waiting on upstream stages are scheduled by the
+		// manager when their dependencies are complete.
+		for _, stage := range stagez {
+			if !rerun[stage.Name] || stage.Status != core.StatusPending {
+				continue
+			}
+			err := scheduler.Schedule(r.Context(), stage)
+			if err != nil {
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("stage", stage.Number).
+					WithField("build", build.Number).
+					WithField("namespace", namespace).
+					WithField("name", name).
+					Warnln("api: cannot schedule stage")
+			}
+		}
+
+		build.Stages = stagez
+		render.JSON(w, build, 200)
+	}
+}
+
+// helper function returns the names of the failed stages,
+// and the stages that directly or indirectly depend on the
+// failed stages.
+func rerunStages(stages []*core.Stage) map[string]bool {
+	rerun := map[string]bool{}
+	for _, stage := range stages {
+		if stage.IsFailed() {
+			rerun[stage.Name] = true
+		}
+	}
+	if len(rerun) == 0 {
+		return rerun
+	}
+	for {
+		changed := false
+		for _, stage := range stages {
+			if rerun[stage.Name] {
+				continue
+			}
+			for _, dep := range stage.DependsOn {
+				if rerun[dep] {
+					rerun[stage.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+		if !changed {
+			return rerun
+		}
+	}
+}
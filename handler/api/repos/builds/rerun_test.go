@@ -0,0 +1,159 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRerun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockBuild := &core.Build{
+		ID:       1,
+		Number:   1,
+		RepoID:   1,
+		Status:   core.StatusFailing,
+		Started:  1000,
+		Finished: 1060,
+	}
+	mockStages := []*core.Stage{
+		{ID: 1, Name: "linux", Status: core.StatusPassing},
+		{ID: 2, Name: "windows", Status: core.StatusFailing, Attempt: 1, Steps: []*core.Step{{ID: 3}}},
+		{ID: 3, Name: "publish", Status: core.StatusSkipped, DependsOn: []string{"linux", "windows"}},
+		{ID: 4, Name: "notify", Status: core.StatusSkipped, DependsOn: []string{"publish"}},
+	}
+
+	checkBuild := func(_ context.Context, build *core.Build) error {
+		if got, want := build.Status, core.StatusPending; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if got, want := build.Started, int64(0); got != want {
+			t.Errorf("Want build started %d, got %d", want, got)
+		}
+		if got, want := build.Finished, int64(0); got != want {
+			t.Errorf("Want build finished %d, got %d", want, got)
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+	builds.EXPECT().Update(gomock.Any(), mockBuild).DoAndReturn(checkBuild)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+	stages.EXPECT().Update(gomock.Any(), mockStages[1]).Return(nil)
+	stages.EXPECT().Update(gomock.Any(), mockStages[2]).Return(nil)
+	stages.EXPECT().Update(gomock.Any(), mockStages[3]).Return(nil)
+
+	resetter := mock.NewMockResetter(controller)
+	resetter.EXPECT().Reset(gomock.Any(), mockStages[1]).Return(nil)
+	resetter.EXPECT().Reset(gomock.Any(), mockStages[2]).Return(nil)
+	resetter.EXPECT().Reset(gomock.Any(), mockStages[3]).Return(nil)
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Schedule(gomock.Any(), mockStages[1]).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRerun(repos, builds, stages, resetter, scheduler)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	tests := []struct {
+		status  string
+		attempt int
+	}{
+		{core.StatusPassing, 0},
+		{core.StatusPending, 2},
+		{core.StatusWaiting, 0},
+		{core.StatusWaiting, 0},
+	}
+	for i, test := range tests {
+		stage := mockStages[i]
+		if got, want := stage.Status, test.status; got != want {
+			t.Errorf("Want stage %s status %s, got %s", stage.Name, want, got)
+		}
+		if got, want := stage.Attempt, test.attempt; got != want {
+			t.Errorf("Want stage %s attempt %d, got %d", stage.Name, want, got)
+		}
+	}
+}
+
+func TestRerun_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockBuild := &core.Build{ID: 1, Number: 1, RepoID: 1, Status: core.StatusRunning}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRerun(repos, builds, nil, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), &errors.Error{Message: "cannot re-run a running build"}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestRerunStages(t *testing.T) {
+	stages := []*core.Stage{
+		{Name: "a", Status: core.StatusPassing},
+		{Name: "b", Status: core.StatusError},
+		{Name: "c", Status: core.StatusPassing, DependsOn: []string{"a"}},
+		{Name: "d", Status: core.StatusSkipped, DependsOn: []string{"e"}},
+		{Name: "e", Status: core.StatusSkipped, DependsOn: []string{"b"}},
+	}
+	got := rerunStages(stages)
+	want := map[string]bool{"b": true, "d": true, "e": true}
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
@@ -16,10 +16,13 @@ package stages
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 
 	"github.com/go-chi/chi"
@@ -33,6 +36,7 @@ func HandleApprove(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
 	stages core.StageStore,
+	environs core.EnvironmentStore,
 	sched core.Scheduler,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -60,6 +64,15 @@ func HandleApprove(
 			render.NotFoundf(w, "Build not found")
 			return
 		}
+		env, ok := reviewer(r, environs, repo, build)
+		if !ok {
+			render.Forbidden(w, errReviewerDenied)
+			return
+		}
+		if env != nil && env.Waiting(build.Created, time.Now()) {
+			render.BadRequestf(w, "Cannot approve a Pipeline before the %s environment wait timer has elapsed", env.Name)
+			return
+		}
 		stage, err := stages.FindNumber(r.Context(), build.ID, stageNumber)
 		if err != nil {
 			render.NotFoundf(w, "Stage not found")
@@ -69,12 +82,15 @@ func HandleApprove(
 			render.BadRequestf(w, "Cannot approve a Pipeline with Status %q", stage.Status)
 			return
 		}
+		before := *stage
 		stage.Status = core.StatusPending
+		stage.Updated = time.Now().Unix()
 		err = stages.Update(r.Context(), stage)
 		if err != nil {
 			render.InternalErrorf(w, "There was a problem approving the Pipeline")
 			return
 		}
+		audit.Record(r, core.AuditStageApprove, fmt.Sprintf("%s/%d/%d", repo.Slug, build.Number, stage.Number), &before, stage)
 		err = sched.Schedule(noContext, stage)
 		if err != nil {
 			render.InternalErrorf(w, "There was a problem scheduling the Pipeline")
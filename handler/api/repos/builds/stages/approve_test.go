@@ -9,18 +9,32 @@ import (
 	"database/sql"
 	"encoding/json"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/mock"
-	"github.com/drone/drone/core"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 )
 
+var mockAdmin = &core.User{
+	ID:    1,
+	Login: "octocat",
+	Admin: true,
+}
+
+var mockUser = &core.User{
+	ID:    1,
+	Login: "octocat",
+}
+
 func TestApprove(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -71,10 +85,74 @@ func TestApprove(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, stages, nil, sched)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that a stage approved after being
+// blocked for longer than the queue timeout is not expired
+// by the watchdog, since the queue time is measured from
+// the approval.
+func TestApprove_QueueTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Status: core.StatusPending,
+	}
+	mockStage := &core.Stage{
+		ID:      222,
+		Number:  2,
+		Status:  core.StatusBlocked,
+		Timeout: &core.TimeoutPolicy{Queue: 3600},
+		Created: time.Now().Add(-24 * time.Hour).Unix(),
+		Updated: time.Now().Add(-24 * time.Hour).Unix(),
+	}
+
+	checkStage := func(_ context.Context, stage *core.Stage) error {
+		if stage.Timeout.QueueExpired(stage, time.Now().Unix()) {
+			t.Errorf("Want queue timeout measured from approval")
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+	stages.EXPECT().Update(gomock.Any(), mockStage).Return(nil).Do(checkStage)
+
+	sched := mock.NewMockScheduler(controller)
+	sched.EXPECT().Schedule(gomock.Any(), mockStage).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, sched)(w, r)
+	HandleApprove(repos, builds, stages, nil, sched)(w, r)
 	if got, want := w.Code, 204; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -122,10 +200,10 @@ func TestApprove_InvalidStatus(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -150,10 +228,10 @@ func TestApprove_InvalidBuildNumber(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(nil, nil, nil, nil)(w, r)
+	HandleApprove(nil, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -178,10 +256,10 @@ func TestApprove_InvalidStageNumber(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(nil, nil, nil, nil)(w, r)
+	HandleApprove(nil, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -228,10 +306,10 @@ func TestApprove_StageNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -270,10 +348,10 @@ func TestApprove_BuildNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, nil, nil)(w, r)
+	HandleApprove(repos, builds, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -309,10 +387,10 @@ func TestApprove_RepoNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, nil, nil, nil)(w, r)
+	HandleApprove(repos, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -367,10 +445,10 @@ func TestApprove_CannotSaveStage(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -428,10 +506,10 @@ func TestApprove_CannotEnqueue(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, sched)(w, r)
+	HandleApprove(repos, builds, stages, nil, sched)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -442,3 +520,231 @@ func TestApprove_CannotEnqueue(t *testing.T) {
 		t.Errorf(diff)
 	}
 }
+
+// this test verifies that a 403 forbidden error is returned
+// if the user is not listed as an approver for the protected
+// deployment environment.
+func TestApprove_NotApprover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Event:  core.EventPromote,
+		Deploy: "production",
+		Status: core.StatusBlocked,
+	}
+	mockEnv := &core.Environment{
+		Name:      "production",
+		Approvers: []string{"spaceghost"},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().FindName(gomock.Any(), mockRepo.ID, mockBuild.Deploy).Return(mockEnv, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, nil, environs, nil)(w, r)
+	if got, want := w.Code, http.StatusForbidden; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errReviewerDenied
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that a 400 bad request error is returned
+// if the environment wait timer has not elapsed.
+// this test verifies that a 403 forbidden status is returned
+// from the http.Handler if a user with write access, but without
+// admin access, attempts to approve a build that is not deployed
+// to an environment with a list of approvers.
+func TestApprove_WriteAccess(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Event:  core.EventPush,
+		Status: core.StatusBlocked,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	ctx := request.WithUser(context.Background(), mockUser)
+	ctx = request.WithPerm(ctx, &core.Perm{Read: true, Write: true})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(ctx, chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, nil, nil, nil)(w, r)
+	if got, want := w.Code, http.StatusForbidden; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errReviewerDenied
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that a user with write access, but without
+// admin access, may approve a build deployed to an environment
+// that lists the user as an approver.
+func TestApprove_Approver(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Event:  core.EventPromote,
+		Deploy: "production",
+		Status: core.StatusPending,
+	}
+	mockStage := &core.Stage{
+		ID:     222,
+		Number: 2,
+		Status: core.StatusBlocked,
+	}
+	mockEnv := &core.Environment{
+		Name:      "production",
+		Approvers: []string{"octocat"},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().FindName(gomock.Any(), mockRepo.ID, mockBuild.Deploy).Return(mockEnv, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+	stages.EXPECT().Update(gomock.Any(), mockStage).Return(nil)
+
+	sched := mock.NewMockScheduler(controller)
+	sched.EXPECT().Schedule(gomock.Any(), mockStage).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	ctx := request.WithUser(context.Background(), mockUser)
+	ctx = request.WithPerm(ctx, &core.Perm{Read: true, Write: true})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(ctx, chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, stages, environs, sched)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestApprove_Waiting(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:      111,
+		Number:  1,
+		Event:   core.EventPromote,
+		Deploy:  "production",
+		Status:  core.StatusBlocked,
+		Created: time.Now().Unix(),
+	}
+	mockEnv := &core.Environment{
+		Name:      "production",
+		Approvers: []string{"octocat"},
+		Wait:      3600,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	environs := mock.NewMockEnvironmentStore(controller)
+	environs.EXPECT().FindName(gomock.Any(), mockRepo.ID, mockBuild.Deploy).Return(mockEnv, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, nil, environs, nil)(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 
 	"github.com/go-chi/chi"
@@ -31,6 +32,7 @@ func HandleDecline(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
 	stages core.StageStore,
+	environs core.EnvironmentStore,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -57,6 +59,11 @@ func HandleDecline(
 			render.NotFoundf(w, "Build not found")
 			return
 		}
+		_, ok := reviewer(r, environs, repo, build)
+		if !ok {
+			render.Forbidden(w, errReviewerDenied)
+			return
+		}
 		stage, err := stages.FindNumber(r.Context(), build.ID, stageNumber)
 		if err != nil {
 			render.NotFoundf(w, "Stage not found")
@@ -67,6 +74,7 @@ func HandleDecline(
 			render.BadRequest(w, err)
 			return
 		}
+		before := *stage
 		stage.Status = core.StatusDeclined
 		err = stages.Update(r.Context(), stage)
 		if err != nil {
@@ -80,6 +88,8 @@ func HandleDecline(
 			return
 		}
 
+		audit.Record(r, core.AuditStageDecline, fmt.Sprintf("%s/%d/%d", repo.Slug, build.Number, stage.Number), &before, stage)
+
 		// TODO delete any pending stages from the build queue
 		// TODO update any pending stages to skipped in the database
 		// TODO update the build status to error in the source code management system
@@ -11,9 +11,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/mock"
-	"github.com/drone/drone/core"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
@@ -33,10 +34,10 @@ func TestDecline_InvalidBuildNumber(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(nil, nil, nil)(w, r)
+	HandleDecline(nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -61,10 +62,10 @@ func TestDecline_InvalidStageNumber(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(nil, nil, nil)(w, r)
+	HandleDecline(nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -100,10 +101,10 @@ func TestDecline_RepoNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(repos, nil, nil)(w, r)
+	HandleDecline(repos, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -142,10 +143,10 @@ func TestDecline_BuildNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(repos, builds, nil)(w, r)
+	HandleDecline(repos, builds, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -192,10 +193,10 @@ func TestDecline_StageNotFound(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(repos, builds, stages)(w, r)
+	HandleDecline(repos, builds, stages, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -247,10 +248,10 @@ func TestDecline_InvalidStatus(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/", nil)
 	r = r.WithContext(
-		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+		context.WithValue(request.WithUser(context.Background(), mockAdmin), chi.RouteCtxKey, c),
 	)
 
-	HandleDecline(repos, builds, stages)(w, r)
+	HandleDecline(repos, builds, stages, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -0,0 +1,54 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+)
+
+var errReviewerDenied = errors.New("You are not permitted to review this Pipeline")
+
+// helper function returns the deployment environment for the
+// build and reports whether the current user is permitted to
+// review (approve or decline) the blocked stage. Environments
+// with a list of approvers may only be reviewed by those users,
+// all other builds may be reviewed by repository admins.
+func reviewer(r *http.Request, environs core.EnvironmentStore, repo *core.Repository, build *core.Build) (*core.Environment, bool) {
+	user, _ := request.UserFrom(r.Context())
+	if user == nil {
+		return nil, false
+	}
+
+	var env *core.Environment
+	switch build.Event {
+	case core.EventPromote, core.EventRollback:
+		// a missing environment is treated the same as an
+		// unprotected environment.
+		env, _ = environs.FindName(r.Context(), repo.ID, build.Deploy)
+	}
+	if env != nil && len(env.Approvers) != 0 {
+		return env, env.IsApprover(user.Login)
+	}
+
+	if user.Admin {
+		return env, true
+	}
+	perm, _ := request.PermFrom(r.Context())
+	return env, perm != nil && perm.Admin
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// historyLimit defines the maximum number of builds
+// included in the test history.
+const historyLimit = 50
+
+var errTestNameRequired = errors.New("Test name is required")
+
+// HandleHistory returns an http.HandlerFunc that writes a
+// json-encoded list of results for the named test, across
+// the most recent builds of the branch, to the response body.
+// The branch defaults to the repository default branch.
+func HandleHistory(
+	repos core.RepositoryStore,
+	tests core.TestStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			suite     = r.FormValue("suite")
+			test      = r.FormValue("name")
+			branch    = r.FormValue("branch")
+		)
+		if test == "" {
+			render.BadRequest(w, errTestNameRequired)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		if branch == "" {
+			branch = repo.Branch
+		}
+		list, err := tests.History(r.Context(), repo.ID, branch, suite, test, historyLimit)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleSummary returns an http.HandlerFunc that writes a
+// json-encoded summary of the build test results to the
+// response body.
+func HandleSummary(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	tests core.TestStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		build, ok := findBuild(w, r, repos, builds)
+		if !ok {
+			return
+		}
+		summary, err := tests.Summary(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, summary, 200)
+	}
+}
+
+// HandleListFailed returns an http.HandlerFunc that writes a
+// json-encoded list of failed build tests to the response
+// body.
+func HandleListFailed(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	tests core.TestStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		build, ok := findBuild(w, r, repos, builds)
+		if !ok {
+			return
+		}
+		list, err := tests.ListFailed(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// helper function finds the build from the url parameters,
+// and writes an error to the response if the build cannot
+// be found.
+func findBuild(
+	w http.ResponseWriter,
+	r *http.Request,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+) (*core.Build, bool) {
+	var (
+		namespace = chi.URLParam(r, "owner")
+		name      = chi.URLParam(r, "name")
+	)
+	number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+	if err != nil {
+		render.BadRequest(w, err)
+		return nil, false
+	}
+	repo, err := repos.FindName(r.Context(), namespace, name)
+	if err != nil {
+		render.NotFound(w, err)
+		return nil, false
+	}
+	build, err := builds.FindNumber(r.Context(), repo.ID, number)
+	if err != nil {
+		render.NotFound(w, err)
+		return nil, false
+	}
+	return build, true
+}
@@ -18,6 +18,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/logger"
@@ -45,6 +46,7 @@ func HandleChown(repos core.RepositoryStore) http.HandlerFunc {
 			return
 		}
 
+		before := *repo
 		user, _ := request.UserFrom(r.Context())
 		repo.UserID = user.ID
 
@@ -57,6 +59,7 @@ func HandleChown(repos core.RepositoryStore) http.HandlerFunc {
 				WithField("name", name).
 				Debugln("api: cannot chown repository")
 		} else {
+			audit.Record(r, core.AuditRepoChown, repo.Slug, &before, repo)
 			render.JSON(w, repo, 200)
 		}
 	}
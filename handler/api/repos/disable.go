@@ -15,9 +15,11 @@
 package repos
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/logger"
 
@@ -29,6 +31,8 @@ import (
 func HandleDisable(
 	repos core.RepositoryStore,
 	sender core.WebhookSender,
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -46,6 +50,7 @@ func HandleDisable(
 				Debugln("api: repository not found")
 			return
 		}
+		before := *repo
 		repo.Active = false
 		err = repos.Update(r.Context(), repo)
 		if err != nil {
@@ -57,10 +62,25 @@ func HandleDisable(
 				Warnln("api: cannot update repository")
 			return
 		}
+		audit.Record(r, core.AuditRepoDisable, repo.Slug, &before, repo)
 
 		action := core.WebhookActionDisabled
 		if r.FormValue("remove") == "true" {
 			action = core.WebhookActionDeleted
+
+			// the artifact contents may be stored outside of
+			// the database, and are deleted with the repository.
+			err = deleteArtifacts(r.Context(), artifacts, blobs, repo)
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("namespace", owner).
+					WithField("name", name).
+					Warnln("api: cannot delete repository artifacts")
+				return
+			}
+
 			err = repos.Delete(r.Context(), repo)
 			if err != nil {
 				render.InternalError(w, err)
@@ -71,6 +91,7 @@ func HandleDisable(
 					Warnln("api: cannot delete repository")
 				return
 			}
+			audit.Record(r, core.AuditRepoDelete, repo.Slug, repo, nil)
 		}
 
 		err = sender.Send(r.Context(), &core.WebhookData{
@@ -89,3 +110,21 @@ func HandleDisable(
 		render.JSON(w, repo, 200)
 	}
 }
+
+// helper function deletes the repository artifacts, and their
+// contents, from the artifact store.
+func deleteArtifacts(ctx context.Context, artifacts core.ArtifactStore, blobs core.ArtifactBlobStore, repo *core.Repository) error {
+	list, err := artifacts.ListRepo(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range list {
+		if err := blobs.Delete(ctx, artifact.ID); err != nil {
+			return err
+		}
+		if err := artifacts.Delete(ctx, artifact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -45,7 +45,7 @@ func TestDisable(t *testing.T) {
 	r := httptest.NewRequest("DELETE", "/api/repos/octocat/hello-world", nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, webhook))
+	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, webhook, nil, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 200; want != got {
@@ -74,7 +74,7 @@ func TestDisable_NotFound(t *testing.T) {
 	r := httptest.NewRequest("DELETE", "/api/repos/octocat/hello-world", nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, nil))
+	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, nil, nil, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 404; want != got {
@@ -108,7 +108,7 @@ func TestDisable_InternalError(t *testing.T) {
 	r := httptest.NewRequest("DELETE", "/api/repos/octocat/hello-world", nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, nil))
+	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, nil, nil, nil))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, http.StatusInternalServerError; want != got {
@@ -139,6 +139,15 @@ func TestDelete(t *testing.T) {
 	repos.EXPECT().Update(gomock.Any(), repo).Return(nil)
 	repos.EXPECT().Delete(gomock.Any(), repo).Return(nil)
 
+	mockArtifact := &core.Artifact{ID: 2, RepoID: repo.ID, Name: "dist/drone"}
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListRepo(gomock.Any(), repo.ID).Return([]*core.Artifact{mockArtifact}, nil)
+	artifacts.EXPECT().Delete(gomock.Any(), mockArtifact).Return(nil)
+
+	blobs := mock.NewMockArtifactBlobStore(controller)
+	blobs.EXPECT().Delete(gomock.Any(), mockArtifact.ID).Return(nil)
+
 	// a failed webhook should result in a warning message in the
 	// logs, but should not cause the endpoint to error.
 	webhook := mock.NewMockWebhookSender(controller)
@@ -148,7 +157,7 @@ func TestDelete(t *testing.T) {
 	r := httptest.NewRequest("DELETE", "/api/repos/octocat/hello-world?remove=true", nil)
 
 	router := chi.NewRouter()
-	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, webhook))
+	router.Delete("/api/repos/{owner}/{name}", HandleDisable(repos, webhook, artifacts, blobs))
 	router.ServeHTTP(w, r)
 
 	if got, want := w.Code, 200; want != got {
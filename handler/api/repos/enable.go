@@ -19,6 +19,7 @@ import (
 	"os"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/logger"
@@ -56,6 +57,7 @@ func HandleEnable(
 				Debugln("api: repository not found")
 			return
 		}
+		before := *repo
 		repo.Active = true
 		repo.UserID = user.ID
 
@@ -106,6 +108,7 @@ func HandleEnable(
 				Debugln("api: cannot activate repository")
 			return
 		}
+		audit.Record(r, core.AuditRepoEnable, repo.Slug, &before, repo)
 
 		err = sender.Send(r.Context(), &core.WebhookData{
 			Event:  core.WebhookEventRepo,
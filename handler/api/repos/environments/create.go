@@ -0,0 +1,74 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environments
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type environmentInput struct {
+	Name      string   `json:"name"`
+	Branches  []string `json:"branches"`
+	Approvers []string `json:"approvers"`
+	Wait      int64    `json:"wait"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a repository environment.
+func HandleCreate(
+	repos core.RepositoryStore,
+	environs core.EnvironmentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(environmentInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		env := &core.Environment{
+			RepoID:    repo.ID,
+			Name:      in.Name,
+			Branches:  in.Branches,
+			Approvers: in.Approvers,
+			Wait:      in.Wait,
+			Created:   time.Now().Unix(),
+			Updated:   time.Now().Unix(),
+		}
+		err = env.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = environs.Create(r.Context(), env)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditEnvironmentCreate, repo.Slug+"/"+env.Name, nil, env)
+		render.JSON(w, env, 200)
+	}
+}
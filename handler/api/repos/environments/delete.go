@@ -0,0 +1,37 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environments
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// HandleDelete returns an http.HandlerFunc that processes http
+// requests to delete a repository environment.
+func HandleDelete(
+	repos core.RepositoryStore,
+	environs core.EnvironmentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		repo, env, err := lookup(r, repos, environs)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		err = environs.Delete(r.Context(), env)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditEnvironmentDelete, repo.Slug+"/"+env.Name, env, nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
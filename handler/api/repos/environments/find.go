@@ -0,0 +1,51 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environments
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes json-encoded
+// environment details to the the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	environs core.EnvironmentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, env, err := lookup(r, repos, environs)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, env, 200)
+	}
+}
+
+// helper function returns the repository and named environment
+// from the request url parameters.
+func lookup(r *http.Request, repos core.RepositoryStore, environs core.EnvironmentStore) (*core.Repository, *core.Environment, error) {
+	var (
+		namespace = chi.URLParam(r, "owner")
+		name      = chi.URLParam(r, "name")
+		environ   = chi.URLParam(r, "environment")
+	)
+	repo, err := repos.FindName(r.Context(), namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	env, err := environs.FindName(r.Context(), repo.ID, environ)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, env, nil
+}
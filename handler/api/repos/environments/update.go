@@ -0,0 +1,71 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environments
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+)
+
+type environmentUpdate struct {
+	Branches  *[]string `json:"branches"`
+	Approvers *[]string `json:"approvers"`
+	Wait      *int64    `json:"wait"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a repository environment.
+func HandleUpdate(
+	repos core.RepositoryStore,
+	environs core.EnvironmentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(environmentUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		repo, env, err := lookup(r, repos, environs)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		before := *env
+		if in.Branches != nil {
+			env.Branches = *in.Branches
+		}
+		if in.Approvers != nil {
+			env.Approvers = *in.Approvers
+		}
+		if in.Wait != nil {
+			env.Wait = *in.Wait
+		}
+		env.Updated = time.Now().Unix()
+
+		err = env.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = environs.Update(r.Context(), env)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditEnvironmentUpdate, repo.Slug+"/"+env.Name, &before, env)
+		render.JSON(w, env, 200)
+	}
+}
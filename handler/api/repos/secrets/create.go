@@ -11,17 +11,23 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
 
 	"github.com/go-chi/chi"
 )
 
 type secretInput struct {
-	Type            string `json:"type"`
-	Name            string `json:"name"`
-	Data            string `json:"data"`
-	PullRequest     bool   `json:"pull_request"`
-	PullRequestPush bool   `json:"pull_request_push"`
+	Type            string   `json:"type"`
+	Name            string   `json:"name"`
+	Data            string   `json:"data"`
+	PullRequest     bool     `json:"pull_request"`
+	PullRequestPush bool     `json:"pull_request_push"`
+	Branches        []string `json:"branches"`
+	Refs            []string `json:"refs"`
+	Events          []string `json:"events"`
+	Images          []string `json:"images"`
 }
 
 // HandleCreate returns an http.HandlerFunc that processes http
@@ -53,6 +59,14 @@ func HandleCreate(
 			Data:            in.Data,
 			PullRequest:     in.PullRequest,
 			PullRequestPush: in.PullRequestPush,
+			Branches:        in.Branches,
+			Refs:            in.Refs,
+			Events:          in.Events,
+			Images:          in.Images,
+		}
+
+		if user, ok := request.UserFrom(r.Context()); ok {
+			s.Author = user.Login
 		}
 
 		err = s.Validate()
@@ -66,6 +80,7 @@ func HandleCreate(
 			render.InternalError(w, err)
 			return
 		}
+		audit.Record(r, core.AuditSecretCreate, repo.Slug+"/"+s.Name, nil, s)
 
 		s = s.Copy()
 		render.JSON(w, s, 200)
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 
 	"github.com/go-chi/chi"
@@ -43,6 +44,7 @@ func HandleDelete(
 			render.InternalError(w, err)
 			return
 		}
+		audit.Record(r, core.AuditSecretDelete, repo.Slug+"/"+s.Name, s, nil)
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
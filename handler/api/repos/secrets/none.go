@@ -46,3 +46,19 @@ func HandleFind(core.RepositoryStore, core.SecretStore) http.HandlerFunc {
 func HandleList(core.RepositoryStore, core.SecretStore) http.HandlerFunc {
 	return notImplemented
 }
+
+func HandleVersions(core.RepositoryStore, core.SecretStore, core.SecretVersionStore) http.HandlerFunc {
+	return notImplemented
+}
+
+func HandleVersionBuilds(core.RepositoryStore, core.SecretStore, core.SecretUsageStore) http.HandlerFunc {
+	return notImplemented
+}
+
+func HandleRollback(core.RepositoryStore, core.SecretStore, core.SecretVersionStore) http.HandlerFunc {
+	return notImplemented
+}
+
+func HandleUsage(core.RepositoryStore, core.BuildStore, core.SecretUsageStore) http.HandlerFunc {
+	return notImplemented
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleVersions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	list := []*core.SecretVersion{
+		{ID: 2, Kind: core.SecretRepository, SecretID: 1, Version: 2, Data: "correct-horse-battery-staple", CreatedBy: "octocat"},
+		{ID: 1, Kind: core.SecretRepository, SecretID: 1, Version: 1, Data: "pa55word", CreatedBy: "octocat"},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummySecretRepo.Namespace, dummySecretRepo.Name).Return(dummySecretRepo, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().FindName(gomock.Any(), dummySecretRepo.ID, "github_password").Return(&core.Secret{ID: 1, RepoID: 1, Name: "github_password"}, nil)
+
+	versions := mock.NewMockSecretVersionStore(controller)
+	versions.EXPECT().List(gomock.Any(), core.SecretRepository, int64(1)).Return(list, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("secret", "github_password")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleVersions(repos, secrets, versions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := []*core.SecretVersion{}
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 2 {
+		t.Errorf("Want 2 secret versions, got %d", len(got))
+		return
+	}
+	for _, version := range got {
+		if version.Data != "" {
+			t.Errorf("Want secret version data scrubbed")
+		}
+	}
+}
+
+func TestHandleRollback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	secret := &core.Secret{ID: 1, RepoID: 1, Name: "github_password", Data: "correct-horse-battery-staple", Version: 2}
+	version := &core.SecretVersion{Kind: core.SecretRepository, SecretID: 1, Version: 1, Data: "pa55word"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummySecretRepo.Namespace, dummySecretRepo.Name).Return(dummySecretRepo, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().FindName(gomock.Any(), dummySecretRepo.ID, "github_password").Return(secret, nil)
+	secrets.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, in *core.Secret) error {
+		if got, want := in.Data, "pa55word"; got != want {
+			t.Errorf("Want secret data rolled back to %q, got %q", want, got)
+		}
+		if got, want := in.Author, "octocat"; got != want {
+			t.Errorf("Want secret author %q, got %q", want, got)
+		}
+		in.Version = 3
+		return nil
+	})
+
+	versions := mock.NewMockSecretVersionStore(controller)
+	versions.EXPECT().Find(gomock.Any(), core.SecretRepository, int64(1), int64(1)).Return(version, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("secret", "github_password")
+	c.URLParams.Add("version", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(context.Background(), &core.User{Login: "octocat"}), chi.RouteCtxKey, c),
+	)
+
+	HandleRollback(repos, secrets, versions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &core.Secret{}, &core.Secret{ID: 1, RepoID: 1, Name: "github_password", Version: 3}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleRollback_VersionNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummySecretRepo.Namespace, dummySecretRepo.Name).Return(dummySecretRepo, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().FindName(gomock.Any(), dummySecretRepo.ID, "github_password").Return(&core.Secret{ID: 1, RepoID: 1, Name: "github_password"}, nil)
+
+	versions := mock.NewMockSecretVersionStore(controller)
+	versions.EXPECT().Find(gomock.Any(), core.SecretRepository, int64(1), int64(9)).Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("secret", "github_password")
+	c.URLParams.Add("version", "9")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRollback(repos, secrets, versions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleVersionBuilds_BadVersion(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("secret", "github_password")
+	c.URLParams.Add("version", "latest")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleVersionBuilds(nil, nil, nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
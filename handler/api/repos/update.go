@@ -19,6 +19,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/logger"
@@ -38,6 +39,7 @@ type (
 		CancelPush  *bool   `json:"auto_cancel_pushes"`
 		Timeout     *int64  `json:"timeout"`
 		Throttle    *int64  `json:"throttle"`
+		Priority    *int64  `json:"priority"`
 		Counter     *int64  `json:"counter"`
 	}
 )
@@ -62,6 +64,7 @@ func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
 				Debugln("api: repository not found")
 			return
 		}
+		before := *repo
 
 		in := new(repositoryInput)
 		err = json.NewDecoder(r.Body).Decode(in)
@@ -109,6 +112,9 @@ func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
 			if in.Throttle != nil {
 				repo.Throttle = *in.Throttle
 			}
+			if in.Priority != nil {
+				repo.Priority = *in.Priority
+			}
 			if in.Counter != nil {
 				repo.Counter = *in.Counter
 			}
@@ -133,6 +139,7 @@ func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
 				Warnln("api: cannot update repository")
 			return
 		}
+		audit.Record(r, core.AuditRepoUpdate, repo.Slug, &before, repo)
 
 		render.JSON(w, repo, 200)
 	}
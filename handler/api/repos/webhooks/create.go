@@ -0,0 +1,75 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type webhookInput struct {
+	Endpoint   string   `json:"endpoint"`
+	Secret     string   `json:"secret"`
+	SkipVerify bool     `json:"skip_verify"`
+	Events     []string `json:"events"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to register a new repository webhook.
+func HandleCreate(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(webhookInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		hook := &core.Webhook{
+			RepoID:     repo.ID,
+			Endpoint:   in.Endpoint,
+			Signer:     in.Secret,
+			SkipVerify: in.SkipVerify,
+			Events:     in.Events,
+			Created:    time.Now().Unix(),
+			Updated:    time.Now().Unix(),
+		}
+
+		err = hook.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = webhooks.Create(r.Context(), hook)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditWebhookCreate, target(r, hook), nil, hook)
+		render.JSON(w, hook, 200)
+	}
+}
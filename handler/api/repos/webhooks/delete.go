@@ -0,0 +1,37 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// HandleDelete returns an http.HandlerFunc that processes http
+// requests to delete a repository webhook.
+func HandleDelete(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, err := lookup(r, repos, webhooks)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		err = webhooks.Delete(r.Context(), hook)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditWebhookDelete, target(r, hook), hook, nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
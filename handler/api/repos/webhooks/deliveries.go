@@ -0,0 +1,40 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// number of recent deliveries returned for a webhook.
+const deliveryLimit = 50
+
+// HandleDeliveries returns an http.HandlerFunc that writes a
+// json-encoded list of recent webhook deliveries to the
+// response body.
+func HandleDeliveries(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+	deliveries core.WebhookDeliveryStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, err := lookup(r, repos, webhooks)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := deliveries.ListWebhook(r.Context(), hook.ID, deliveryLimit)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes json-encoded
+// webhook details to the the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, err := lookup(r, repos, webhooks)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, hook, 200)
+	}
+}
+
+// helper function returns the webhook from the request url,
+// or an error if the webhook does not belong to the repository.
+func lookup(r *http.Request, repos core.RepositoryStore, webhooks core.WebhookStore) (*core.Webhook, error) {
+	var (
+		namespace = chi.URLParam(r, "owner")
+		name      = chi.URLParam(r, "name")
+	)
+	id, err := strconv.ParseInt(chi.URLParam(r, "webhook"), 10, 64)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	repo, err := repos.FindName(r.Context(), namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := webhooks.Find(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if hook.RepoID != repo.ID {
+		return nil, errors.ErrNotFound
+	}
+	return hook, nil
+}
+
+// target returns the audit log target for the webhook.
+func target(r *http.Request, hook *core.Webhook) string {
+	return fmt.Sprintf("%s/%s/%d",
+		chi.URLParam(r, "owner"),
+		chi.URLParam(r, "name"),
+		hook.ID,
+	)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+)
+
+type webhookUpdate struct {
+	Endpoint   *string   `json:"endpoint"`
+	Secret     *string   `json:"secret"`
+	SkipVerify *bool     `json:"skip_verify"`
+	Events     *[]string `json:"events"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a repository webhook.
+func HandleUpdate(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(webhookUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		hook, err := lookup(r, repos, webhooks)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		before := *hook
+		if in.Endpoint != nil {
+			hook.Endpoint = *in.Endpoint
+		}
+		if in.Secret != nil {
+			hook.Signer = *in.Secret
+		}
+		if in.SkipVerify != nil {
+			hook.SkipVerify = *in.SkipVerify
+		}
+		if in.Events != nil {
+			hook.Events = *in.Events
+		}
+		hook.Updated = time.Now().Unix()
+
+		err = hook.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = webhooks.Update(r.Context(), hook)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditWebhookUpdate, target(r, hook), &before, hook)
+		render.JSON(w, hook, 200)
+	}
+}
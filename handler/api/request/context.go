@@ -28,6 +28,7 @@ const (
 	userKey key = iota
 	permKey
 	repoKey
+	scopeKey
 )
 
 // WithUser returns a copy of parent in which the user value is set
@@ -62,3 +63,14 @@ func RepoFrom(ctx context.Context) (*core.Repository, bool) {
 	repo, ok := ctx.Value(repoKey).(*core.Repository)
 	return repo, ok
 }
+
+// WithScope returns a copy of parent in which the scope value is set
+func WithScope(parent context.Context, scope string) context.Context {
+	return context.WithValue(parent, scopeKey, scope)
+}
+
+// ScopeFrom returns the value of the scope key on the ctx
+func ScopeFrom(ctx context.Context) (string, bool) {
+	scope, ok := ctx.Value(scopeKey).(string)
+	return scope, ok
+}
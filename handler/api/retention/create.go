@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retention
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+)
+
+type retentionInput struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Builds  int    `json:"keep_builds"`
+	Days    int    `json:"keep_days"`
+	LogDays int    `json:"keep_log_days"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new retention policy.
+func HandleCreate(policies core.RetentionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(retentionInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		policy := &core.Retention{
+			Kind:    in.Kind,
+			Name:    in.Name,
+			Builds:  in.Builds,
+			Days:    in.Days,
+			LogDays: in.LogDays,
+			Created: time.Now().Unix(),
+			Updated: time.Now().Unix(),
+		}
+		err = policy.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = policies.Create(r.Context(), policy)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, policy, 200)
+	}
+}
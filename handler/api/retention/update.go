@@ -0,0 +1,73 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retention
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type retentionUpdate struct {
+	Builds  *int `json:"keep_builds"`
+	Days    *int `json:"keep_days"`
+	LogDays *int `json:"keep_log_days"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a retention policy.
+func HandleUpdate(policies core.RetentionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "retention"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		in := new(retentionUpdate)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		policy, err := policies.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Builds != nil {
+			policy.Builds = *in.Builds
+		}
+		if in.Days != nil {
+			policy.Days = *in.Days
+		}
+		if in.LogDays != nil {
+			policy.LogDays = *in.LogDays
+		}
+		err = policy.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		policy.Updated = time.Now().Unix()
+		err = policies.Update(r.Context(), policy)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, policy, 200)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 
 	"github.com/go-chi/chi"
@@ -33,6 +34,7 @@ func HandleDelete(secrets core.GlobalSecretStore) http.HandlerFunc {
 			render.InternalError(w, err)
 			return
 		}
+		audit.Record(r, core.AuditSecretDelete, s.Namespace+"/"+s.Name, s, nil)
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
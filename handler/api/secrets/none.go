@@ -50,3 +50,15 @@ func HandleList(core.GlobalSecretStore) http.HandlerFunc {
 func HandleAll(core.GlobalSecretStore) http.HandlerFunc {
 	return notImplemented
 }
+
+func HandleVersions(core.GlobalSecretStore, core.SecretVersionStore) http.HandlerFunc {
+	return notImplemented
+}
+
+func HandleVersionBuilds(core.GlobalSecretStore, core.SecretUsageStore) http.HandlerFunc {
+	return notImplemented
+}
+
+func HandleRollback(core.GlobalSecretStore, core.SecretVersionStore) http.HandlerFunc {
+	return notImplemented
+}
@@ -11,15 +11,21 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
 
 	"github.com/go-chi/chi"
 )
 
 type secretUpdate struct {
-	Data            *string `json:"data"`
-	PullRequest     *bool   `json:"pull_request"`
-	PullRequestPush *bool   `json:"pull_request_push"`
+	Data            *string   `json:"data"`
+	PullRequest     *bool     `json:"pull_request"`
+	PullRequestPush *bool     `json:"pull_request_push"`
+	Branches        *[]string `json:"branches"`
+	Refs            *[]string `json:"refs"`
+	Events          *[]string `json:"events"`
+	Images          *[]string `json:"images"`
 }
 
 // HandleUpdate returns an http.HandlerFunc that processes http
@@ -44,6 +50,7 @@ func HandleUpdate(secrets core.GlobalSecretStore) http.HandlerFunc {
 			return
 		}
 
+		before := *s
 		if in.Data != nil {
 			s.Data = *in.Data
 		}
@@ -53,6 +60,22 @@ func HandleUpdate(secrets core.GlobalSecretStore) http.HandlerFunc {
 		if in.PullRequestPush != nil {
 			s.PullRequestPush = *in.PullRequestPush
 		}
+		if in.Branches != nil {
+			s.Branches = *in.Branches
+		}
+		if in.Refs != nil {
+			s.Refs = *in.Refs
+		}
+		if in.Events != nil {
+			s.Events = *in.Events
+		}
+		if in.Images != nil {
+			s.Images = *in.Images
+		}
+
+		if user, ok := request.UserFrom(r.Context()); ok {
+			s.Author = user.Login
+		}
 
 		err = s.Validate()
 		if err != nil {
@@ -65,6 +88,7 @@ func HandleUpdate(secrets core.GlobalSecretStore) http.HandlerFunc {
 			render.InternalError(w, err)
 			return
 		}
+		audit.Record(r, core.AuditSecretUpdate, s.Namespace+"/"+s.Name, &before, s)
 
 		s = s.Copy()
 		render.JSON(w, s, 200)
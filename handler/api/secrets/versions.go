@@ -0,0 +1,121 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package secrets
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleVersions returns an http.HandlerFunc that writes a
+// json-encoded list of secret versions to the response body.
+// The secret values are not included in the response.
+func HandleVersions(
+	secrets core.GlobalSecretStore,
+	versions core.SecretVersionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			name      = chi.URLParam(r, "name")
+		)
+		secret, err := secrets.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := versions.List(r.Context(), core.SecretOrganization, secret.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleVersionBuilds returns an http.HandlerFunc that writes a
+// json-encoded list of build stages that consumed the secret
+// version to the response body.
+func HandleVersionBuilds(
+	secrets core.GlobalSecretStore,
+	usage core.SecretUsageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			name      = chi.URLParam(r, "name")
+		)
+		version, err := strconv.ParseInt(chi.URLParam(r, "version"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		secret, err := secrets.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := usage.ListVersion(r.Context(), core.SecretOrganization, secret.ID, version)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleRollback returns an http.HandlerFunc that processes http
+// requests to roll back a secret to a previous version. The
+// previous value is saved as a new version of the secret.
+func HandleRollback(
+	secrets core.GlobalSecretStore,
+	versions core.SecretVersionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			name      = chi.URLParam(r, "name")
+		)
+		version, err := strconv.ParseInt(chi.URLParam(r, "version"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		s, err := secrets.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		v, err := versions.Find(r.Context(), core.SecretOrganization, s.ID, version)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		before := *s
+		s.Data = v.Data
+		if user, ok := request.UserFrom(r.Context()); ok {
+			s.Author = user.Login
+		}
+		err = secrets.Update(r.Context(), s)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		audit.Record(r, core.AuditSecretRollback, s.Namespace+"/"+s.Name, &before, s)
+
+		s = s.Copy()
+		render.JSON(w, s, 200)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleVersionBuilds(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	list := []*core.SecretUsage{
+		{ID: 1, RepoID: 1, BuildID: 2, BuildNumber: 3, StageID: 4, Kind: core.SecretOrganization, SecretID: 5, Name: "github_password", Version: 1},
+	}
+
+	secrets := mock.NewMockGlobalSecretStore(controller)
+	secrets.EXPECT().FindName(gomock.Any(), "octocat", "github_password").Return(&core.Secret{ID: 5, Namespace: "octocat", Name: "github_password"}, nil)
+
+	usage := mock.NewMockSecretUsageStore(controller)
+	usage.EXPECT().ListVersion(gomock.Any(), core.SecretOrganization, int64(5), int64(1)).Return(list, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+	c.URLParams.Add("name", "github_password")
+	c.URLParams.Add("version", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleVersionBuilds(secrets, usage).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := []*core.SecretUsage{}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, list); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleRollback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	secret := &core.Secret{ID: 5, Namespace: "octocat", Name: "github_password", Data: "correct-horse-battery-staple", Version: 2}
+	version := &core.SecretVersion{Kind: core.SecretOrganization, SecretID: 5, Version: 1, Data: "pa55word"}
+
+	secrets := mock.NewMockGlobalSecretStore(controller)
+	secrets.EXPECT().FindName(gomock.Any(), "octocat", "github_password").Return(secret, nil)
+	secrets.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, in *core.Secret) error {
+		if got, want := in.Data, "pa55word"; got != want {
+			t.Errorf("Want secret data rolled back to %q, got %q", want, got)
+		}
+		return nil
+	})
+
+	versions := mock.NewMockSecretVersionStore(controller)
+	versions.EXPECT().Find(gomock.Any(), core.SecretOrganization, int64(5), int64(1)).Return(version, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+	c.URLParams.Add("name", "github_password")
+	c.URLParams.Add("version", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRollback(secrets, versions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
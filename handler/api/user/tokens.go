@@ -0,0 +1,145 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/dchest/uniuri"
+	"github.com/go-chi/chi"
+)
+
+var errTokenExpiry = errors.New("Invalid Token Expiry")
+
+type tokenInput struct {
+	Name    string   `json:"name"`
+	Scopes  []string `json:"scopes"`
+	Repo    string   `json:"repo"`
+	Expires int64    `json:"expires"`
+}
+
+type accessTokenWithToken struct {
+	*core.AccessToken
+	Token string `json:"token"`
+}
+
+// HandleListTokens returns an http.HandlerFunc that writes a
+// json-encoded list of personal access tokens owned by the
+// current user to the response body.
+func HandleListTokens(tokens core.AccessTokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		list, err := tokens.List(r.Context(), viewer.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot list tokens")
+		} else {
+			render.JSON(w, list, 200)
+		}
+	}
+}
+
+// HandleCreateToken returns an http.HandlerFunc that processes
+// an http.Request to create a personal access token for the
+// current user. The token value is only returned on creation.
+func HandleCreateToken(tokens core.AccessTokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+
+		in := new(tokenInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot unmarshal request body")
+			return
+		}
+
+		now := time.Now().Unix()
+		if in.Expires != 0 && in.Expires <= now {
+			render.BadRequest(w, errTokenExpiry)
+			return
+		}
+
+		value := uniuri.NewLen(40)
+		token := &core.AccessToken{
+			UserID:  viewer.ID,
+			Name:    in.Name,
+			Scopes:  in.Scopes,
+			Repo:    in.Repo,
+			Hash:    core.HashToken(value),
+			Expires: in.Expires,
+			Created: now,
+		}
+		err = token.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = tokens.Create(r.Context(), token)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot create token")
+			return
+		}
+		audit.Record(r, core.AuditTokenCreate, viewer.Login+"/"+token.Name, nil, token)
+		render.JSON(w, &accessTokenWithToken{token, value}, 200)
+	}
+}
+
+// HandleDeleteToken returns an http.HandlerFunc that processes
+// an http.Request to delete a personal access token owned by
+// the current user.
+func HandleDeleteToken(tokens core.AccessTokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		id, err := strconv.ParseInt(chi.URLParam(r, "token"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		token, err := tokens.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		if token.UserID != viewer.ID {
+			render.NotFound(w, errors.ErrNotFound)
+			return
+		}
+		err = tokens.Delete(r.Context(), token)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot delete token")
+			return
+		}
+		audit.Record(r, core.AuditTokenDelete, viewer.Login+"/"+token.Name, token, nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCreateToken(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1, Login: "octocat"}
+
+	var stored *core.AccessToken
+	tokens := mock.NewMockAccessTokenStore(controller)
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, token *core.AccessToken) error {
+			stored = token
+			return nil
+		},
+	)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "deploy",
+		Scopes: []string{core.ScopeBuildsWrite},
+		Repo:   "octocat/hello-world",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleCreateToken(tokens)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+		return
+	}
+
+	out := new(accessTokenWithToken)
+	json.NewDecoder(w.Body).Decode(out)
+	if out.Token == "" {
+		t.Errorf("Expect token value returned")
+	}
+	if got, want := stored.Hash, core.HashToken(out.Token); got != want {
+		t.Errorf("Want token hash persisted")
+	}
+	if got, want := stored.UserID, mockUser.ID; got != want {
+		t.Errorf("Want user id %d, got %d", want, got)
+	}
+}
+
+func TestCreateToken_InvalidScope(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "deploy",
+		Scopes: []string{"repo:write"},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1}),
+	)
+
+	HandleCreateToken(nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCreateToken_Expired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:    "deploy",
+		Scopes:  []string{core.ScopeRead},
+		Expires: 1,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1}),
+	)
+
+	HandleCreateToken(nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestDeleteToken(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockToken := &core.AccessToken{ID: 2, UserID: 1, Name: "deploy"}
+
+	tokens := mock.NewMockAccessTokenStore(controller)
+	tokens.EXPECT().Find(gomock.Any(), mockToken.ID).Return(mockToken, nil)
+	tokens.EXPECT().Delete(gomock.Any(), mockToken).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("token", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 1}), chi.RouteCtxKey, c),
+	)
+
+	HandleDeleteToken(tokens)(w, r)
+	if got, want := w.Code, http.StatusNoContent; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that a user cannot delete a token owned
+// by another user.
+func TestDeleteToken_NotOwner(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockToken := &core.AccessToken{ID: 2, UserID: 3, Name: "deploy"}
+
+	tokens := mock.NewMockAccessTokenStore(controller)
+	tokens.EXPECT().Find(gomock.Any(), mockToken.ID).Return(mockToken, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("token", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 1}), chi.RouteCtxKey, c),
+	)
+
+	HandleDeleteToken(tokens)(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
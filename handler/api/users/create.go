@@ -21,6 +21,7 @@ import (
 
 	"github.com/dchest/uniuri"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/logger"
@@ -94,6 +95,7 @@ func HandleCreate(users core.UserStore, service core.UserService, sender core.We
 				Warnln("api: cannot create user")
 			return
 		}
+		audit.Record(r, core.AuditUserCreate, user.Login, nil, user)
 
 		err = sender.Send(r.Context(), &core.WebhookData{
 			Event:  core.WebhookEventUser,
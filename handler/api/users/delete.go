@@ -19,6 +19,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/logger"
 
@@ -55,6 +56,7 @@ func HandleDelete(
 				Warnln("api: cannot delete user")
 			return
 		}
+		audit.Record(r, core.AuditUserDelete, user.Login, user, nil)
 
 		err = sender.Send(r.Context(), &core.WebhookData{
 			Event:  core.WebhookEventUser,
@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/render"
 	"github.com/drone/drone/logger"
 
@@ -54,6 +55,7 @@ func HandleUpdate(users core.UserStore, transferer core.Transferer) http.Handler
 			return
 		}
 
+		before := *user
 		if in.Admin != nil {
 			user.Admin = *in.Admin
 		}
@@ -72,6 +74,7 @@ func HandleUpdate(users core.UserStore, transferer core.Transferer) http.Handler
 			logger.FromRequest(r).WithError(err).
 				Warnln("api: cannot update user")
 		} else {
+			audit.Record(r, core.AuditUserUpdate, user.Login, &before, user)
 			render.JSON(w, user, 200)
 		}
 
@@ -0,0 +1,77 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+type discovery struct {
+	Issuer          string   `json:"issuer"`
+	JWKSURI         string   `json:"jwks_uri"`
+	ResponseTypes   []string `json:"response_types_supported"`
+	SubjectTypes    []string `json:"subject_types_supported"`
+	SigningAlgs     []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported []string `json:"claims_supported"`
+}
+
+type jwks struct {
+	Keys []*core.JSONWebKey `json:"keys"`
+}
+
+// HandleDiscovery returns an http.HandlerFunc that writes the
+// OpenID Connect discovery document to the response body.
+func HandleDiscovery(identity core.IdentityService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		issuer := strings.TrimSuffix(identity.Issuer(), "/")
+		writeJSON(w, &discovery{
+			Issuer:        issuer,
+			JWKSURI:       issuer + "/.well-known/jwks",
+			ResponseTypes: []string{"id_token"},
+			SubjectTypes:  []string{"public"},
+			SigningAlgs:   []string{"RS256"},
+			ClaimsSupported: []string{
+				"sub",
+				"aud",
+				"exp",
+				"iat",
+				"iss",
+				"nbf",
+				"repo",
+				"ref",
+				"event",
+				"build_number",
+				"deploy_target",
+				"pipeline",
+			},
+		}, 200)
+	}
+}
+
+// HandleJWKS returns an http.HandlerFunc that writes the public
+// keys used to verify identity tokens to the response body.
+func HandleJWKS(identity core.IdentityService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := identity.Keys(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, &jwks{Keys: keys}, 200)
+	}
+}
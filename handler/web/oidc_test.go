@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleDiscovery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	identity := mock.NewMockIdentityService(controller)
+	identity.EXPECT().Issuer().Return("https://drone.company.com/")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/.well-known/openid-configuration", nil)
+
+	HandleDiscovery(identity).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(discovery)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Issuer, "https://drone.company.com"; got != want {
+		t.Errorf("Want issuer %q, got %q", want, got)
+	}
+	if got, want := got.JWKSURI, "https://drone.company.com/.well-known/jwks"; got != want {
+		t.Errorf("Want jwks uri %q, got %q", want, got)
+	}
+}
+
+func TestHandleJWKS(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockKeys := []*core.JSONWebKey{
+		{Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "k1", N: "0vx7agoebGcQSuuPiLJXZpt", E: "AQAB"},
+	}
+
+	identity := mock.NewMockIdentityService(controller)
+	identity.EXPECT().Keys(gomock.Any()).Return(mockKeys, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/.well-known/jwks", nil)
+
+	HandleJWKS(identity).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(jwks), &jwks{Keys: mockKeys}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
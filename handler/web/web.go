@@ -35,6 +35,7 @@ func New(
 	builds core.BuildStore,
 	client *scm.Client,
 	hooks core.HookParser,
+	identity core.IdentityService,
 	license *core.License,
 	licenses core.LicenseService,
 	linker core.Linker,
@@ -54,6 +55,7 @@ func New(
 		Builds:    builds,
 		Client:    client,
 		Hooks:     hooks,
+		Identity:  identity,
 		License:   license,
 		Licenses:  licenses,
 		Linker:    linker,
@@ -76,6 +78,7 @@ type Server struct {
 	Builds    core.BuildStore
 	Client    *scm.Client
 	Hooks     core.HookParser
+	Identity  core.IdentityService
 	License   *core.License
 	Licenses  core.LicenseService
 	Linker    core.Linker
@@ -110,6 +113,8 @@ func (s Server) Handler() http.Handler {
 	r.Get("/link/{namespace}/{name}/src/*", link.HandleTree(s.Linker))
 	r.Get("/link/{namespace}/{name}/commit/{commit}", link.HandleCommit(s.Linker))
 	r.Get("/version", HandleVersion)
+	r.Get("/.well-known/openid-configuration", HandleDiscovery(s.Identity))
+	r.Get("/.well-known/jwks", HandleJWKS(s.Identity))
 	r.Get("/varz", HandleVarz(s.Client, s.License))
 
 	r.Handle("/login",
@@ -0,0 +1,248 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livelog
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/broker"
+	"github.com/drone/drone/core"
+
+	"github.com/dchest/uniuri"
+	"github.com/sirupsen/logrus"
+)
+
+// channel is the broker channel used to distribute streams.
+const channel = "drone_logs"
+
+// stream operations distributed through the broker.
+const (
+	opCreate = "create"
+	opDelete = "delete"
+	opWrite  = "write"
+)
+
+// lines written to a stream are buffered and distributed in
+// batches, at most once per flush interval or when the batch
+// size is reached, to avoid a broker round trip per line.
+const (
+	flushInterval = 250 * time.Millisecond
+	flushSize     = 100
+)
+
+// event wraps a stream operation distributed through the
+// broker with the identifier of the originating replica.
+type event struct {
+	Origin string       `json:"origin"`
+	Op     string       `json:"op"`
+	ID     int64        `json:"id"`
+	Lines  []*core.Line `json:"lines,omitempty"`
+}
+
+type distributed struct {
+	*streamer
+
+	origin string
+	broker broker.Broker
+
+	mu      sync.Mutex
+	pending map[int64][]*core.Line
+	locks   map[int64]*sync.Mutex
+
+	done chan struct{}
+	once sync.Once
+}
+
+// NewDistributed returns a new log streamer that replicates
+// streams to all replicas that share the message broker, so
+// that logs can be tailed from any replica.
+func NewDistributed(b broker.Broker) (core.LogStream, error) {
+	d := &distributed{
+		streamer: New().(*streamer),
+		origin:   uniuri.NewLen(16),
+		broker:   b,
+		pending:  map[int64][]*core.Line{},
+		locks:    map[int64]*sync.Mutex{},
+		done:     make(chan struct{}),
+	}
+	sub, err := b.Subscribe(context.Background(), channel)
+	if err != nil {
+		return nil, err
+	}
+	go d.receive(sub)
+	go d.flushEvery(flushInterval)
+	return d, nil
+}
+
+func (d *distributed) Create(ctx context.Context, id int64) error {
+	d.streamer.Create(ctx, id)
+	return d.publish(ctx, opCreate, id, nil)
+}
+
+func (d *distributed) Delete(ctx context.Context, id int64) error {
+	// the stream may have been created by another replica
+	// before this replica received the create operation, and
+	// is therefore not required to exist locally.
+	err := d.streamer.Delete(ctx, id)
+	if err != nil && err != errStreamNotFound {
+		return err
+	}
+	// buffered lines are distributed before the delete
+	// operation so that other replicas receive the full log.
+	// The stream lock is held until the delete operation is
+	// published, so that a concurrent flush cannot publish
+	// lines after the delete.
+	lock := d.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+	if err := d.flushLocked(ctx, id); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	delete(d.locks, id)
+	d.mu.Unlock()
+	return d.publish(ctx, opDelete, id, nil)
+}
+
+// Close stops distributing the buffered lines at the flush
+// interval.
+func (d *distributed) Close() error {
+	d.once.Do(func() {
+		close(d.done)
+	})
+	return nil
+}
+
+func (d *distributed) Write(ctx context.Context, id int64, line *core.Line) error {
+	err := d.streamer.Write(ctx, id, line)
+	if err != nil && err != errStreamNotFound {
+		return err
+	}
+	d.mu.Lock()
+	d.pending[id] = append(d.pending[id], line)
+	full := len(d.pending[id]) >= flushSize
+	d.mu.Unlock()
+	if full {
+		return d.flush(ctx, id)
+	}
+	return nil
+}
+
+// flush distributes the buffered lines of the stream. The
+// stream lock is held while the lines are published, so that
+// batches are published in order.
+func (d *distributed) flush(ctx context.Context, id int64) error {
+	lock := d.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+	return d.flushLocked(ctx, id)
+}
+
+// flushLocked distributes the buffered lines of the stream.
+// The caller must hold the stream lock.
+func (d *distributed) flushLocked(ctx context.Context, id int64) error {
+	d.mu.Lock()
+	lines := d.pending[id]
+	delete(d.pending, id)
+	d.mu.Unlock()
+	if len(lines) == 0 {
+		return nil
+	}
+	return d.publish(ctx, opWrite, id, lines)
+}
+
+// lock returns the lock that serializes publishing the
+// operations of the stream.
+func (d *distributed) lock(id int64) *sync.Mutex {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lock, ok := d.locks[id]
+	if !ok {
+		lock = new(sync.Mutex)
+		d.locks[id] = lock
+	}
+	return lock
+}
+
+// flushEvery distributes the buffered lines of every stream
+// at the given interval, until the streamer is closed.
+func (d *distributed) flushEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+		d.mu.Lock()
+		var ids []int64
+		for id := range d.pending {
+			ids = append(ids, id)
+		}
+		d.mu.Unlock()
+		for _, id := range ids {
+			if err := d.flush(noContext, id); err != nil {
+				logrus.WithError(err).
+					WithField("id", id).
+					Warnln("livelog: cannot distribute log lines")
+			}
+		}
+	}
+}
+
+func (d *distributed) publish(ctx context.Context, op string, id int64, lines []*core.Line) error {
+	data, err := json.Marshal(&event{
+		Origin: d.origin,
+		Op:     op,
+		ID:     id,
+		Lines:  lines,
+	})
+	if err != nil {
+		return err
+	}
+	return d.broker.Publish(ctx, channel, data)
+}
+
+// receive applies stream operations received from other
+// replicas to the local streams. Operations that originate
+// from this replica were already applied and are ignored.
+func (d *distributed) receive(sub <-chan []byte) {
+	for data := range sub {
+		in := new(event)
+		if err := json.Unmarshal(data, in); err != nil {
+			logrus.WithError(err).Warnln("livelog: cannot decode event")
+			continue
+		}
+		if in.Origin == d.origin {
+			continue
+		}
+		switch in.Op {
+		case opCreate:
+			d.streamer.Create(noContext, in.ID)
+		case opDelete:
+			d.streamer.Delete(noContext, in.ID)
+		case opWrite:
+			for _, line := range in.Lines {
+				d.streamer.Write(noContext, in.ID, line)
+			}
+		}
+	}
+}
+
+var noContext = context.Background()
@@ -0,0 +1,231 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package livelog
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/broker"
+	"github.com/drone/drone/core"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDistributed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two replicas that share the same broker.
+	b := broker.NewMemory()
+	s1, err := NewDistributed(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := NewDistributed(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1.Create(ctx, 1)
+	s1.Write(ctx, 1, &core.Line{Number: 0, Message: "hello"})
+	s1.Write(ctx, 1, &core.Line{Number: 1, Message: "world"})
+
+	var tail <-chan *core.Line
+	var errc <-chan error
+	for i := 0; i < 100 && tail == nil; i++ {
+		tail, errc = s2.Tail(ctx, 1)
+		time.Sleep(time.Millisecond)
+	}
+	if tail == nil {
+		t.Fatalf("Want stream replicated")
+	}
+	for _, want := range []string{"hello", "world"} {
+		select {
+		case line := <-tail:
+			if got := line.Message; got != want {
+				t.Errorf("Want line %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Want line %q replicated", want)
+		}
+	}
+
+	if err := s1.Delete(ctx, 1); err != nil {
+		t.Error(err)
+	}
+	select {
+	case <-errc:
+	case <-time.After(time.Second):
+		t.Errorf("Want stream deleted on all replicas")
+	}
+	if got := len(s1.Info(ctx).Streams); got != 0 {
+		t.Errorf("Want stream removed, got %d streams", got)
+	}
+}
+
+func TestDistributed_WriteRemote(t *testing.T) {
+	ctx := context.Background()
+	b := broker.NewMemory()
+	s1, _ := NewDistributed(b)
+	s2, _ := NewDistributed(b)
+
+	// a runner may send log lines to a replica that has not
+	// yet received the stream. This must not be an error.
+	if err := s2.Write(ctx, 2, &core.Line{}); err != nil {
+		t.Error(err)
+	}
+	if err := s2.Delete(ctx, 2); err != nil {
+		t.Error(err)
+	}
+	if got := len(s1.Info(ctx).Streams); got != 0 {
+		t.Errorf("Want no streams, got %d", got)
+	}
+}
+
+// this test verifies that log lines are distributed in
+// batches instead of one broker message per line.
+func TestDistributed_Batch(t *testing.T) {
+	ctx := context.Background()
+	b := &countingBroker{Broker: broker.NewMemory()}
+	s, _ := NewDistributed(b)
+
+	s.Create(ctx, 3)
+	for i := 0; i < flushSize+1; i++ {
+		s.Write(ctx, 3, &core.Line{Number: i})
+	}
+	// the create operation and the first full batch.
+	if got, want := b.count(), 2; got != want {
+		t.Errorf("Want %d messages published, got %d", want, got)
+	}
+	// the remaining line is distributed before the delete.
+	s.Delete(ctx, 3)
+	if got, want := b.count(), 4; got != want {
+		t.Errorf("Want %d messages published, got %d", want, got)
+	}
+}
+
+// this test verifies that a delete operation is not published
+// while a concurrent flush is publishing the buffered lines of
+// the stream.
+func TestDistributed_FlushOrder(t *testing.T) {
+	ctx := context.Background()
+	b := &orderedBroker{
+		Broker:  broker.NewMemory(),
+		writing: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	v, _ := NewDistributed(b)
+	s := v.(*distributed)
+	defer s.Close()
+
+	s.Create(ctx, 4)
+	s.Write(ctx, 4, &core.Line{Number: 1})
+
+	// the flush takes the buffered lines and blocks while
+	// publishing them.
+	go s.flush(ctx, 4)
+	<-b.writing
+
+	deleted := make(chan struct{})
+	go func() {
+		s.Delete(ctx, 4)
+		close(deleted)
+	}()
+	select {
+	case <-deleted:
+		t.Errorf("Want delete blocked until the flush is published")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(b.release)
+	<-deleted
+
+	got, want := b.ops(), []string{opCreate, opWrite, opDelete}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that closing the streamer stops the
+// periodic flush.
+func TestDistributed_Close(t *testing.T) {
+	v, _ := NewDistributed(broker.NewMemory())
+	s := v.(*distributed)
+	s.Close()
+	s.Close()
+	select {
+	case <-s.done:
+	default:
+		t.Errorf("Want streamer closed")
+	}
+}
+
+// orderedBroker records the published operations, and blocks
+// publishing the first write operation until released.
+type orderedBroker struct {
+	broker.Broker
+
+	sync.Mutex
+	published []string
+	writing   chan struct{}
+	release   chan struct{}
+	blocked   bool
+}
+
+func (b *orderedBroker) Publish(ctx context.Context, channel string, data []byte) error {
+	in := new(event)
+	json.Unmarshal(data, in)
+	b.Lock()
+	block := in.Op == opWrite && !b.blocked
+	b.blocked = b.blocked || block
+	b.Unlock()
+	if block {
+		close(b.writing)
+		<-b.release
+	}
+	b.Lock()
+	b.published = append(b.published, in.Op)
+	b.Unlock()
+	return b.Broker.Publish(ctx, channel, data)
+}
+
+func (b *orderedBroker) ops() []string {
+	b.Lock()
+	defer b.Unlock()
+	return append([]string(nil), b.published...)
+}
+
+type countingBroker struct {
+	broker.Broker
+
+	sync.Mutex
+	n int
+}
+
+func (b *countingBroker) Publish(ctx context.Context, channel string, data []byte) error {
+	b.Lock()
+	b.n++
+	b.Unlock()
+	return b.Broker.Publish(ctx, channel, data)
+}
+
+func (b *countingBroker) count() int {
+	b.Lock()
+	defer b.Unlock()
+	return b.n
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/drone/drone/core (interfaces: Pubsub,Canceler,ConvertService,ValidateService,NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,GlobalSecretStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Transferer,Triggerer,Syncer,LogStream,WebhookSender,LicenseService,TemplateStore)
+// Source: github.com/drone/drone/core (interfaces: Pubsub,Canceler,ConvertService,ValidateService,NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,GlobalSecretStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Transferer,Triggerer,Syncer,LogStream,WebhookSender,LicenseService,TemplateStore,QuotaStore,RetentionStore,WebhookDeliveryStore,WebhookStore,SecretVersionStore,SecretUsageStore,AuditStore,AccessTokenStore,IdentityService,EnvironmentStore,RetryStore,ArtifactStore,ArtifactBlobStore,TestStore,Resetter)
 
 // Package mock is a generated GoMock package.
 package mock
@@ -464,6 +464,21 @@ func (m *MockCommitService) EXPECT() *MockCommitServiceMockRecorder {
 	return m.recorder
 }
 
+// CompareChanges mocks base method.
+func (m *MockCommitService) CompareChanges(arg0 context.Context, arg1 *core.User, arg2, arg3, arg4 string) ([]*core.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareChanges", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*core.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareChanges indicates an expected call of CompareChanges.
+func (mr *MockCommitServiceMockRecorder) CompareChanges(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareChanges", reflect.TypeOf((*MockCommitService)(nil).CompareChanges), arg0, arg1, arg2, arg3, arg4)
+}
+
 // Find mocks base method.
 func (m *MockCommitService) Find(arg0 context.Context, arg1 *core.User, arg2, arg3 string) (*core.Commit, error) {
 	m.ctrl.T.Helper()
@@ -855,6 +870,21 @@ func (mr *MockBuildStoreMockRecorder) LatestDeploys(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestDeploys", reflect.TypeOf((*MockBuildStore)(nil).LatestDeploys), arg0, arg1)
 }
 
+// LatestDeploysNamespace mocks base method.
+func (m *MockBuildStore) LatestDeploysNamespace(arg0 context.Context, arg1 string) ([]*core.Build, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestDeploysNamespace", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestDeploysNamespace indicates an expected call of LatestDeploysNamespace.
+func (mr *MockBuildStoreMockRecorder) LatestDeploysNamespace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestDeploysNamespace", reflect.TypeOf((*MockBuildStore)(nil).LatestDeploysNamespace), arg0, arg1)
+}
+
 // LatestPulls mocks base method.
 func (m *MockBuildStore) LatestPulls(arg0 context.Context, arg1 int64) ([]*core.Build, error) {
 	m.ctrl.T.Helper()
@@ -885,6 +915,36 @@ func (mr *MockBuildStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBuildStore)(nil).List), arg0, arg1, arg2, arg3)
 }
 
+// ListBefore mocks base method.
+func (m *MockBuildStore) ListBefore(arg0 context.Context, arg1, arg2 int64, arg3 int) ([]*core.Build, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBefore", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBefore indicates an expected call of ListBefore.
+func (mr *MockBuildStoreMockRecorder) ListBefore(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBefore", reflect.TypeOf((*MockBuildStore)(nil).ListBefore), arg0, arg1, arg2, arg3)
+}
+
+// ListDeploys mocks base method.
+func (m *MockBuildStore) ListDeploys(arg0 context.Context, arg1 int64, arg2 string, arg3, arg4 int) ([]*core.Build, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeploys", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeploys indicates an expected call of ListDeploys.
+func (mr *MockBuildStoreMockRecorder) ListDeploys(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploys", reflect.TypeOf((*MockBuildStore)(nil).ListDeploys), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ListRef mocks base method.
 func (m *MockBuildStore) ListRef(arg0 context.Context, arg1 int64, arg2 string, arg3, arg4 int) ([]*core.Build, error) {
 	m.ctrl.T.Helper()
@@ -1149,6 +1209,21 @@ func (mr *MockLogStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockLogStore)(nil).Find), arg0, arg1)
 }
 
+// Query mocks base method.
+func (m *MockLogStore) Query(arg0 context.Context, arg1 int64, arg2 *core.LogQuery) ([]*core.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockLogStoreMockRecorder) Query(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockLogStore)(nil).Query), arg0, arg1, arg2)
+}
+
 // Update mocks base method.
 func (m *MockLogStore) Update(arg0 context.Context, arg1 int64, arg2 io.Reader) error {
 	m.ctrl.T.Helper()
@@ -1702,6 +1777,20 @@ func (mr *MockStepStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStepStore)(nil).List), arg0, arg1)
 }
 
+// Purge mocks base method.
+func (m *MockStepStore) Purge(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockStepStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockStepStore)(nil).Purge), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockStepStore) Update(arg0 context.Context, arg1 *core.Step) error {
 	m.ctrl.T.Helper()
@@ -2878,3 +2967,1394 @@ func (mr *MockTemplateStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTemplateStore)(nil).Update), arg0, arg1)
 }
+
+// MockQuotaStore is a mock of QuotaStore interface.
+type MockQuotaStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaStoreMockRecorder
+}
+
+// MockQuotaStoreMockRecorder is the mock recorder for MockQuotaStore.
+type MockQuotaStoreMockRecorder struct {
+	mock *MockQuotaStore
+}
+
+// NewMockQuotaStore creates a new mock instance.
+func NewMockQuotaStore(ctrl *gomock.Controller) *MockQuotaStore {
+	mock := &MockQuotaStore{ctrl: ctrl}
+	mock.recorder = &MockQuotaStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaStore) EXPECT() *MockQuotaStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockQuotaStore) Create(arg0 context.Context, arg1 *core.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuotaStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuotaStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockQuotaStore) Delete(arg0 context.Context, arg1 *core.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuotaStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuotaStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockQuotaStore) Find(arg0 context.Context, arg1 int64) (*core.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockQuotaStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockQuotaStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method.
+func (m *MockQuotaStore) FindName(arg0 context.Context, arg1, arg2 string) (*core.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName.
+func (mr *MockQuotaStoreMockRecorder) FindName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockQuotaStore)(nil).FindName), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockQuotaStore) List(arg0 context.Context) ([]*core.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuotaStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuotaStore)(nil).List), arg0)
+}
+
+// Update mocks base method.
+func (m *MockQuotaStore) Update(arg0 context.Context, arg1 *core.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockQuotaStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuotaStore)(nil).Update), arg0, arg1)
+}
+
+// MockRetentionStore is a mock of RetentionStore interface.
+type MockRetentionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionStoreMockRecorder
+}
+
+// MockRetentionStoreMockRecorder is the mock recorder for MockRetentionStore.
+type MockRetentionStoreMockRecorder struct {
+	mock *MockRetentionStore
+}
+
+// NewMockRetentionStore creates a new mock instance.
+func NewMockRetentionStore(ctrl *gomock.Controller) *MockRetentionStore {
+	mock := &MockRetentionStore{ctrl: ctrl}
+	mock.recorder = &MockRetentionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionStore) EXPECT() *MockRetentionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRetentionStore) Create(arg0 context.Context, arg1 *core.Retention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRetentionStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRetentionStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockRetentionStore) Delete(arg0 context.Context, arg1 *core.Retention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRetentionStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRetentionStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockRetentionStore) Find(arg0 context.Context, arg1 int64) (*core.Retention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Retention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockRetentionStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRetentionStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method.
+func (m *MockRetentionStore) FindName(arg0 context.Context, arg1, arg2 string) (*core.Retention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Retention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName.
+func (mr *MockRetentionStoreMockRecorder) FindName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockRetentionStore)(nil).FindName), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockRetentionStore) List(arg0 context.Context) ([]*core.Retention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.Retention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRetentionStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRetentionStore)(nil).List), arg0)
+}
+
+// Update mocks base method.
+func (m *MockRetentionStore) Update(arg0 context.Context, arg1 *core.Retention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRetentionStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRetentionStore)(nil).Update), arg0, arg1)
+}
+
+// MockWebhookDeliveryStore is a mock of WebhookDeliveryStore interface.
+type MockWebhookDeliveryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryStoreMockRecorder
+}
+
+// MockWebhookDeliveryStoreMockRecorder is the mock recorder for MockWebhookDeliveryStore.
+type MockWebhookDeliveryStoreMockRecorder struct {
+	mock *MockWebhookDeliveryStore
+}
+
+// NewMockWebhookDeliveryStore creates a new mock instance.
+func NewMockWebhookDeliveryStore(ctrl *gomock.Controller) *MockWebhookDeliveryStore {
+	mock := &MockWebhookDeliveryStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryStore) EXPECT() *MockWebhookDeliveryStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockWebhookDeliveryStore) Claim(arg0 context.Context, arg1 *core.WebhookDelivery, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Claim(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Claim), arg0, arg1, arg2)
+}
+
+// Create mocks base method.
+func (m *MockWebhookDeliveryStore) Create(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockWebhookDeliveryStore) Find(arg0 context.Context, arg1 int64) (*core.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockWebhookDeliveryStore) List(arg0 context.Context, arg1 int) ([]*core.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookDeliveryStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).List), arg0, arg1)
+}
+
+// ListPending mocks base method.
+func (m *MockWebhookDeliveryStore) ListPending(arg0 context.Context, arg1 int64) ([]*core.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockWebhookDeliveryStoreMockRecorder) ListPending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).ListPending), arg0, arg1)
+}
+
+// ListWebhook mocks base method.
+func (m *MockWebhookDeliveryStore) ListWebhook(arg0 context.Context, arg1 int64, arg2 int) ([]*core.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhook", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhook indicates an expected call of ListWebhook.
+func (mr *MockWebhookDeliveryStoreMockRecorder) ListWebhook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhook", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).ListWebhook), arg0, arg1, arg2)
+}
+
+// Purge mocks base method.
+func (m *MockWebhookDeliveryStore) Purge(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Purge), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockWebhookDeliveryStore) Update(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Update), arg0, arg1)
+}
+
+// MockWebhookStore is a mock of WebhookStore interface.
+type MockWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStoreMockRecorder
+}
+
+// MockWebhookStoreMockRecorder is the mock recorder for MockWebhookStore.
+type MockWebhookStoreMockRecorder struct {
+	mock *MockWebhookStore
+}
+
+// NewMockWebhookStore creates a new mock instance.
+func NewMockWebhookStore(ctrl *gomock.Controller) *MockWebhookStore {
+	mock := &MockWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookStore) Create(arg0 context.Context, arg1 *core.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookStore) Delete(arg0 context.Context, arg1 *core.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockWebhookStore) Find(arg0 context.Context, arg1 int64) (*core.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockWebhookStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWebhookStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockWebhookStore) List(arg0 context.Context, arg1 int64) ([]*core.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookStore)(nil).List), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockWebhookStore) Update(arg0 context.Context, arg1 *core.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookStore)(nil).Update), arg0, arg1)
+}
+
+// MockSecretVersionStore is a mock of SecretVersionStore interface.
+type MockSecretVersionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretVersionStoreMockRecorder
+}
+
+// MockSecretVersionStoreMockRecorder is the mock recorder for MockSecretVersionStore.
+type MockSecretVersionStoreMockRecorder struct {
+	mock *MockSecretVersionStore
+}
+
+// NewMockSecretVersionStore creates a new mock instance.
+func NewMockSecretVersionStore(ctrl *gomock.Controller) *MockSecretVersionStore {
+	mock := &MockSecretVersionStore{ctrl: ctrl}
+	mock.recorder = &MockSecretVersionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretVersionStore) EXPECT() *MockSecretVersionStoreMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockSecretVersionStore) Find(arg0 context.Context, arg1 string, arg2, arg3 int64) (*core.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockSecretVersionStoreMockRecorder) Find(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockSecretVersionStore)(nil).Find), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method.
+func (m *MockSecretVersionStore) List(arg0 context.Context, arg1 string, arg2 int64) ([]*core.SecretVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.SecretVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSecretVersionStoreMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretVersionStore)(nil).List), arg0, arg1, arg2)
+}
+
+// MockSecretUsageStore is a mock of SecretUsageStore interface.
+type MockSecretUsageStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretUsageStoreMockRecorder
+}
+
+// MockSecretUsageStoreMockRecorder is the mock recorder for MockSecretUsageStore.
+type MockSecretUsageStoreMockRecorder struct {
+	mock *MockSecretUsageStore
+}
+
+// NewMockSecretUsageStore creates a new mock instance.
+func NewMockSecretUsageStore(ctrl *gomock.Controller) *MockSecretUsageStore {
+	mock := &MockSecretUsageStore{ctrl: ctrl}
+	mock.recorder = &MockSecretUsageStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretUsageStore) EXPECT() *MockSecretUsageStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSecretUsageStore) Create(arg0 context.Context, arg1 *core.SecretUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSecretUsageStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSecretUsageStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockSecretUsageStore) List(arg0 context.Context, arg1 int64) ([]*core.SecretUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.SecretUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSecretUsageStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretUsageStore)(nil).List), arg0, arg1)
+}
+
+// ListVersion mocks base method.
+func (m *MockSecretUsageStore) ListVersion(arg0 context.Context, arg1 string, arg2, arg3 int64) ([]*core.SecretUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.SecretUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersion indicates an expected call of ListVersion.
+func (mr *MockSecretUsageStoreMockRecorder) ListVersion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersion", reflect.TypeOf((*MockSecretUsageStore)(nil).ListVersion), arg0, arg1, arg2, arg3)
+}
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditStore) Create(arg0 context.Context, arg1 *core.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockAuditStore) List(arg0 context.Context, arg1 *core.AuditFilter) ([]*core.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditStore)(nil).List), arg0, arg1)
+}
+
+// MockAccessTokenStore is a mock of AccessTokenStore interface.
+type MockAccessTokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenStoreMockRecorder
+}
+
+// MockAccessTokenStoreMockRecorder is the mock recorder for MockAccessTokenStore.
+type MockAccessTokenStoreMockRecorder struct {
+	mock *MockAccessTokenStore
+}
+
+// NewMockAccessTokenStore creates a new mock instance.
+func NewMockAccessTokenStore(ctrl *gomock.Controller) *MockAccessTokenStore {
+	mock := &MockAccessTokenStore{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenStore) EXPECT() *MockAccessTokenStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccessTokenStore) Create(arg0 context.Context, arg1 *core.AccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokenStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessTokenStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockAccessTokenStore) Delete(arg0 context.Context, arg1 *core.AccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokenStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessTokenStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockAccessTokenStore) Find(arg0 context.Context, arg1 int64) (*core.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAccessTokenStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAccessTokenStore)(nil).Find), arg0, arg1)
+}
+
+// FindHash mocks base method.
+func (m *MockAccessTokenStore) FindHash(arg0 context.Context, arg1 string) (*core.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHash", arg0, arg1)
+	ret0, _ := ret[0].(*core.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHash indicates an expected call of FindHash.
+func (mr *MockAccessTokenStoreMockRecorder) FindHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHash", reflect.TypeOf((*MockAccessTokenStore)(nil).FindHash), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockAccessTokenStore) List(arg0 context.Context, arg1 int64) ([]*core.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccessTokenStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccessTokenStore)(nil).List), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockAccessTokenStore) Touch(arg0 context.Context, arg1 *core.AccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockAccessTokenStoreMockRecorder) Touch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockAccessTokenStore)(nil).Touch), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockAccessTokenStore) Update(arg0 context.Context, arg1 *core.AccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAccessTokenStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAccessTokenStore)(nil).Update), arg0, arg1)
+}
+
+// MockIdentityService is a mock of IdentityService interface.
+type MockIdentityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityServiceMockRecorder
+}
+
+// MockIdentityServiceMockRecorder is the mock recorder for MockIdentityService.
+type MockIdentityServiceMockRecorder struct {
+	mock *MockIdentityService
+}
+
+// NewMockIdentityService creates a new mock instance.
+func NewMockIdentityService(ctrl *gomock.Controller) *MockIdentityService {
+	mock := &MockIdentityService{ctrl: ctrl}
+	mock.recorder = &MockIdentityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityService) EXPECT() *MockIdentityServiceMockRecorder {
+	return m.recorder
+}
+
+// Issuer mocks base method.
+func (m *MockIdentityService) Issuer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer.
+func (mr *MockIdentityServiceMockRecorder) Issuer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*MockIdentityService)(nil).Issuer))
+}
+
+// Keys mocks base method.
+func (m *MockIdentityService) Keys(arg0 context.Context) ([]*core.JSONWebKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", arg0)
+	ret0, _ := ret[0].([]*core.JSONWebKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockIdentityServiceMockRecorder) Keys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockIdentityService)(nil).Keys), arg0)
+}
+
+// Token mocks base method.
+func (m *MockIdentityService) Token(arg0 context.Context, arg1 *core.IdentityArgs) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockIdentityServiceMockRecorder) Token(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockIdentityService)(nil).Token), arg0, arg1)
+}
+
+// MockEnvironmentStore is a mock of EnvironmentStore interface.
+type MockEnvironmentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnvironmentStoreMockRecorder
+}
+
+// MockEnvironmentStoreMockRecorder is the mock recorder for MockEnvironmentStore.
+type MockEnvironmentStoreMockRecorder struct {
+	mock *MockEnvironmentStore
+}
+
+// NewMockEnvironmentStore creates a new mock instance.
+func NewMockEnvironmentStore(ctrl *gomock.Controller) *MockEnvironmentStore {
+	mock := &MockEnvironmentStore{ctrl: ctrl}
+	mock.recorder = &MockEnvironmentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnvironmentStore) EXPECT() *MockEnvironmentStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEnvironmentStore) Create(arg0 context.Context, arg1 *core.Environment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEnvironmentStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEnvironmentStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockEnvironmentStore) Delete(arg0 context.Context, arg1 *core.Environment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEnvironmentStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEnvironmentStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockEnvironmentStore) Find(arg0 context.Context, arg1 int64) (*core.Environment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockEnvironmentStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockEnvironmentStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method.
+func (m *MockEnvironmentStore) FindName(arg0 context.Context, arg1 int64, arg2 string) (*core.Environment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName.
+func (mr *MockEnvironmentStoreMockRecorder) FindName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockEnvironmentStore)(nil).FindName), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockEnvironmentStore) List(arg0 context.Context, arg1 int64) ([]*core.Environment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockEnvironmentStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEnvironmentStore)(nil).List), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockEnvironmentStore) Update(arg0 context.Context, arg1 *core.Environment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEnvironmentStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEnvironmentStore)(nil).Update), arg0, arg1)
+}
+
+// MockRetryStore is a mock of RetryStore interface.
+type MockRetryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetryStoreMockRecorder
+}
+
+// MockRetryStoreMockRecorder is the mock recorder for MockRetryStore.
+type MockRetryStoreMockRecorder struct {
+	mock *MockRetryStore
+}
+
+// NewMockRetryStore creates a new mock instance.
+func NewMockRetryStore(ctrl *gomock.Controller) *MockRetryStore {
+	mock := &MockRetryStore{ctrl: ctrl}
+	mock.recorder = &MockRetryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetryStore) EXPECT() *MockRetryStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRetryStore) Create(arg0 context.Context, arg1 *core.Retry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRetryStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRetryStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockRetryStore) List(arg0 context.Context, arg1 int64) ([]*core.Retry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Retry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRetryStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRetryStore)(nil).List), arg0, arg1)
+}
+
+// ListFlaky mocks base method.
+func (m *MockRetryStore) ListFlaky(arg0 context.Context, arg1 int64) ([]*core.FlakyStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFlaky", arg0, arg1)
+	ret0, _ := ret[0].([]*core.FlakyStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlaky indicates an expected call of ListFlaky.
+func (mr *MockRetryStoreMockRecorder) ListFlaky(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlaky", reflect.TypeOf((*MockRetryStore)(nil).ListFlaky), arg0, arg1)
+}
+
+// Pass mocks base method.
+func (m *MockRetryStore) Pass(arg0 context.Context, arg1 int64, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pass", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pass indicates an expected call of Pass.
+func (mr *MockRetryStoreMockRecorder) Pass(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pass", reflect.TypeOf((*MockRetryStore)(nil).Pass), arg0, arg1, arg2)
+}
+
+// MockArtifactStore is a mock of ArtifactStore interface.
+type MockArtifactStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtifactStoreMockRecorder
+}
+
+// MockArtifactStoreMockRecorder is the mock recorder for MockArtifactStore.
+type MockArtifactStoreMockRecorder struct {
+	mock *MockArtifactStore
+}
+
+// NewMockArtifactStore creates a new mock instance.
+func NewMockArtifactStore(ctrl *gomock.Controller) *MockArtifactStore {
+	mock := &MockArtifactStore{ctrl: ctrl}
+	mock.recorder = &MockArtifactStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArtifactStore) EXPECT() *MockArtifactStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockArtifactStore) Create(arg0 context.Context, arg1 *core.Artifact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockArtifactStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArtifactStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockArtifactStore) Delete(arg0 context.Context, arg1 *core.Artifact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArtifactStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArtifactStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockArtifactStore) Find(arg0 context.Context, arg1 int64) (*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockArtifactStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArtifactStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method.
+func (m *MockArtifactStore) FindName(arg0 context.Context, arg1 int64, arg2 string) (*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName.
+func (mr *MockArtifactStoreMockRecorder) FindName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockArtifactStore)(nil).FindName), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockArtifactStore) List(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArtifactStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArtifactStore)(nil).List), arg0, arg1)
+}
+
+// ListBuild mocks base method.
+func (m *MockArtifactStore) ListBuild(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBuild", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBuild indicates an expected call of ListBuild.
+func (mr *MockArtifactStoreMockRecorder) ListBuild(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBuild", reflect.TypeOf((*MockArtifactStore)(nil).ListBuild), arg0, arg1)
+}
+
+// ListPurge mocks base method.
+func (m *MockArtifactStore) ListPurge(arg0 context.Context, arg1, arg2 int64) ([]*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPurge", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPurge indicates an expected call of ListPurge.
+func (mr *MockArtifactStoreMockRecorder) ListPurge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPurge", reflect.TypeOf((*MockArtifactStore)(nil).ListPurge), arg0, arg1, arg2)
+}
+
+// ListRepo mocks base method.
+func (m *MockArtifactStore) ListRepo(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRepo", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRepo indicates an expected call of ListRepo.
+func (mr *MockArtifactStoreMockRecorder) ListRepo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepo", reflect.TypeOf((*MockArtifactStore)(nil).ListRepo), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockArtifactStore) Update(arg0 context.Context, arg1 *core.Artifact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockArtifactStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArtifactStore)(nil).Update), arg0, arg1)
+}
+
+// MockArtifactBlobStore is a mock of ArtifactBlobStore interface.
+type MockArtifactBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtifactBlobStoreMockRecorder
+}
+
+// MockArtifactBlobStoreMockRecorder is the mock recorder for MockArtifactBlobStore.
+type MockArtifactBlobStoreMockRecorder struct {
+	mock *MockArtifactBlobStore
+}
+
+// NewMockArtifactBlobStore creates a new mock instance.
+func NewMockArtifactBlobStore(ctrl *gomock.Controller) *MockArtifactBlobStore {
+	mock := &MockArtifactBlobStore{ctrl: ctrl}
+	mock.recorder = &MockArtifactBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArtifactBlobStore) EXPECT() *MockArtifactBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockArtifactBlobStore) Create(arg0 context.Context, arg1 int64, arg2 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockArtifactBlobStoreMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArtifactBlobStore)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method.
+func (m *MockArtifactBlobStore) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockArtifactBlobStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArtifactBlobStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockArtifactBlobStore) Find(arg0 context.Context, arg1 int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockArtifactBlobStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArtifactBlobStore)(nil).Find), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockArtifactBlobStore) Update(arg0 context.Context, arg1 int64, arg2 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockArtifactBlobStoreMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArtifactBlobStore)(nil).Update), arg0, arg1, arg2)
+}
+
+// MockTestStore is a mock of TestStore interface.
+type MockTestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTestStoreMockRecorder
+}
+
+// MockTestStoreMockRecorder is the mock recorder for MockTestStore.
+type MockTestStoreMockRecorder struct {
+	mock *MockTestStore
+}
+
+// NewMockTestStore creates a new mock instance.
+func NewMockTestStore(ctrl *gomock.Controller) *MockTestStore {
+	mock := &MockTestStore{ctrl: ctrl}
+	mock.recorder = &MockTestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTestStore) EXPECT() *MockTestStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTestStore) Create(arg0 context.Context, arg1 []*core.TestCase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTestStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTestStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockTestStore) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTestStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTestStore)(nil).Delete), arg0, arg1)
+}
+
+// History mocks base method.
+func (m *MockTestStore) History(arg0 context.Context, arg1 int64, arg2, arg3, arg4 string, arg5 int) ([]*core.TestHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]*core.TestHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockTestStoreMockRecorder) History(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockTestStore)(nil).History), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// List mocks base method.
+func (m *MockTestStore) List(arg0 context.Context, arg1 int64) ([]*core.TestCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTestStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTestStore)(nil).List), arg0, arg1)
+}
+
+// ListFailed mocks base method.
+func (m *MockTestStore) ListFailed(arg0 context.Context, arg1 int64) ([]*core.TestCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailed", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailed indicates an expected call of ListFailed.
+func (mr *MockTestStoreMockRecorder) ListFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailed", reflect.TypeOf((*MockTestStore)(nil).ListFailed), arg0, arg1)
+}
+
+// Summary mocks base method.
+func (m *MockTestStore) Summary(arg0 context.Context, arg1 int64) (*core.TestSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", arg0, arg1)
+	ret0, _ := ret[0].(*core.TestSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockTestStoreMockRecorder) Summary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockTestStore)(nil).Summary), arg0, arg1)
+}
+
+// MockResetter is a mock of Resetter interface.
+type MockResetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockResetterMockRecorder
+}
+
+// MockResetterMockRecorder is the mock recorder for MockResetter.
+type MockResetterMockRecorder struct {
+	mock *MockResetter
+}
+
+// NewMockResetter creates a new mock instance.
+func NewMockResetter(ctrl *gomock.Controller) *MockResetter {
+	mock := &MockResetter{ctrl: ctrl}
+	mock.recorder = &MockResetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResetter) EXPECT() *MockResetterMockRecorder {
+	return m.recorder
+}
+
+// Reset mocks base method.
+func (m *MockResetter) Reset(arg0 context.Context, arg1 *core.Stage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockResetterMockRecorder) Reset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockResetter)(nil).Reset), arg0, arg1)
+}
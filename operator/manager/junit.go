@@ -0,0 +1,135 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+// maximum length of the test suite and test name, and the
+// maximum length of the failure message.
+const (
+	maxTestName    = 500
+	maxTestMessage = 2000
+)
+
+// MaxTestReport is the maximum size of an uploaded junit test
+// report, in bytes.
+const MaxTestReport = 10 << 20
+
+type (
+	// junitSuite represents a junit testsuite element. The
+	// root testsuites element, when present, is decoded as a
+	// suite with nested suites and no test cases.
+	junitSuite struct {
+		Name   string       `xml:"name,attr"`
+		Suites []junitSuite `xml:"testsuite"`
+		Cases  []junitCase  `xml:"testcase"`
+	}
+
+	// junitCase represents a junit testcase element.
+	junitCase struct {
+		Name      string       `xml:"name,attr"`
+		Classname string       `xml:"classname,attr"`
+		Time      string       `xml:"time,attr"`
+		Failure   *junitResult `xml:"failure"`
+		Error     *junitResult `xml:"error"`
+		Skipped   *junitResult `xml:"skipped"`
+	}
+
+	// junitResult represents a junit failure, error or
+	// skipped element.
+	junitResult struct {
+		Message string `xml:"message,attr"`
+		Body    string `xml:",chardata"`
+	}
+)
+
+// parseJUnit parses the junit or xunit xml report and returns
+// the list of test cases.
+func parseJUnit(r io.Reader) ([]*core.TestCase, error) {
+	root := junitSuite{}
+	if err := xml.NewDecoder(io.LimitReader(r, MaxTestReport)).Decode(&root); err != nil {
+		return nil, err
+	}
+	return appendSuite(nil, root), nil
+}
+
+// helper function appends the test cases of the suite, and
+// its nested suites, to the list.
+func appendSuite(tests []*core.TestCase, suite junitSuite) []*core.TestCase {
+	for _, c := range suite.Cases {
+		test := &core.TestCase{
+			Suite:    truncate(c.Classname, maxTestName),
+			Name:     truncate(c.Name, maxTestName),
+			Status:   core.StatusPassing,
+			Duration: parseDuration(c.Time),
+		}
+		if test.Suite == "" {
+			test.Suite = truncate(suite.Name, maxTestName)
+		}
+		switch {
+		case c.Error != nil:
+			test.Status = core.StatusError
+			test.Message = truncate(c.Error.message(), maxTestMessage)
+		case c.Failure != nil:
+			test.Status = core.StatusFailing
+			test.Message = truncate(c.Failure.message(), maxTestMessage)
+		case c.Skipped != nil:
+			test.Status = core.StatusSkipped
+			test.Message = truncate(c.Skipped.message(), maxTestMessage)
+		}
+		tests = append(tests, test)
+	}
+	for _, nested := range suite.Suites {
+		tests = appendSuite(tests, nested)
+	}
+	return tests
+}
+
+// message returns the result message attribute, falling
+// back to the element text.
+func (r *junitResult) message() string {
+	if s := strings.TrimSpace(r.Message); s != "" {
+		return s
+	}
+	return strings.TrimSpace(r.Body)
+}
+
+// helper function parses the junit time attribute, in
+// seconds, and returns the duration in milliseconds.
+func parseDuration(s string) int64 {
+	s = strings.Replace(s, ",", "", -1)
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return int64(f * 1000)
+}
+
+// helper function truncates the string to a maximum
+// number of characters.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
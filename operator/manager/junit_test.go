@@ -0,0 +1,70 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseJUnit(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="pkg/core" tests="4">
+    <testcase classname="core" name="TestPass" time="0.015"></testcase>
+    <testcase classname="core" name="TestFail" time="1.5">
+      <failure message="expected true">core_test.go:12: expected true</failure>
+    </testcase>
+    <testcase name="TestError" time="0">
+      <error>panic: runtime error</error>
+    </testcase>
+    <testcase classname="core" name="TestSkip">
+      <skipped/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+	got, err := parseJUnit(strings.NewReader(report))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []*core.TestCase{
+		{Suite: "core", Name: "TestPass", Status: core.StatusPassing, Duration: 15},
+		{Suite: "core", Name: "TestFail", Status: core.StatusFailing, Duration: 1500, Message: "expected true"},
+		{Suite: "pkg/core", Name: "TestError", Status: core.StatusError, Message: "panic: runtime error"},
+		{Suite: "core", Name: "TestSkip", Status: core.StatusSkipped},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseJUnit_Suite(t *testing.T) {
+	report := `<testsuite name="suite"><testcase name="TestPass" time="1,000.5"/></testsuite>`
+
+	got, err := parseJUnit(strings.NewReader(report))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []*core.TestCase{
+		{Suite: "suite", Name: "TestPass", Status: core.StatusPassing, Duration: 1000500},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseJUnit_Invalid(t *testing.T) {
+	_, err := parseJUnit(strings.NewReader("{}"))
+	if err == nil {
+		t.Errorf("Expect error parsing invalid report")
+	}
+}
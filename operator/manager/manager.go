@@ -42,6 +42,7 @@ type (
 		Config  *core.File       `json:"config"`
 		Secrets []*core.Secret   `json:"secrets"`
 		System  *core.System     `json:"system"`
+		IDToken string           `json:"id_token,omitempty"`
 	}
 
 	// BuildManager encapsulates complex build operations and provides
@@ -82,6 +83,12 @@ type (
 
 		// UploadBytes uploads the full logs
 		UploadBytes(ctx context.Context, step int64, b []byte) error
+
+		// UploadArtifact uploads a build artifact
+		UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error
+
+		// UploadTests uploads a junit test report
+		UploadTests(ctx context.Context, step int64, r io.Reader) error
 	}
 
 	// Request provides filters when requesting a pending
@@ -100,14 +107,19 @@ type (
 
 // New returns a new Manager.
 func New(
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
 	builds core.BuildStore,
 	config core.ConfigService,
 	converter core.ConvertService,
 	events core.Pubsub,
+	identity core.IdentityService,
 	logs core.LogStore,
 	logz core.LogStream,
 	netrcs core.NetrcService,
 	repos core.RepositoryStore,
+	resetter core.Resetter,
+	retries core.RetryStore,
 	scheduler core.Scheduler,
 	secrets core.SecretStore,
 	globals core.GlobalSecretStore,
@@ -115,26 +127,35 @@ func New(
 	stages core.StageStore,
 	steps core.StepStore,
 	system *core.System,
+	tests core.TestStore,
 	users core.UserStore,
+	usage core.SecretUsageStore,
 	webhook core.WebhookSender,
 ) BuildManager {
 	return &Manager{
+		Artifacts: artifacts,
+		Blobs:     blobs,
 		Builds:    builds,
 		Config:    config,
 		Converter: converter,
 		Events:    events,
 		Globals:   globals,
+		Identity:  identity,
 		Logs:      logs,
 		Logz:      logz,
 		Netrcs:    netrcs,
 		Repos:     repos,
+		Resetter:  resetter,
+		Retries:   retries,
 		Scheduler: scheduler,
 		Secrets:   secrets,
 		Status:    status,
 		Stages:    stages,
 		Steps:     steps,
 		System:    system,
+		Tests:     tests,
 		Users:     users,
+		Usage:     usage,
 		Webhook:   webhook,
 	}
 }
@@ -142,22 +163,29 @@ func New(
 // Manager provides a simplified interface to the build runner so that it
 // can more easily interact with the server.
 type Manager struct {
+	Artifacts core.ArtifactStore
+	Blobs     core.ArtifactBlobStore
 	Builds    core.BuildStore
 	Config    core.ConfigService
 	Converter core.ConvertService
 	Events    core.Pubsub
 	Globals   core.GlobalSecretStore
+	Identity  core.IdentityService
 	Logs      core.LogStore
 	Logz      core.LogStream
 	Netrcs    core.NetrcService
 	Repos     core.RepositoryStore
+	Resetter  core.Resetter
+	Retries   core.RetryStore
 	Scheduler core.Scheduler
 	Secrets   core.SecretStore
 	Status    core.StatusService
 	Stages    core.StageStore
 	Steps     core.StepStore
 	System    *core.System
+	Tests     core.TestStore
 	Users     core.UserStore
+	Usage     core.SecretUsageStore
 	Webhook   core.WebhookSender
 }
 
@@ -330,6 +358,9 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 			build.Event == core.EventPullRequest {
 			continue
 		}
+		if !matchBuild(secret, repo, build) {
+			continue
+		}
 		secrets = append(secrets, secret)
 	}
 	for _, secret := range tmpGlobalSecrets {
@@ -337,8 +368,32 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 			build.Event == core.EventPullRequest {
 			continue
 		}
+		if !matchBuild(secret, repo, build) {
+			continue
+		}
 		secrets = append(secrets, secret)
 	}
+	// secrets restricted to specific images are removed if
+	// they are referenced by a step using any other image.
+	secrets = filterImages(secrets, config.Data, stage.Name)
+
+	// record the secret versions exposed to the stage so that
+	// builds can be audited when a secret is rotated. Errors are
+	// logged but do not prevent the build from executing.
+	err = m.recordSecrets(ctx, build, stage, config.Data, secrets)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot record secret usage")
+	}
+
+	// mint a short-lived identity token for the stage. Errors
+	// are logged but do not prevent the build from executing.
+	token, err := m.identityToken(ctx, repo, build, stage)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot create identity token")
+	}
+
 	return &Context{
 		Repo:    repo,
 		Build:   build,
@@ -346,6 +401,7 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 		Secrets: secrets,
 		System:  m.System,
 		Config:  &core.File{Data: []byte(config.Data)},
+		IDToken: token,
 	}, nil
 }
 
@@ -432,6 +488,8 @@ func (m *Manager) AfterAll(ctx context.Context, stage *core.Stage) error {
 		Events:    m.Events,
 		Logs:      m.Logz,
 		Repos:     m.Repos,
+		Resetter:  m.Resetter,
+		Retries:   m.Retries,
 		Scheduler: m.Scheduler,
 		Steps:     m.Steps,
 		Stages:    m.Stages,
@@ -535,3 +593,125 @@ func (m *Manager) UploadBytes(ctx context.Context, step int64, data []byte) erro
 	}
 	return err
 }
+
+// UploadArtifact uploads a build artifact. If the stage already
+// contains an artifact with the same name, the artifact contents
+// are replaced.
+func (m *Manager) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	logger := logrus.WithField("step-id", step).WithField("artifact", name)
+
+	s, err := m.Steps.Find(noContext, step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, s.StageID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find stage")
+		return err
+	}
+
+	artifact, err := m.Artifacts.FindName(noContext, stage.ID, name)
+	exists := err == nil
+	if !exists {
+		artifact = &core.Artifact{
+			RepoID:  stage.RepoID,
+			BuildID: stage.BuildID,
+			StageID: stage.ID,
+			Name:    name,
+			Created: time.Now().Unix(),
+		}
+		if err := artifact.Validate(); err != nil {
+			logger.WithError(err).Warnln("manager: invalid artifact")
+			return err
+		}
+		err = m.Artifacts.Create(noContext, artifact)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot create artifact")
+			return err
+		}
+	}
+
+	counter := &countingReader{r: r}
+	if exists {
+		err = m.Blobs.Update(ctx, artifact.ID, counter)
+	} else {
+		err = m.Blobs.Create(ctx, artifact.ID, counter)
+	}
+	if err != nil {
+		// the contents may be partially written, for example
+		// if the upload exceeds the maximum size, and the
+		// artifact is therefore removed.
+		logger.WithError(err).Warnln("manager: cannot upload artifact")
+		m.Blobs.Delete(noContext, artifact.ID)
+		m.Artifacts.Delete(noContext, artifact)
+		return err
+	}
+
+	artifact.StepID = s.ID
+	artifact.Size = counter.n
+	artifact.Updated = time.Now().Unix()
+	err = m.Artifacts.Update(noContext, artifact)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot update artifact")
+	}
+	return err
+}
+
+// UploadTests uploads a junit test report. The test cases are
+// appended to those previously reported by the step, since a
+// step may upload a report per package.
+func (m *Manager) UploadTests(ctx context.Context, step int64, r io.Reader) error {
+	logger := logrus.WithField("step-id", step)
+
+	s, err := m.Steps.Find(noContext, step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, s.StageID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find stage")
+		return err
+	}
+
+	tests, err := parseJUnit(r)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot parse test report")
+		return err
+	}
+	now := time.Now().Unix()
+	for _, test := range tests {
+		test.RepoID = stage.RepoID
+		test.BuildID = stage.BuildID
+		test.StageID = stage.ID
+		test.StepID = s.ID
+		test.Created = now
+	}
+	err = m.Tests.Create(noContext, tests)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot persist test report")
+	}
+	return err
+}
+
+// identityToken returns a short-lived identity token for the
+// stage. A token is not minted for pull requests from a fork,
+// since trust policies keyed on the repository would otherwise
+// trust code from the fork.
+func (m *Manager) identityToken(ctx context.Context, repo *core.Repository, build *core.Build, stage *core.Stage) (string, error) {
+	if isFork(repo, build) {
+		return "", nil
+	}
+	return m.Identity.Token(ctx, &core.IdentityArgs{
+		Repo:  repo,
+		Build: build,
+		Stage: stage,
+	})
+}
+
+// helper function returns true if the build is a pull request
+// from a fork of the repository.
+func isFork(repo *core.Repository, build *core.Build) bool {
+	return build.Fork != "" && build.Fork != repo.Slug
+}
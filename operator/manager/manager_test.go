@@ -5,11 +5,51 @@
 package manager
 
 import (
-	"io/ioutil"
+	"context"
+	"testing"
 
-	"github.com/sirupsen/logrus"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
 )
 
-func init() {
-	logrus.SetOutput(ioutil.Discard)
+func TestIdentityToken(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{Event: core.EventPullRequest, Fork: "octocat/hello-world"}
+	stage := &core.Stage{Name: "default"}
+
+	identity := mock.NewMockIdentityService(controller)
+	identity.EXPECT().Token(gomock.Any(), &core.IdentityArgs{Repo: repo, Build: build, Stage: stage}).Return("token", nil)
+
+	m := &Manager{Identity: identity}
+	token, err := m.identityToken(context.Background(), repo, build, stage)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := token, "token"; got != want {
+		t.Errorf("Want token %q, got %q", want, got)
+	}
+}
+
+// this test verifies that an identity token is not minted for
+// a pull request from a fork.
+func TestIdentityToken_Fork(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{Event: core.EventPullRequest, Fork: "spaceghost/hello-world"}
+
+	m := &Manager{Identity: mock.NewMockIdentityService(controller)}
+	token, err := m.identityToken(context.Background(), repo, build, &core.Stage{})
+	if err != nil {
+		t.Error(err)
+	}
+	if token != "" {
+		t.Errorf("Want no token minted for a fork")
+	}
 }
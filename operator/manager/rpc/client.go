@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -208,6 +209,16 @@ func (s *Client) UploadBytes(ctx context.Context, step int64, data []byte) error
 	return s.upload(noContext, endpoint, data)
 }
 
+func (s *Client) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	endpoint := "/rpc/v1/artifact?id=" + fmt.Sprint(step) + "&name=" + url.QueryEscape(name)
+	return s.upload(noContext, endpoint, r)
+}
+
+func (s *Client) UploadTests(ctx context.Context, step int64, r io.Reader) error {
+	endpoint := "/rpc/v1/tests?id=" + fmt.Sprint(step)
+	return s.upload(noContext, endpoint, r)
+}
+
 func (s *Client) send(ctx context.Context, path string, in, out interface{}) error {
 	// Source a buffer from a pool. The agent may generate a
 	// large number of small requests for log entries. This will
@@ -28,14 +28,17 @@ var noContext = context.Background()
 type Server struct {
 	manager manager.BuildManager
 	secret  string
+	maxSize int64
 }
 
 // NewServer returns a new rpc server that enables remote
 // interaction with the build controller using the http transport.
-func NewServer(manager manager.BuildManager, secret string) *Server {
+// The maxSize limits the size of uploaded artifacts.
+func NewServer(manager manager.BuildManager, secret string, maxSize int64) *Server {
 	return &Server{
 		manager: manager,
 		secret:  secret,
+		maxSize: maxSize,
 	}
 }
 
@@ -71,6 +74,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.handleWatch(w, r)
 	case "/rpc/v1/upload":
 		s.handleUpload(w, r)
+	case "/rpc/v1/artifact":
+		s.handleArtifact(w, r)
+	case "/rpc/v1/tests":
+		s.handleTests(w, r)
 	default:
 		w.WriteHeader(404)
 	}
@@ -246,6 +253,44 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if !limitBody(w, r, s.maxSize) {
+		return
+	}
+	err = s.manager.UploadArtifact(ctx, id, r.FormValue("name"), r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleTests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if !limitBody(w, r, manager.MaxTestReport) {
+		return
+	}
+	err = s.manager.UploadTests(ctx, id, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
@@ -284,3 +329,21 @@ func writeError(w http.ResponseWriter, err error) {
 	}
 	io.WriteString(w, err.Error())
 }
+
+// limit the request body to the maximum size. Requests that
+// declare a larger body are rejected immediately, and the body
+// of all other requests is limited while it is streamed, since
+// the content length may be unknown. A zero size disables the
+// limit.
+func limitBody(w http.ResponseWriter, r *http.Request, size int64) bool {
+	if size <= 0 {
+		return true
+	}
+	if r.ContentLength > size {
+		w.WriteHeader(http.StatusRequestEntityTooLarge) // should fail
+		io.WriteString(w, "request body too large")
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, size)
+	return true
+}
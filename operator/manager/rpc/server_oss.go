@@ -33,7 +33,7 @@ type Server struct {
 }
 
 // NewServer returns a no-op rpc server.
-func NewServer(manager.BuildManager, string) *Server {
+func NewServer(manager.BuildManager, string, int64) *Server {
 	return &Server{}
 }
 
@@ -97,5 +97,15 @@ func (Server) UploadBytes(ctx context.Context, step int64, b []byte) error {
 	return errors.New("not implemented")
 }
 
+// UploadArtifact uploads a build artifact
+func (Server) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	return errors.New("not implemented")
+}
+
+// UploadTests uploads a junit test report
+func (Server) UploadTests(ctx context.Context, step int64, r io.Reader) error {
+	return errors.New("not implemented")
+}
+
 // ServeHTTP is an empty handler.
 func (Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
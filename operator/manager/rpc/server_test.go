@@ -5,3 +5,39 @@
 // +build !oss
 
 package rpc
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader("hello world"))
+	if limitBody(w, r, 5) {
+		t.Errorf("Want request rejected when the content length exceeds the limit")
+	}
+	if got, want := w.Code, 413; got != want {
+		t.Errorf("Want status code %d, got %d", want, got)
+	}
+
+	// the content length is unknown when the body is streamed,
+	// and the limit is enforced while reading the body.
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/", strings.NewReader("hello world"))
+	r.ContentLength = -1
+	if !limitBody(w, r, 5) {
+		t.Errorf("Want request accepted when the content length is unknown")
+	}
+	if _, err := ioutil.ReadAll(r.Body); err == nil {
+		t.Errorf("Want error reading a body that exceeds the limit")
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/", strings.NewReader("hello world"))
+	if !limitBody(w, r, 0) {
+		t.Errorf("Want request accepted when the limit is disabled")
+	}
+}
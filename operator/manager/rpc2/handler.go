@@ -270,6 +270,51 @@ func HandleLogUpload(m manager.BuildManager) http.HandlerFunc {
 	}
 }
 
+// HandleArtifactUpload returns an http.HandlerFunc that accepts
+// an http.Request to upload and persist a build artifact for a
+// pipeline step.
+//
+// POST /rpc/v2/step/{step}/artifacts/upload?name={name}
+func HandleArtifactUpload(m manager.BuildManager, maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		step, _ := strconv.ParseInt(
+			chi.URLParam(r, "step"), 10, 64)
+
+		if !limitBody(w, r, maxSize) {
+			return
+		}
+
+		err := m.UploadArtifact(noContext, step, r.FormValue("name"), r.Body)
+		if err != nil {
+			writeError(w, err)
+		} else {
+			writeOK(w)
+		}
+	}
+}
+
+// HandleTestUpload returns an http.HandlerFunc that accepts
+// an http.Request to upload and persist a junit test report
+// for a pipeline step.
+//
+// POST /rpc/v2/step/{step}/tests/upload
+func HandleTestUpload(m manager.BuildManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		step, _ := strconv.ParseInt(
+			chi.URLParam(r, "step"), 10, 64)
+
+		if !limitBody(w, r, manager.MaxTestReport) {
+			return
+		}
+		err := m.UploadTests(noContext, step, r.Body)
+		if err != nil {
+			writeError(w, err)
+		} else {
+			writeOK(w)
+		}
+	}
+}
+
 // write a 200 Status OK to the response body.
 func writeJSON(w http.ResponseWriter, v interface{}) {
 	json.NewEncoder(w).Encode(v)
@@ -293,3 +338,21 @@ func writeError(w http.ResponseWriter, err error) {
 	}
 	io.WriteString(w, err.Error())
 }
+
+// limit the request body to the maximum size. Requests that
+// declare a larger body are rejected immediately, and the body
+// of all other requests is limited while it is streamed, since
+// the content length may be unknown. A zero size disables the
+// limit.
+func limitBody(w http.ResponseWriter, r *http.Request, size int64) bool {
+	if size <= 0 {
+		return true
+	}
+	if r.ContentLength > size {
+		w.WriteHeader(http.StatusRequestEntityTooLarge) // should fail
+		io.WriteString(w, "request body too large")
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, size)
+	return true
+}
@@ -21,7 +21,8 @@ type Server http.Handler
 
 // NewServer returns a new rpc server that enables remote
 // interaction with the build controller using the http transport.
-func NewServer(manager manager.BuildManager, secret string) Server {
+// The maxSize limits the size of uploaded artifacts.
+func NewServer(manager manager.BuildManager, secret string, maxSize int64) Server {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.NoCache)
@@ -37,6 +38,8 @@ func NewServer(manager manager.BuildManager, secret string) Server {
 	r.Post("/build/{build}/watch", HandleWatch(manager))
 	r.Post("/step/{step}/logs/batch", HandleLogBatch(manager))
 	r.Post("/step/{step}/logs/upload", HandleLogUpload(manager))
+	r.Post("/step/{step}/artifacts/upload", HandleArtifactUpload(manager, maxSize))
+	r.Post("/step/{step}/tests/upload", HandleTestUpload(manager))
 	return Server(r)
 }
 
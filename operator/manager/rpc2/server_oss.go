@@ -28,6 +28,6 @@ type Server http.Handler
 
 // NewServer returns a new rpc server that enables remote
 // interaction with the build controller using the http transport.
-func NewServer(manager manager.BuildManager, secret string) Server {
+func NewServer(manager manager.BuildManager, secret string, maxSize int64) Server {
 	return Server(http.NotFoundHandler())
 }
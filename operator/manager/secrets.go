@@ -0,0 +1,259 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/compiler/image"
+	"github.com/drone/drone/core"
+)
+
+// helper function records the secret versions consumed by the
+// build stage.
+func (m *Manager) recordSecrets(ctx context.Context, build *core.Build, stage *core.Stage, config string, secrets []*core.Secret) error {
+	if m.Usage == nil {
+		return nil
+	}
+	// if the configuration cannot be parsed we cannot determine
+	// which secrets are referenced, and conservatively record all
+	// secrets exposed to the stage.
+	used := secrets
+	if names, err := secretNames(config, stage.Name); err == nil {
+		used = filterSecrets(secrets, names)
+	}
+	now := time.Now().Unix()
+	for _, secret := range used {
+		kind := core.SecretRepository
+		if secret.RepoID == 0 {
+			kind = core.SecretOrganization
+		}
+		err := m.Usage.Create(ctx, &core.SecretUsage{
+			RepoID:      build.RepoID,
+			BuildID:     build.ID,
+			BuildNumber: build.Number,
+			StageID:     stage.ID,
+			Kind:        kind,
+			SecretID:    secret.ID,
+			Name:        secret.Name,
+			Version:     secret.Version,
+			Created:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helper function returns the names of the secrets referenced
+// by the named pipeline.
+func secretNames(config, pipeline string) ([]string, error) {
+	v, err := findPipeline(config, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if v == nil {
+		return names, nil
+	}
+	names = append(names, v.PullSecrets...)
+	for _, container := range append(v.Steps, v.Services...) {
+		names = append(names, containerSecrets(container)...)
+	}
+	return names, nil
+}
+
+// helper function returns the named pipeline from the
+// configuration, or nil if the pipeline does not exist.
+func findPipeline(config, name string) (*yaml.Pipeline, error) {
+	manifest, err := yaml.ParseString(config)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range manifest.Resources {
+		v, ok := resource.(*yaml.Pipeline)
+		if ok && v.Name == name {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// helper function returns the names of the secrets referenced
+// by the container environment and settings.
+func containerSecrets(container *yaml.Container) []string {
+	var names []string
+	for _, env := range container.Environment {
+		if env != nil && env.Secret != "" {
+			names = append(names, env.Secret)
+		}
+	}
+	for _, param := range container.Settings {
+		if param != nil && param.Secret != "" {
+			names = append(names, param.Secret)
+		}
+	}
+	return names
+}
+
+// helper function returns true if the secret branch, ref and
+// event restrictions allow the secret to be exposed to the
+// build. Pull requests are matched against the source branch,
+// and pull requests from a fork never match a branch
+// restriction, since the fork controls its branch names.
+func matchBuild(secret *core.Secret, repo *core.Repository, build *core.Build) bool {
+	if len(secret.Events) != 0 && !matchEvent(secret.Events, build.Event) {
+		return false
+	}
+	if len(secret.Branches) != 0 {
+		branch := build.Target
+		if build.Event == core.EventPullRequest {
+			if isFork(repo, build) {
+				return false
+			}
+			branch = build.Source
+		}
+		if !matchPattern(secret.Branches, branch) {
+			return false
+		}
+	}
+	if len(secret.Refs) != 0 && !matchPattern(secret.Refs, build.Ref) {
+		return false
+	}
+	return true
+}
+
+// helper function removes secrets with image restrictions that
+// are referenced by pipeline steps using an image that is not
+// allowed, or by pipeline steps that override the commands or
+// entrypoint of the image. If the configuration cannot be
+// parsed, all secrets with image restrictions are removed.
+func filterImages(secrets []*core.Secret, config, pipeline string) []*core.Secret {
+	var containers map[string][]*yaml.Container
+	var parsed bool
+	var out []*core.Secret
+	for _, secret := range secrets {
+		if len(secret.Images) == 0 {
+			out = append(out, secret)
+			continue
+		}
+		if !parsed {
+			containers, parsed = secretContainers(config, pipeline), true
+		}
+		if containers == nil {
+			continue
+		}
+		allowed := true
+		for _, container := range containers[strings.ToLower(secret.Name)] {
+			if overridesImage(container) || !matchImage(secret.Images, container.Image) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			out = append(out, secret)
+		}
+	}
+	return out
+}
+
+// helper function returns the pipeline steps that reference
+// each secret, keyed by lowercase secret name. It returns nil
+// if the configuration cannot be parsed.
+func secretContainers(config, pipeline string) map[string][]*yaml.Container {
+	v, err := findPipeline(config, pipeline)
+	if err != nil {
+		return nil
+	}
+	containers := map[string][]*yaml.Container{}
+	if v == nil {
+		return containers
+	}
+	for _, container := range append(v.Steps, v.Services...) {
+		for _, name := range containerSecrets(container) {
+			name = strings.ToLower(name)
+			containers[name] = append(containers[name], container)
+		}
+	}
+	return containers
+}
+
+// helper function returns true if the pipeline step overrides
+// the commands or entrypoint of the image, in which case the
+// step does not run the code of the image and cannot receive
+// secrets restricted to the image.
+func overridesImage(container *yaml.Container) bool {
+	return len(container.Commands) != 0 ||
+		len(container.Command) != 0 ||
+		len(container.Entrypoint) != 0
+}
+
+// helper function returns true if the event matches an event
+// in the list.
+func matchEvent(events []string, event string) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the value matches a glob
+// pattern in the list.
+func matchPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the image matches an image
+// in the list. The image tag is ignored, and the list may
+// include glob patterns.
+func matchImage(patterns []string, name string) bool {
+	if image.Match(name, patterns...) {
+		return true
+	}
+	return matchPattern(patterns, image.Trim(name))
+}
+
+// helper function returns the secrets matching the names. The
+// first secret with a matching name is selected, consistent with
+// how secrets are resolved by the runner.
+func filterSecrets(secrets []*core.Secret, names []string) []*core.Secret {
+	var out []*core.Secret
+	seen := map[*core.Secret]struct{}{}
+	for _, name := range names {
+		for _, secret := range secrets {
+			if !strings.EqualFold(secret.Name, name) {
+				continue
+			}
+			if _, ok := seen[secret]; !ok {
+				seen[secret] = struct{}{}
+				out = append(out, secret)
+			}
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testSecretConfig = `
+kind: pipeline
+name: default
+
+steps:
+- name: test
+  image: golang
+  environment:
+    TOKEN:
+      from_secret: token
+- name: publish
+  image: plugins/docker
+  settings:
+    password:
+      from_secret: docker_password
+
+image_pull_secrets:
+- dockerconfig
+
+---
+kind: pipeline
+name: deploy
+
+steps:
+- name: deploy
+  image: alpine
+  environment:
+    SSH_KEY:
+      from_secret: ssh_key
+`
+
+var testSecretOverrideConfig = `
+kind: pipeline
+name: default
+
+steps:
+- name: test
+  image: golang
+  commands:
+  - echo $TOKEN
+  environment:
+    TOKEN:
+      from_secret: token
+- name: publish
+  image: plugins/docker
+  entrypoint: [ /bin/sh, -c, "echo $PLUGIN_PASSWORD" ]
+  settings:
+    password:
+      from_secret: docker_password
+`
+
+func TestSecretNames(t *testing.T) {
+	got, err := secretNames(testSecretConfig, "default")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []string{"dockerconfig", "token", "docker_password"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestFilterSecrets(t *testing.T) {
+	repo := &core.Secret{RepoID: 1, Name: "token"}
+	global := &core.Secret{Namespace: "octocat", Name: "TOKEN"}
+	other := &core.Secret{RepoID: 1, Name: "ssh_key"}
+
+	got := filterSecrets([]*core.Secret{repo, other, global}, []string{"token", "Token", "docker_password"})
+	want := []*core.Secret{repo}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestMatchBuild(t *testing.T) {
+	tests := []struct {
+		secret *core.Secret
+		build  *core.Build
+		match  bool
+	}{
+		{
+			secret: &core.Secret{},
+			build:  &core.Build{Event: core.EventPush, Target: "feature/x", Ref: "refs/heads/feature/x"},
+			match:  true,
+		},
+		{
+			secret: &core.Secret{Events: []string{core.EventPromote}},
+			build:  &core.Build{Event: core.EventPush, Target: "master"},
+			match:  false,
+		},
+		{
+			secret: &core.Secret{Events: []string{core.EventPromote}},
+			build:  &core.Build{Event: core.EventPromote, Target: "master"},
+			match:  true,
+		},
+		{
+			secret: &core.Secret{Branches: []string{"master", "release/*"}},
+			build:  &core.Build{Event: core.EventPush, Target: "release/1.0"},
+			match:  true,
+		},
+		{
+			secret: &core.Secret{Branches: []string{"master", "release/*"}},
+			build:  &core.Build{Event: core.EventPush, Target: "feature/x"},
+			match:  false,
+		},
+		{
+			secret: &core.Secret{Branches: []string{"master"}},
+			build:  &core.Build{Event: core.EventPullRequest, Target: "master", Source: "feature/x", Fork: "octocat/hello-world"},
+			match:  false,
+		},
+		{
+			secret: &core.Secret{Branches: []string{"master"}},
+			build:  &core.Build{Event: core.EventPullRequest, Target: "develop", Source: "master", Fork: "octocat/hello-world"},
+			match:  true,
+		},
+		{
+			secret: &core.Secret{Branches: []string{"master"}},
+			build:  &core.Build{Event: core.EventPullRequest, Target: "master", Source: "master", Fork: "spaceghost/hello-world"},
+			match:  false,
+		},
+		{
+			secret: &core.Secret{Refs: []string{"refs/tags/v*"}},
+			build:  &core.Build{Event: core.EventTag, Ref: "refs/tags/v1.0.0"},
+			match:  true,
+		},
+		{
+			secret: &core.Secret{Refs: []string{"refs/tags/v*"}},
+			build:  &core.Build{Event: core.EventPush, Ref: "refs/heads/master"},
+			match:  false,
+		},
+	}
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	for i, test := range tests {
+		if got, want := matchBuild(test.secret, repo, test.build), test.match; got != want {
+			t.Errorf("Want match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestFilterImages(t *testing.T) {
+	token := &core.Secret{Name: "token", Images: []string{"golang"}}
+	docker := &core.Secret{Name: "docker_password", Images: []string{"plugins/*"}}
+	deploy := &core.Secret{Name: "ssh_key", Images: []string{"appleboy/drone-ssh"}}
+	other := &core.Secret{Name: "other"}
+
+	// the ssh_key secret is only referenced by the deploy
+	// pipeline, and the docker_password secret is allowed
+	// by the glob pattern.
+	got := filterImages([]*core.Secret{token, docker, deploy, other}, testSecretConfig, "default")
+	want := []*core.Secret{token, docker, deploy, other}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	got = filterImages([]*core.Secret{token, docker, deploy, other}, testSecretConfig, "deploy")
+	want = []*core.Secret{token, docker, other}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// secrets with image restrictions are removed if the
+	// step overrides the commands or entrypoint of the image.
+	got = filterImages([]*core.Secret{token, docker, other}, testSecretOverrideConfig, "default")
+	want = []*core.Secret{other}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// secrets with image restrictions are removed if the
+	// configuration cannot be parsed.
+	got = filterImages([]*core.Secret{token, other}, "{", "default")
+	want = []*core.Secret{other}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
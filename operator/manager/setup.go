@@ -92,6 +92,7 @@ func (s *setup) do(ctx context.Context, stage *core.Stage) error {
 		if len(step.Error) > 500 {
 			step.Error = step.Error[:500]
 		}
+		step.Attempt = stage.Attempt
 		err := s.Steps.Create(noContext, step)
 		if err != nil {
 			logger.WithError(err).
@@ -33,6 +33,8 @@ type teardown struct {
 	Logs      core.LogStream
 	Scheduler core.Scheduler
 	Repos     core.RepositoryStore
+	Resetter  core.Resetter
+	Retries   core.RetryStore
 	Steps     core.StepStore
 	Status    core.StatusService
 	Stages    core.StageStore
@@ -83,6 +85,18 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		stage.Error = stage.Error[:500]
 	}
 
+	// steps that pass after a failed attempt are recorded
+	// so that flaky steps can be reported.
+	if stage.Attempt > 1 {
+		t.passRetries(ctx, stage)
+	}
+
+	// the stage is re-scheduled within the same build if
+	// the failure matches the stage retry policy.
+	if stage.Retry.Match(stage) {
+		return t.retry(ctx, build, stage)
+	}
+
 	stage.Updated = time.Now().Unix()
 	err = t.Stages.Update(noContext, stage)
 	if err != nil {
@@ -333,6 +347,88 @@ func (t *teardown) scheduleDownstream(
 	return errs
 }
 
+// retry is a helper function that records the failed steps
+// and re-schedules the failed stage as a new attempt.
+func (t *teardown) retry(ctx context.Context, build *core.Build, stage *core.Stage) error {
+	attempt := stage.Attempt
+	if attempt == 0 {
+		attempt = 1
+	}
+
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"build.id":      build.ID,
+			"stage.id":      stage.ID,
+			"stage.name":    stage.Name,
+			"stage.attempt": attempt,
+		},
+	)
+	logger.Debugln("manager: retry failed stage")
+
+	for _, step := range stage.Steps {
+		if step.ErrIgnore || !step.IsFailed() {
+			continue
+		}
+		err := t.Retries.Create(noContext, &core.Retry{
+			RepoID:   build.RepoID,
+			BuildID:  build.ID,
+			StageID:  stage.ID,
+			Stage:    stage.Name,
+			Step:     step.Name,
+			Attempt:  attempt,
+			ExitCode: step.ExitCode,
+			Error:    step.Error,
+			Created:  time.Now().Unix(),
+		})
+		if err != nil {
+			logger.WithError(err).
+				WithField("step.name", step.Name).
+				Warnln("manager: cannot record the retried step")
+		}
+	}
+
+	err := t.Resetter.Reset(noContext, stage)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot reset the stage")
+		return err
+	}
+
+	stage.Attempt = attempt + 1
+	stage.Status = core.StatusPending
+	err = t.Stages.Update(noContext, stage)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot update the stage")
+		return err
+	}
+
+	err = t.Scheduler.Schedule(noContext, stage)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot schedule the stage")
+	}
+	return err
+}
+
+// passRetries is a helper function that marks previously
+// retried steps as passed if they succeed on a subsequent
+// attempt.
+func (t *teardown) passRetries(ctx context.Context, stage *core.Stage) {
+	for _, step := range stage.Steps {
+		if step.Status != core.StatusPassing {
+			continue
+		}
+		err := t.Retries.Pass(noContext, stage.ID, step.Name)
+		if err != nil {
+			logrus.WithError(err).
+				WithField("stage.id", stage.ID).
+				WithField("step.name", step.Name).
+				Warnln("manager: cannot record the passed step")
+		}
+	}
+}
+
 // resync updates the stage from the database. Note that it does
 // not update the Version field. This is by design. It prevents
 // the current go routine from updating a stage that has been
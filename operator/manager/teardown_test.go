@@ -3,3 +3,101 @@
 // that can be found in the LICENSE file.
 
 package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestTeardownRetry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	build := &core.Build{ID: 1, RepoID: 2}
+	stage := &core.Stage{
+		ID:       3,
+		Name:     "default",
+		Status:   core.StatusFailing,
+		ExitCode: 137,
+		Machine:  "runner-1",
+		Started:  1000,
+		Stopped:  1060,
+		Attempt:  1,
+		Retry:    &core.RetryPolicy{Attempts: 2, ExitCodes: []int{137}},
+		Steps: []*core.Step{
+			{ID: 4, Name: "clone", Status: core.StatusPassing},
+			{ID: 5, Name: "test", Status: core.StatusFailing, ExitCode: 137},
+		},
+	}
+
+	checkRetry := func(_ context.Context, retry *core.Retry) error {
+		if got, want := retry.Step, "test"; got != want {
+			t.Errorf("Want retried step %s, got %s", want, got)
+		}
+		if got, want := retry.Attempt, 1; got != want {
+			t.Errorf("Want retried attempt %d, got %d", want, got)
+		}
+		if got, want := retry.ExitCode, 137; got != want {
+			t.Errorf("Want retried exit code %d, got %d", want, got)
+		}
+		return nil
+	}
+
+	checkStage := func(_ context.Context, stage *core.Stage) error {
+		if got, want := stage.Status, core.StatusPending; got != want {
+			t.Errorf("Want stage status %s, got %s", want, got)
+		}
+		if got, want := stage.Attempt, 2; got != want {
+			t.Errorf("Want stage attempt %d, got %d", want, got)
+		}
+		return nil
+	}
+
+	retries := mock.NewMockRetryStore(controller)
+	retries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(checkRetry)
+
+	resetter := mock.NewMockResetter(controller)
+	resetter.EXPECT().Reset(gomock.Any(), stage).Return(nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Update(gomock.Any(), stage).DoAndReturn(checkStage)
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Schedule(gomock.Any(), stage).Return(nil)
+
+	r := &teardown{
+		Resetter:  resetter,
+		Retries:   retries,
+		Scheduler: scheduler,
+		Stages:    stages,
+	}
+	err := r.retry(noContext, build, stage)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTeardownPassRetries(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stage := &core.Stage{
+		ID:      3,
+		Attempt: 2,
+		Steps: []*core.Step{
+			{Name: "clone", Status: core.StatusPassing},
+			{Name: "test", Status: core.StatusFailing},
+		},
+	}
+
+	retries := mock.NewMockRetryStore(controller)
+	retries.EXPECT().Pass(gomock.Any(), stage.ID, "clone").Return(nil)
+
+	r := &teardown{Retries: retries}
+	r.passRetries(noContext, stage)
+}
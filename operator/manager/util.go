@@ -15,6 +15,8 @@
 package manager
 
 import (
+	"io"
+
 	"github.com/drone/drone/core"
 )
 
@@ -110,3 +112,16 @@ func depsComplete(stage *core.Stage, siblings []*core.Stage) bool {
 	}
 	return true
 }
+
+// countingReader wraps an io.Reader and counts the number
+// of bytes read.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -48,3 +48,16 @@ func Test_runnerEnviron(t *testing.T) {
 		t.Errorf(diff)
 	}
 }
+
+func Test_identityEnviron(t *testing.T) {
+	got := identityEnviron("eyJhbGciOiJSUzI1NiJ9")
+	want := map[string]string{
+		"DRONE_OIDC_TOKEN": "eyJhbGciOiJSUzI1NiJ9",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got := identityEnviron(""); len(got) != 0 {
+		t.Errorf("Want empty environment when identity tokens are disabled")
+	}
+}
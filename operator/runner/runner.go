@@ -173,6 +173,7 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		systemEnviron(m.System),
 		linkEnviron(m.Repo, m.Build, m.System),
 		m.Build.Params,
+		identityEnviron(m.IDToken),
 	)
 
 	//
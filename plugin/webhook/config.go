@@ -14,7 +14,11 @@
 
 package webhook
 
-import "github.com/drone/drone/core"
+import (
+	"time"
+
+	"github.com/drone/drone/core"
+)
 
 // Config provides the webhook configuration.
 type Config struct {
@@ -22,4 +26,27 @@ type Config struct {
 	Endpoint []string
 	Secret   string
 	System   *core.System
+
+	// SkipVerify disables tls verification for the global
+	// endpoints.
+	SkipVerify bool
+
+	// Webhooks provides the webhooks registered by
+	// repository administrators.
+	Webhooks core.WebhookStore
+
+	// Deliveries persists deliveries so that failed
+	// deliveries can be retried.
+	Deliveries core.WebhookDeliveryStore
+
+	// Retries is the maximum number of delivery attempts.
+	Retries int
+
+	// Backoff is the delay before the first retry, which
+	// doubles with each failed attempt.
+	Backoff time.Duration
+
+	// History is the duration that completed deliveries
+	// are kept before they are purged.
+	History time.Duration
 }
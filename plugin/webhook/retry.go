@@ -0,0 +1,82 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Retrier retries failed and redelivered webhook deliveries.
+type Retrier struct {
+	sender  *sender
+	history time.Duration
+}
+
+// NewRetrier returns a new Retrier.
+func NewRetrier(config Config) *Retrier {
+	return &Retrier{
+		sender:  newSender(config),
+		history: config.History,
+	}
+}
+
+// Start starts the retrier.
+func (r *Retrier) Start(ctx context.Context, dur time.Duration) error {
+	ticker := time.NewTicker(dur)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			r.run(ctx, time.Now())
+		}
+	}
+}
+
+func (r *Retrier) run(ctx context.Context, now time.Time) error {
+	if r.sender.Deliveries == nil {
+		return nil
+	}
+	pending, err := r.sender.Deliveries.ListPending(ctx, now.Unix())
+	if err != nil {
+		logrus.WithError(err).
+			Errorln("webhook: cannot list pending deliveries")
+		return err
+	}
+	for _, delivery := range pending {
+		// the delivery is claimed before it is sent, so that
+		// server replicas do not send the same delivery. A
+		// claim expires if the server stops before the
+		// delivery is updated, and the delivery is retried.
+		err := r.sender.Deliveries.Claim(ctx, delivery, now.Add(claimTimeout).Unix())
+		if err != nil {
+			logrus.WithError(err).
+				WithField("delivery", delivery.ID).
+				Debugln("webhook: cannot claim delivery")
+			continue
+		}
+		if err := r.sender.deliver(ctx, delivery); err != nil {
+			logrus.WithError(err).
+				WithField("delivery", delivery.ID).
+				Errorln("webhook: cannot update delivery")
+		}
+	}
+	if r.history > 0 {
+		before := now.Add(-r.history).Unix()
+		if err := r.sender.Deliveries.Purge(ctx, before); err != nil {
+			logrus.WithError(err).
+				Errorln("webhook: cannot purge delivery history")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/99designs/httpsignatures-go"
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestWebhook_Retry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(500)
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionUpdated,
+		Build:  &core.Build{Number: 1},
+	}
+
+	var delivery *core.WebhookDelivery
+	checkPending := func(_ context.Context, in *core.WebhookDelivery) error {
+		delivery = in
+		if got, want := in.Status, core.DeliveryPending; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if got, want := in.Code, 500; got != want {
+			t.Errorf("Want response code %d, got %d", want, got)
+		}
+		if got, want := in.Attempts, 1; got != want {
+			t.Errorf("Want attempts %d, got %d", want, got)
+		}
+		if in.NextRetry <= in.Created {
+			t.Errorf("Want next retry scheduled")
+		}
+		if in.Error == "" {
+			t.Errorf("Want error recorded")
+		}
+		return nil
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	deliveries.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(checkPending)
+
+	config := Config{
+		Endpoint:   []string{"https://company.com/hooks"},
+		Secret:     "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+		Deliveries: deliveries,
+	}
+	err := New(config).Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// the retrier re-sends the pending delivery once it is
+	// due for retry.
+	gock.New("https://company.com").
+		Post("/hooks").
+		MatchHeader("X-Drone-Event", "build").
+		BodyString(delivery.Payload).
+		Reply(200)
+
+	checkSuccess := func(_ context.Context, in *core.WebhookDelivery) error {
+		if got, want := in.Status, core.DeliverySuccess; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if got, want := in.Attempts, 2; got != want {
+			t.Errorf("Want attempts %d, got %d", want, got)
+		}
+		if in.Error != "" || in.NextRetry != 0 {
+			t.Errorf("Want error and next retry cleared")
+		}
+		return nil
+	}
+
+	now := time.Unix(delivery.NextRetry, 0)
+	deliveries.EXPECT().ListPending(gomock.Any(), now.Unix()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, now.Add(claimTimeout).Unix()).Return(nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).DoAndReturn(checkSuccess)
+
+	err = NewRetrier(config).run(noContext, now)
+	if err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestWebhook_RetryExhausted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(503)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Status:   core.DeliveryPending,
+		Attempts: 2,
+		Payload:  "{}",
+	}
+
+	checkFailed := func(_ context.Context, in *core.WebhookDelivery) error {
+		if got, want := in.Status, core.DeliveryFailed; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if in.NextRetry != 0 {
+			t.Errorf("Want no retry scheduled")
+		}
+		return nil
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListPending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, gomock.Any()).Return(nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).DoAndReturn(checkFailed)
+	deliveries.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil)
+
+	r := NewRetrier(Config{
+		Deliveries: deliveries,
+		Retries:    3,
+		History:    time.Hour,
+	})
+	err := r.run(noContext, time.Now())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWebhook_Backoff(t *testing.T) {
+	s := newSender(Config{Backoff: time.Minute})
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{20, maxBackoff},
+	}
+	for _, test := range tests {
+		if got, want := s.backoff(test.attempts), test.delay; got != want {
+			t.Errorf("Want delay %s after %d attempts, got %s", want, test.attempts, got)
+		}
+	}
+}
+
+func TestWebhook_Repository(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionUpdated,
+		Repo:   &core.Repository{ID: 1, Slug: "octocat/hello-world"},
+		Build:  &core.Build{Number: 1},
+	}
+
+	hooks := []*core.Webhook{
+		{
+			ID:       1,
+			RepoID:   1,
+			Endpoint: "https://octocat.com/hooks",
+			Signer:   "correct-horse-battery-staple",
+			Events:   []string{"build:*"},
+		},
+		{
+			ID:       2,
+			RepoID:   1,
+			Endpoint: "https://octocat.com/ignored",
+			Signer:   "correct-horse-battery-staple",
+			Events:   []string{"repo"},
+		},
+	}
+
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		signature, err := httpsignatures.FromRequest(r)
+		if err != nil || !signature.IsValid("correct-horse-battery-staple", r) {
+			t.Errorf("Want request signed with the webhook secret")
+		}
+		if got, want := r.Header.Get("X-Drone-Event"), "build"; got != want {
+			t.Errorf("Want event header %q, got %q", want, got)
+		}
+	}))
+	defer server.Close()
+	hooks[0].Endpoint = server.URL
+	hooks[1].Endpoint = server.URL + "/ignored"
+
+	checkDelivery := func(_ context.Context, in *core.WebhookDelivery) error {
+		if got, want := in.WebhookID, int64(1); got != want {
+			t.Errorf("Want webhook id %d, got %d", want, got)
+		}
+		if got, want := in.Status, core.DeliverySuccess; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		return nil
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().List(gomock.Any(), int64(1)).Return(hooks, nil)
+	webhooks.EXPECT().Find(gomock.Any(), int64(1)).Return(hooks[0], nil)
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	deliveries.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(checkDelivery)
+
+	config := Config{
+		Webhooks:   webhooks,
+		Deliveries: deliveries,
+	}
+	// the test server listens on a loopback address, which
+	// the restricted client refuses to connect to.
+	sender := newSender(config)
+	sender.Client = server.Client()
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := received, 1; got != want {
+		t.Errorf("Want %d requests, got %d", want, got)
+	}
+}
+
+func TestWebhook_RepositoryDeleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	delivery := &core.WebhookDelivery{
+		ID:        1,
+		WebhookID: 1,
+		Endpoint:  "https://octocat.com/hooks",
+		Event:     core.WebhookEventBuild,
+		Status:    core.DeliveryPending,
+		Attempts:  1,
+		Payload:   "{}",
+	}
+
+	checkFailed := func(_ context.Context, in *core.WebhookDelivery) error {
+		if got, want := in.Status, core.DeliveryFailed; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		return nil
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().Find(gomock.Any(), int64(1)).Return(nil, sql.ErrNoRows)
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListPending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, gomock.Any()).Return(nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).DoAndReturn(checkFailed)
+
+	r := NewRetrier(Config{
+		Webhooks:   webhooks,
+		Deliveries: deliveries,
+	})
+	err := r.run(noContext, time.Now())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that a delivery claimed by another
+// server is not sent.
+func TestWebhook_RetryClaimed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Status:   core.DeliveryPending,
+		Attempts: 1,
+		Payload:  "{}",
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListPending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, gomock.Any()).Return(db.ErrOptimisticLock)
+
+	r := NewRetrier(Config{
+		Deliveries: deliveries,
+	})
+	err := r.run(noContext, time.Now())
+	if err != nil {
+		t.Error(err)
+	}
+}
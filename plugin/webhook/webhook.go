@@ -10,17 +10,39 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/drone/drone/core"
 
 	"github.com/99designs/httpsignatures-go"
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
 )
 
+// default retry configuration.
+const (
+	defaultRetries = 8
+	defaultBackoff = 30 * time.Second
+	maxBackoff     = 6 * time.Hour
+)
+
+// claimTimeout defines how long a delivery is claimed by a
+// server while it is sent. It must exceed the send timeout.
+const claimTimeout = 5 * time.Minute
+
+// maximum length of the error message stored with
+// a delivery.
+const maxErrorLen = 500
+
 // required http headers
 var headers = []string{
 	"date",
@@ -32,14 +54,58 @@ var signer = httpsignatures.NewSigner(
 	headers...,
 )
 
+// insecureClient is used to deliver webhooks to endpoints
+// with tls verification disabled.
+var insecureClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	},
+}
+
+// restrictedClient and restrictedInsecureClient are used to
+// deliver webhooks to endpoints registered by repository
+// administrators. The clients refuse to connect to loopback,
+// private and link-local addresses, including redirects.
+var (
+	restrictedClient         = newRestrictedClient(false)
+	restrictedInsecureClient = newRestrictedClient(true)
+)
+
+// error returned when the repository webhook of a delivery
+// no longer exists.
+var errWebhookNotFound = errors.New("webhook: repository webhook not found")
+
+// error returned when a repository webhook endpoint resolves
+// to a loopback, private or link-local address.
+var errPrivateAddress = errors.New("webhook: endpoint resolves to a private address")
+
 // New returns a new Webhook sender.
 func New(config Config) core.WebhookSender {
-	return &sender{
-		Events:    config.Events,
-		Endpoints: config.Endpoint,
-		Secret:    config.Secret,
-		System:    config.System,
+	return newSender(config)
+}
+
+func newSender(config Config) *sender {
+	s := &sender{
+		Events:     config.Events,
+		Endpoints:  config.Endpoint,
+		Secret:     config.Secret,
+		System:     config.System,
+		SkipVerify: config.SkipVerify,
+		Webhooks:   config.Webhooks,
+		Deliveries: config.Deliveries,
+		Retries:    config.Retries,
+		Backoff:    config.Backoff,
+	}
+	if s.Retries <= 0 {
+		s.Retries = defaultRetries
+	}
+	if s.Backoff <= 0 {
+		s.Backoff = defaultBackoff
 	}
+	return s
 }
 
 type payload struct {
@@ -48,45 +114,179 @@ type payload struct {
 }
 
 type sender struct {
-	Client    *http.Client
-	Events    []string
-	Endpoints []string
-	Secret    string
-	System    *core.System
+	Client     *http.Client
+	Events     []string
+	Endpoints  []string
+	Secret     string
+	SkipVerify bool
+	System     *core.System
+	Webhooks   core.WebhookStore
+	Deliveries core.WebhookDeliveryStore
+	Retries    int
+	Backoff    time.Duration
 }
 
-// Send sends the JSON encoded webhook to the global
-// HTTP endpoints.
+// Send sends the JSON encoded webhook to the global HTTP
+// endpoints and to the webhooks registered by the repository.
+// If a delivery store is configured, each delivery is
+// persisted and failed deliveries are retried.
 func (s *sender) Send(ctx context.Context, in *core.WebhookData) error {
-	if len(s.Endpoints) == 0 {
-		return nil
+	var targets []*core.WebhookDelivery
+	if s.match(in.Event, in.Action) {
+		for _, endpoint := range s.Endpoints {
+			targets = append(targets, &core.WebhookDelivery{
+				Endpoint: endpoint,
+			})
+		}
 	}
-	if s.match(in.Event, in.Action) == false {
+	if in.Repo != nil && s.Webhooks != nil {
+		hooks, err := s.Webhooks.List(ctx, in.Repo.ID)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			if match(hook.Events, in.Event, in.Action) {
+				targets = append(targets, &core.WebhookDelivery{
+					WebhookID: hook.ID,
+					Endpoint:  hook.Endpoint,
+				})
+			}
+		}
+	}
+	if len(targets) == 0 {
 		return nil
 	}
+
 	wrapper := payload{
 		WebhookData: in,
 		System:      s.System,
 	}
 	data, _ := json.Marshal(wrapper)
-	for _, endpoint := range s.Endpoints {
-		err := s.send(endpoint, s.Secret, in.Event, data)
-		if err != nil {
-			return err
+
+	var result error
+	for _, delivery := range targets {
+		now := time.Now().Unix()
+		delivery.Event = in.Event
+		delivery.Action = in.Action
+		delivery.Status = core.DeliveryPending
+		delivery.Payload = string(data)
+		delivery.Created = now
+		delivery.Updated = now
+		// the delivery is claimed by this server until the
+		// first attempt completes.
+		delivery.NextRetry = now + int64(claimTimeout/time.Second)
+
+		if s.Deliveries == nil {
+			secret, skipVerify, err := s.signer(ctx, delivery)
+			if err == nil {
+				_, err = s.send(delivery, secret, in.Event, data, skipVerify)
+			}
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+			continue
+		}
+		// if the delivery cannot be persisted it cannot be
+		// retried, and the error is returned to the caller.
+		if err := s.Deliveries.Create(ctx, delivery); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		if err := s.deliver(ctx, delivery); err != nil {
+			result = multierror.Append(result, err)
 		}
 	}
-	return nil
+	return result
+}
+
+// signer returns the signing secret and tls verification
+// setting for the delivery. Deliveries to a repository webhook
+// are signed with the secret of the webhook.
+func (s *sender) signer(ctx context.Context, delivery *core.WebhookDelivery) (string, bool, error) {
+	if delivery.WebhookID == 0 {
+		return s.Secret, s.SkipVerify, nil
+	}
+	if s.Webhooks == nil {
+		return "", false, errWebhookNotFound
+	}
+	hook, err := s.Webhooks.Find(ctx, delivery.WebhookID)
+	if err != nil {
+		return "", false, errWebhookNotFound
+	}
+	return hook.Signer, hook.SkipVerify, nil
 }
 
-func (s *sender) send(endpoint, secret, event string, data []byte) error {
+// deliver attempts the delivery and persists the result. A
+// failed delivery is scheduled for retry with exponential
+// backoff until the maximum number of attempts is reached.
+func (s *sender) deliver(ctx context.Context, delivery *core.WebhookDelivery) error {
+	start := time.Now()
+	secret, skipVerify, err := s.signer(ctx, delivery)
+	if err == errWebhookNotFound {
+		// the repository webhook was deleted and the
+		// delivery cannot be retried.
+		delivery.Attempts = s.Retries - 1
+	}
+	var code int
+	if err == nil {
+		code, err = s.send(delivery, secret, delivery.Event, []byte(delivery.Payload), skipVerify)
+	}
+	if err == nil && (code < 200 || code > 299) {
+		err = fmt.Errorf("webhook: unexpected status code %d", code)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.Code = code
+	delivery.Latency = int64(now.Sub(start) / time.Millisecond)
+	delivery.Updated = now.Unix()
+	delivery.NextRetry = 0
+	delivery.Error = ""
+	switch {
+	case err == nil:
+		delivery.Status = core.DeliverySuccess
+	case delivery.Attempts >= s.Retries:
+		delivery.Status = core.DeliveryFailed
+		delivery.Error = truncate(err.Error(), maxErrorLen)
+	default:
+		delivery.Status = core.DeliveryPending
+		delivery.Error = truncate(err.Error(), maxErrorLen)
+		delivery.NextRetry = now.Add(s.backoff(delivery.Attempts)).Unix()
+	}
+
+	if err != nil {
+		logrus.WithError(err).
+			WithField("endpoint", delivery.Endpoint).
+			WithField("delivery", delivery.ID).
+			WithField("attempts", delivery.Attempts).
+			WithField("status", delivery.Status).
+			Warnln("webhook: delivery failed")
+	}
+	return s.Deliveries.Update(ctx, delivery)
+}
+
+// backoff returns the delay before the next attempt, doubling
+// the delay with each failed attempt.
+func (s *sender) backoff(attempts int) time.Duration {
+	delay := s.Backoff
+	for i := 1; i < attempts; i++ {
+		delay = delay * 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+func (s *sender) send(delivery *core.WebhookDelivery, secret, event string, data []byte, skipVerify bool) (int, error) {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, time.Minute)
 	defer cancel()
 
 	buf := bytes.NewBuffer(data)
-	req, err := http.NewRequest("POST", endpoint, buf)
+	req, err := http.NewRequest("POST", delivery.Endpoint, buf)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req = req.WithContext(ctx)
@@ -94,19 +294,29 @@ func (s *sender) send(endpoint, secret, event string, data []byte) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Digest", "SHA-256="+digest(data))
 	req.Header.Add("Date", time.Now().UTC().Format(http.TimeFormat))
-	err = signer.SignRequest("hmac-key", s.Secret, req)
+	err = signer.SignRequest("hmac-key", secret, req)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	res, err := s.client().Do(req)
-	if res != nil {
-		res.Body.Close()
+	// deliveries to repository webhooks are restricted to
+	// public addresses.
+	restricted := delivery.WebhookID != 0
+	res, err := s.client(restricted, skipVerify).Do(req)
+	if err != nil {
+		return 0, err
 	}
-	return err
+	res.Body.Close()
+	return res.StatusCode, nil
 }
 
 func (s *sender) match(event, action string) bool {
-	if len(s.Events) == 0 {
+	return match(s.Events, event, action)
+}
+
+// match returns true if the event and action match one of
+// the event filter patterns, or if no patterns are defined.
+func match(patterns []string, event, action string) bool {
+	if len(patterns) == 0 {
 		return true
 	}
 	var name string
@@ -116,7 +326,7 @@ func (s *sender) match(event, action string) bool {
 	case action != "":
 		name = event + ":" + action
 	}
-	for _, pattern := range s.Events {
+	for _, pattern := range patterns {
 		if ok, _ := filepath.Match(pattern, name); ok {
 			return true
 		}
@@ -124,11 +334,54 @@ func (s *sender) match(event, action string) bool {
 	return false
 }
 
-func (s *sender) client() *http.Client {
-	if s.Client == nil {
+func (s *sender) client(restricted, skipVerify bool) *http.Client {
+	switch {
+	case s.Client != nil:
+		return s.Client
+	case restricted && skipVerify:
+		return restrictedInsecureClient
+	case restricted:
+		return restrictedClient
+	case skipVerify:
+		return insecureClient
+	default:
 		return http.DefaultClient
 	}
-	return s.Client
+}
+
+// helper function returns an http client that refuses to
+// connect to loopback, private and link-local addresses. The
+// address is checked after it is resolved, and the client does
+// not use a proxy, which would otherwise bypass the check.
+func newRestrictedClient(skipVerify bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   restrictAddress,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: skipVerify,
+			},
+		},
+	}
+}
+
+// helper function returns an error if the resolved address
+// is a loopback, private or link-local address.
+func restrictAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || core.IsPrivateAddress(ip) {
+		return errPrivateAddress
+	}
+	return nil
 }
 
 func digest(data []byte) string {
@@ -136,3 +389,10 @@ func digest(data []byte) string {
 	h.Write(data)
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
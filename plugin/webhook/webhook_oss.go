@@ -18,6 +18,7 @@ package webhook
 
 import (
 	"context"
+	"time"
 
 	"github.com/drone/drone/core"
 )
@@ -32,3 +33,16 @@ type noop struct{}
 func (noop) Send(context.Context, *core.WebhookData) error {
 	return nil
 }
+
+// Retrier retries failed webhook deliveries.
+type Retrier struct{}
+
+// NewRetrier returns a new Retrier.
+func NewRetrier(Config) *Retrier {
+	return new(Retrier)
+}
+
+// Start starts the retrier.
+func (*Retrier) Start(ctx context.Context, dur time.Duration) error {
+	return nil
+}
@@ -9,6 +9,7 @@ package webhook
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/drone/drone/core"
@@ -63,17 +64,40 @@ func TestWebhook(t *testing.T) {
 
 func TestWebhook_CustomClient(t *testing.T) {
 	sender := new(sender)
-	if sender.client() != http.DefaultClient {
+	if sender.client(false, false) != http.DefaultClient {
 		t.Errorf("Expect default http client")
 	}
+	if sender.client(false, true) != insecureClient {
+		t.Errorf("Expect insecure http client")
+	}
+	if sender.client(true, false) != restrictedClient {
+		t.Errorf("Expect restricted http client")
+	}
+	if sender.client(true, true) != restrictedInsecureClient {
+		t.Errorf("Expect restricted insecure http client")
+	}
 
 	custom := &http.Client{}
 	sender.Client = custom
-	if sender.client() != custom {
+	if sender.client(true, false) != custom {
 		t.Errorf("Expect custom http client")
 	}
 }
 
+// this test verifies that the restricted client refuses to
+// connect to a loopback address.
+func TestWebhook_RestrictedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Want request to loopback address refused")
+	}))
+	defer server.Close()
+
+	_, err := restrictedClient.Get(server.URL)
+	if err == nil {
+		t.Errorf("Want error connecting to loopback address")
+	}
+}
+
 func TestWebhook_NoEndpoints(t *testing.T) {
 	webhook := &core.WebhookData{
 		Event:  core.WebhookEventUser,
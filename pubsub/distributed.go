@@ -0,0 +1,91 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/drone/drone/broker"
+	"github.com/drone/drone/core"
+
+	"github.com/dchest/uniuri"
+	"github.com/sirupsen/logrus"
+)
+
+// channel is the broker channel used to distribute messages.
+const channel = "drone_events"
+
+// envelope wraps a message distributed through the broker
+// with the identifier of the originating replica.
+type envelope struct {
+	Origin  string        `json:"origin"`
+	Message *core.Message `json:"message"`
+}
+
+type distributed struct {
+	*hub
+
+	origin string
+	broker broker.Broker
+}
+
+// NewDistributed creates a new publish subscriber that
+// distributes messages to subscribers on all replicas that
+// share the message broker.
+func NewDistributed(b broker.Broker) (core.Pubsub, error) {
+	d := &distributed{
+		hub:    New().(*hub),
+		origin: uniuri.NewLen(16),
+		broker: b,
+	}
+	sub, err := b.Subscribe(context.Background(), channel)
+	if err != nil {
+		return nil, err
+	}
+	go d.receive(sub)
+	return d, nil
+}
+
+func (d *distributed) Publish(ctx context.Context, e *core.Message) error {
+	d.hub.Publish(ctx, e)
+	data, err := json.Marshal(&envelope{
+		Origin:  d.origin,
+		Message: e,
+	})
+	if err != nil {
+		return err
+	}
+	return d.broker.Publish(ctx, channel, data)
+}
+
+// receive publishes messages received from other replicas
+// to the local subscribers. Messages that originate from this
+// replica were already published and are ignored.
+func (d *distributed) receive(sub <-chan []byte) {
+	for data := range sub {
+		in := new(envelope)
+		if err := json.Unmarshal(data, in); err != nil {
+			logrus.WithError(err).Warnln("pubsub: cannot decode message")
+			continue
+		}
+		if in.Origin == d.origin || in.Message == nil {
+			continue
+		}
+		d.hub.Publish(noContext, in.Message)
+	}
+}
+
+var noContext = context.Background()
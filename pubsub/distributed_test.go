@@ -0,0 +1,64 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/broker"
+	"github.com/drone/drone/core"
+)
+
+func TestDistributed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two replicas that share the same broker.
+	b := broker.NewMemory()
+	p1, err := NewDistributed(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := NewDistributed(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events1, _ := p1.Subscribe(ctx)
+	events2, _ := p2.Subscribe(ctx)
+
+	p1.Publish(ctx, &core.Message{Repository: "octocat/hello-world"})
+
+	for i, events := range []<-chan *core.Message{events1, events2} {
+		select {
+		case e := <-events:
+			if got, want := e.Repository, "octocat/hello-world"; got != want {
+				t.Errorf("Want repository %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Want message received by replica %d", i+1)
+		}
+	}
+
+	// the publishing replica must not receive its own
+	// message a second time through the broker.
+	select {
+	case <-events1:
+		t.Errorf("Want message published once")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
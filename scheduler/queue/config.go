@@ -0,0 +1,45 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import "time"
+
+// Config provides the scheduler configuration.
+type Config struct {
+	// Interval defines how often the queue is re-scanned
+	// for pending stages, in addition to scanning when a
+	// stage is scheduled or a runner requests work.
+	Interval time.Duration
+
+	// Lease defines how long a runner has to accept a stage
+	// before the stage is returned to the queue and becomes
+	// eligible for processing by another runner.
+	Lease time.Duration
+
+	// FairShare enables fair-share scheduling across each
+	// repository or namespace. Stages are dispatched to the
+	// repository or namespace with the least recent usage,
+	// weighted by its shares.
+	FairShare string
+
+	// Shares defines the number of shares for each repository
+	// or namespace. Each repository or namespace receives a
+	// single share by default.
+	Shares map[string]int
+
+	// Decay defines the half-life of the recent usage used
+	// by fair-share scheduling.
+	Decay time.Duration
+}
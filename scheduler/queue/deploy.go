@@ -0,0 +1,52 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"fmt"
+
+	"github.com/drone/drone/core"
+)
+
+// checkDeploy returns a reason the stage must wait for the
+// deployment of an earlier build to the same environment, or
+// an empty string if the stage can be dispatched. An
+// environment accepts a single deployment at a time. This is
+// enforced when the stage is dispatched, since concurrent
+// deployment requests can both pass the check performed when
+// the deployment is requested.
+func checkDeploy(stage *core.Stage, siblings []*core.Stage, owners map[int64]*owner) string {
+	current, ok := owners[stage.BuildID]
+	if !ok || current.deploy == "" {
+		return ""
+	}
+	for _, sibling := range siblings {
+		if sibling.RepoID != stage.RepoID {
+			continue
+		}
+		if sibling.BuildID == stage.BuildID {
+			continue
+		}
+		other, ok := owners[sibling.BuildID]
+		if !ok || other.deploy != current.deploy {
+			continue
+		}
+		if sibling.BuildID < stage.BuildID ||
+			sibling.Status == core.StatusRunning {
+			return fmt.Sprintf("waiting on deployment: a deployment to %s is in progress", current.deploy)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestCheckDeploy(t *testing.T) {
+	owners := map[int64]*owner{
+		1: {deploy: "production"},
+		2: {deploy: "production"},
+		3: {deploy: "staging"},
+		4: {},
+	}
+	items := []*core.Stage{
+		{ID: 1, RepoID: 1, BuildID: 1, Status: core.StatusPending},
+		{ID: 2, RepoID: 1, BuildID: 2, Status: core.StatusPending},
+		{ID: 3, RepoID: 1, BuildID: 3, Status: core.StatusPending},
+		{ID: 4, RepoID: 1, BuildID: 4, Status: core.StatusPending},
+		{ID: 5, RepoID: 2, BuildID: 5, Status: core.StatusPending},
+	}
+	owners[5] = &owner{deploy: "production"}
+
+	tests := []struct {
+		stage *core.Stage
+		wait  bool
+	}{
+		// the earliest deployment to the environment.
+		{stage: items[0], wait: false},
+		// a later deployment to the same environment.
+		{stage: items[1], wait: true},
+		// a deployment to another environment.
+		{stage: items[2], wait: false},
+		// a build that is not a deployment.
+		{stage: items[3], wait: false},
+		// a deployment of another repository.
+		{stage: items[4], wait: false},
+	}
+	for i, test := range tests {
+		reason := checkDeploy(test.stage, items, owners)
+		if got, want := reason != "", test.wait; got != want {
+			t.Errorf("Want wait %v, got %v at index %d", want, got, i)
+		}
+	}
+
+	// a running deployment blocks an earlier deployment.
+	items[1].Status = core.StatusRunning
+	if checkDeploy(items[0], items, owners) == "" {
+		t.Errorf("Want deployment blocked by a running deployment")
+	}
+
+	// the check is skipped if the owners are not resolved.
+	if checkDeploy(items[1], items, nil) != "" {
+		t.Errorf("Want deployment dispatched without owners")
+	}
+}
+
+// this test verifies that concurrent deployments to the same
+// environment are dispatched one at a time.
+func TestQueueDeploy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, RepoID: 1, BuildID: 1, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+		{ID: 2, RepoID: 1, BuildID: 2, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+	}
+	deploy := &core.Build{Event: core.EventPromote, Deploy: "production"}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil)
+	store.EXPECT().Update(ctx, items[0]).Return(nil)
+	store.EXPECT().Update(ctx, items[1]).Return(nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(ctx, int64(1)).Return(&core.Repository{Namespace: "octocat"}, nil).Times(2)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(ctx, gomock.Any()).Return(deploy, nil).Times(2)
+
+	q := newQueue(store, nil, newOwners(repos, builds), Config{})
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next.ID, items[0].ID; got != want {
+		t.Errorf("Want stage %d, got %d", want, got)
+	}
+	if got, want := items[1].Reason, "waiting on deployment: a deployment to production is in progress"; got != want {
+		t.Errorf("Want reason %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// Fair-share modes.
+const (
+	FairShareRepository = "repository"
+	FairShareNamespace  = "namespace"
+)
+
+// fairshare orders the queue so that each dispatch goes to
+// the repository or namespace with the least recent usage,
+// weighted by its configured shares.
+type fairshare struct {
+	mode   string
+	shares map[string]int
+	decay  time.Duration
+	usage  map[string]*share
+}
+
+// share tracks the recent usage of a repository or namespace.
+// The usage decays exponentially so that past usage is
+// gradually forgotten.
+type share struct {
+	value   float64
+	updated time.Time
+}
+
+// newFairShare returns a new fairshare, or nil if fair-share
+// scheduling is disabled.
+func newFairShare(config Config) *fairshare {
+	if config.FairShare == "" {
+		return nil
+	}
+	f := &fairshare{
+		mode:   config.FairShare,
+		shares: config.Shares,
+		decay:  config.Decay,
+		usage:  map[string]*share{},
+	}
+	if f.decay == 0 {
+		f.decay = time.Hour
+	}
+	return f
+}
+
+// sort orders the items by effective priority and then by the
+// weighted usage of their group. The usage of a group includes
+// the items of the same group that are ahead in the queue, which
+// interleaves groups in proportion to their shares.
+func (f *fairshare) sort(items []*core.Stage, owners map[int64]*owner, now time.Time) {
+	pending := map[string]int{}
+	weighted := map[*core.Stage]float64{}
+	for _, item := range items {
+		group := f.group(item, owners)
+		weighted[item] = (f.value(group, now) + float64(pending[group])) / f.weight(group)
+		pending[group]++
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, pj := items[i].EffectivePriority(now), items[j].EffectivePriority(now)
+		if pi != pj {
+			return pi > pj
+		}
+		return weighted[items[i]] < weighted[items[j]]
+	})
+}
+
+// record records the item was dispatched, increasing the
+// recent usage of its group.
+func (f *fairshare) record(item *core.Stage, owners map[int64]*owner, now time.Time) {
+	group := f.group(item, owners)
+	f.usage[group] = &share{
+		value:   f.value(group, now) + 1,
+		updated: now,
+	}
+}
+
+// prune removes usage that has decayed to a negligible value.
+func (f *fairshare) prune(now time.Time) {
+	for group := range f.usage {
+		if f.value(group, now) < 0.01 {
+			delete(f.usage, group)
+		}
+	}
+}
+
+// value returns the decayed usage of the group.
+func (f *fairshare) value(group string, now time.Time) float64 {
+	s, ok := f.usage[group]
+	if !ok {
+		return 0
+	}
+	elapsed := now.Sub(s.updated)
+	return s.value * math.Pow(0.5, float64(elapsed)/float64(f.decay))
+}
+
+// weight returns the configured shares of the group, which
+// defaults to a single share.
+func (f *fairshare) weight(group string) float64 {
+	if shares, ok := f.shares[group]; ok && shares > 0 {
+		return float64(shares)
+	}
+	return 1
+}
+
+// group returns the repository or namespace of the item.
+func (f *fairshare) group(item *core.Stage, owners map[int64]*owner) string {
+	owner, ok := owners[item.BuildID]
+	switch {
+	case ok && f.mode == FairShareNamespace && owner.namespace != "":
+		return owner.namespace
+	case ok && f.mode == FairShareRepository && owner.repo != "":
+		return owner.repo
+	default:
+		return strconv.FormatInt(item.RepoID, 10)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+// this test verifies that stages are interleaved across
+// repositories instead of dispatched in id order.
+func TestFairShareSort(t *testing.T) {
+	f := newFairShare(Config{FairShare: FairShareRepository})
+	owners := map[int64]*owner{
+		1: {repo: "octocat/hello-world"},
+		2: {repo: "spaceghost/hello-world"},
+	}
+	items := []*core.Stage{
+		{ID: 1, BuildID: 1},
+		{ID: 2, BuildID: 1},
+		{ID: 3, BuildID: 1},
+		{ID: 4, BuildID: 2},
+		{ID: 5, BuildID: 2},
+	}
+	f.sort(items, owners, time.Now())
+
+	want := []int64{1, 4, 2, 5, 3}
+	for i, item := range items {
+		if got := item.ID; got != want[i] {
+			t.Errorf("Want stage %d at index %d, got %d", want[i], i, got)
+		}
+	}
+}
+
+// this test verifies that repositories with more shares
+// receive a proportionally larger number of dispatches, and
+// that priority takes precedence over recent usage.
+func TestFairShareSort_Shares(t *testing.T) {
+	f := newFairShare(Config{
+		FairShare: FairShareNamespace,
+		Shares:    map[string]int{"octocat": 2},
+	})
+	owners := map[int64]*owner{
+		1: {namespace: "octocat"},
+		2: {namespace: "spaceghost"},
+	}
+	items := []*core.Stage{
+		{ID: 1, BuildID: 1},
+		{ID: 2, BuildID: 1},
+		{ID: 3, BuildID: 1},
+		{ID: 4, BuildID: 1},
+		{ID: 5, BuildID: 2},
+		{ID: 6, BuildID: 2},
+		{ID: 7, BuildID: 2, Priority: core.PriorityHigh},
+	}
+	f.sort(items, owners, time.Now())
+
+	want := []int64{7, 1, 5, 2, 3, 6, 4}
+	for i, item := range items {
+		if got := item.ID; got != want[i] {
+			t.Errorf("Want stage %d at index %d, got %d", want[i], i, got)
+		}
+	}
+}
+
+// this test verifies that priority aging applies when
+// fair-share scheduling is enabled, so that low priority
+// stages that have waited in the queue are not starved.
+func TestFairShareSort_Aging(t *testing.T) {
+	f := newFairShare(Config{FairShare: FairShareRepository})
+	owners := map[int64]*owner{
+		1: {repo: "octocat/hello-world"},
+		2: {repo: "spaceghost/hello-world"},
+	}
+	now := time.Now()
+	items := []*core.Stage{
+		{ID: 1, BuildID: 1, Priority: core.PriorityLow, Created: now.Add(-time.Hour).Unix()},
+		{ID: 2, BuildID: 2, Priority: core.PriorityHigh, Created: now.Unix()},
+	}
+	f.sort(items, owners, now)
+
+	want := []int64{1, 2}
+	for i, item := range items {
+		if got := item.ID; got != want[i] {
+			t.Errorf("Want stage %d at index %d, got %d", want[i], i, got)
+		}
+	}
+}
+
+// this test verifies that recent usage decays over time.
+func TestFairShareRecord(t *testing.T) {
+	f := newFairShare(Config{FairShare: FairShareRepository, Decay: time.Hour})
+	owners := map[int64]*owner{
+		1: {repo: "octocat/hello-world"},
+	}
+	now := time.Now()
+	f.record(&core.Stage{BuildID: 1}, owners, now)
+	f.record(&core.Stage{BuildID: 1}, owners, now)
+
+	if got, want := f.value("octocat/hello-world", now), 2.0; got != want {
+		t.Errorf("Want usage %v, got %v", want, got)
+	}
+	if got, want := f.value("octocat/hello-world", now.Add(time.Hour)), 1.0; got != want {
+		t.Errorf("Want decayed usage %v, got %v", want, got)
+	}
+
+	f.prune(now.Add(24 * time.Hour))
+	if len(f.usage) != 0 {
+		t.Errorf("Want decayed usage pruned")
+	}
+}
+
+// this test verifies the queue dispatches the stage from the
+// repository with the least recent usage.
+func TestQueueFairShare(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, RepoID: 1, BuildID: 1, OS: "linux", Arch: "amd64"},
+		{ID: 2, RepoID: 2, BuildID: 2, OS: "linux", Arch: "amd64"},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil)
+	store.EXPECT().Update(ctx, items[1]).Return(nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(ctx, int64(1)).Return(&core.Repository{Slug: "octocat/hello-world"}, nil)
+	repos.EXPECT().Find(ctx, int64(2)).Return(&core.Repository{Slug: "spaceghost/hello-world"}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(ctx, gomock.Any()).Return(&core.Build{}, nil).Times(2)
+
+	q := newQueue(store, nil, newOwners(repos, builds), Config{FairShare: FairShareRepository})
+	q.fair.usage["octocat/hello-world"] = &share{value: 10, updated: time.Now()}
+
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next.ID, int64(2); got != want {
+		t.Errorf("Want stage %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import "strings"
+
+// label selector operators.
+const (
+	opEquals    = "="
+	opNotEquals = "!="
+	opIn        = "in"
+	opNotIn     = "notin"
+	opExists    = "exists"
+)
+
+// requirement is a label selector requirement, modeled on
+// Kubernetes node selectors.
+type requirement struct {
+	op     string
+	values []string
+}
+
+// parseRequirement parses the label selector requirement from
+// the label value. The following expressions are supported,
+// and any other value must match the label exactly:
+//
+//	exists
+//	!= value
+//	in (value1, value2)
+//	notin (value1, value2)
+func parseRequirement(value string) requirement {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case trimmed == opExists:
+		return requirement{op: opExists}
+	case strings.HasPrefix(trimmed, opNotEquals):
+		return requirement{
+			op:     opNotEquals,
+			values: []string{strings.TrimSpace(strings.TrimPrefix(trimmed, opNotEquals))},
+		}
+	}
+	for _, op := range []string{opIn, opNotIn} {
+		if !strings.HasPrefix(trimmed, op) {
+			continue
+		}
+		list := strings.TrimSpace(strings.TrimPrefix(trimmed, op))
+		if !strings.HasPrefix(list, "(") || !strings.HasSuffix(list, ")") {
+			continue
+		}
+		list = strings.TrimSuffix(strings.TrimPrefix(list, "("), ")")
+		var values []string
+		for _, v := range strings.Split(list, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return requirement{op: op, values: values}
+	}
+	return requirement{op: opEquals, values: []string{value}}
+}
+
+// matches returns true if the label satisfies the requirement.
+func (r requirement) matches(value string, ok bool) bool {
+	switch r.op {
+	case opExists:
+		return ok
+	case opNotEquals:
+		return !ok || value != r.values[0]
+	case opIn:
+		return ok && contains(r.values, value)
+	case opNotIn:
+		return !ok || !contains(r.values, value)
+	default:
+		return ok && value == r.values[0]
+	}
+}
+
+// checkLabels returns true if the runner labels satisfy the
+// stage label selector. The stage only needs to define the
+// labels it requires, and the runner may define any number of
+// additional labels.
+func checkLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		value, ok := labels[k]
+		if !parseRequirement(v).matches(value, ok) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
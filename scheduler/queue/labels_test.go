@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package queue
+
+import "testing"
+
+func TestCheckLabels(t *testing.T) {
+	runner := map[string]string{
+		"gpu":    "no",
+		"region": "eu",
+		"disk":   "ssd",
+	}
+	tests := []struct {
+		selector map[string]string
+		labels   map[string]string
+		match    bool
+	}{
+		// empty selectors match any runner.
+		{selector: nil, labels: nil, match: true},
+		{selector: nil, labels: runner, match: true},
+		// subset matching.
+		{selector: map[string]string{"region": "eu"}, labels: runner, match: true},
+		{selector: map[string]string{"region": "eu", "disk": "ssd"}, labels: runner, match: true},
+		{selector: map[string]string{"region": "us"}, labels: runner, match: false},
+		{selector: map[string]string{"region": "eu"}, labels: nil, match: false},
+		{selector: map[string]string{"arch": "arm"}, labels: runner, match: false},
+		// exists
+		{selector: map[string]string{"disk": "exists"}, labels: runner, match: true},
+		{selector: map[string]string{"arch": "exists"}, labels: runner, match: false},
+		// not equals
+		{selector: map[string]string{"gpu": "!= yes"}, labels: runner, match: true},
+		{selector: map[string]string{"gpu": "!=no"}, labels: runner, match: false},
+		{selector: map[string]string{"arch": "!= arm"}, labels: runner, match: true},
+		// in
+		{selector: map[string]string{"region": "in (us, eu)"}, labels: runner, match: true},
+		{selector: map[string]string{"region": "in (us,ap)"}, labels: runner, match: false},
+		{selector: map[string]string{"arch": "in (arm)"}, labels: runner, match: false},
+		// notin
+		{selector: map[string]string{"region": "notin (us, ap)"}, labels: runner, match: true},
+		{selector: map[string]string{"region": "notin (eu)"}, labels: runner, match: false},
+		{selector: map[string]string{"arch": "notin (arm)"}, labels: runner, match: true},
+		// combined
+		{selector: map[string]string{"region": "in (eu)", "gpu": "!= yes", "disk": "exists"}, labels: runner, match: true},
+		{selector: map[string]string{"region": "in (eu)", "gpu": "yes"}, labels: runner, match: false},
+		// values that are not expressions must match exactly.
+		{selector: map[string]string{"zone": "india"}, labels: map[string]string{"zone": "india"}, match: true},
+		{selector: map[string]string{"zone": "in eu"}, labels: map[string]string{"zone": "in eu"}, match: true},
+	}
+	for i, test := range tests {
+		if got, want := checkLabels(test.selector, test.labels), test.match; got != want {
+			t.Errorf("Want match %v at index %d, got %v", want, i, got)
+		}
+	}
+}
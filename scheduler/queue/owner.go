@@ -0,0 +1,96 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// owner identifies the repository, namespace and user that
+// own a build, and the target environment of a deployment.
+type owner struct {
+	repo      string
+	namespace string
+	user      string
+	deploy    string
+}
+
+// owners resolves the owner of each build with incomplete
+// stages. Owners are cached since a build owner does not
+// change while the build is in the queue.
+type owners struct {
+	repos  core.RepositoryStore
+	builds core.BuildStore
+	cache  map[int64]*owner
+}
+
+func newOwners(repos core.RepositoryStore, builds core.BuildStore) *owners {
+	return &owners{
+		repos:  repos,
+		builds: builds,
+		cache:  map[int64]*owner{},
+	}
+}
+
+// resolve returns the owner of each stage, keyed by build id.
+func (o *owners) resolve(ctx context.Context, items []*core.Stage) map[int64]*owner {
+	out := map[int64]*owner{}
+	for _, item := range items {
+		if _, ok := out[item.BuildID]; ok {
+			continue
+		}
+		if cached, ok := o.cache[item.BuildID]; ok {
+			out[item.BuildID] = cached
+			continue
+		}
+		out[item.BuildID] = o.find(ctx, item)
+	}
+	// the cache only retains owners for builds with
+	// incomplete stages, pruning completed builds.
+	o.cache = out
+	return out
+}
+
+// find returns the owner of the stage from the datastore.
+func (o *owners) find(ctx context.Context, stage *core.Stage) *owner {
+	logger := logrus.
+		WithField("build-id", stage.BuildID).
+		WithField("stage-id", stage.ID)
+
+	out := new(owner)
+	repo, err := o.repos.Find(ctx, stage.RepoID)
+	if err != nil {
+		logger.WithError(err).Debugln("queue: cannot find stage repository")
+		return out
+	}
+	out.repo = repo.Slug
+	out.namespace = repo.Namespace
+
+	build, err := o.builds.Find(ctx, stage.BuildID)
+	if err != nil {
+		logger.WithError(err).Debugln("queue: cannot find stage build")
+		return out
+	}
+	out.user = build.Sender
+	switch build.Event {
+	case core.EventPromote, core.EventRollback:
+		out.deploy = build.Deploy
+	}
+	return out
+}
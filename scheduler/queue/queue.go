@@ -16,12 +16,15 @@ package queue
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/drone/drone-go/drone"
 
 	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
 )
 
 type queue struct {
@@ -30,21 +33,35 @@ type queue struct {
 	ready    chan struct{}
 	paused   bool
 	interval time.Duration
+	lease    time.Duration
 	throttle int
 	store    core.StageStore
+	quotas   core.QuotaStore
+	owners   *owners
+	fair     *fairshare
 	workers  map[*worker]struct{}
 	ctx      context.Context
 }
 
 // newQueue returns a new Queue backed by the build datastore.
-func newQueue(store core.StageStore) *queue {
+func newQueue(store core.StageStore, quotas core.QuotaStore, owners *owners, config Config) *queue {
 	q := &queue{
 		store:    store,
+		quotas:   quotas,
+		owners:   owners,
+		fair:     newFairShare(config),
 		ready:    make(chan struct{}, 1),
 		workers:  map[*worker]struct{}{},
-		interval: time.Minute,
+		interval: config.Interval,
+		lease:    config.Lease,
 		ctx:      context.Background(),
 	}
+	if q.interval == 0 {
+		q.interval = time.Minute
+	}
+	if q.lease == 0 {
+		q.lease = time.Minute
+	}
 	go q.start()
 	return q
 }
@@ -130,6 +147,40 @@ func (q *queue) signal(ctx context.Context) error {
 		return err
 	}
 
+	var quotas []*core.Quota
+	if q.quotas != nil {
+		quotas, err = q.quotas.List(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the build owners are required to enforce quotas,
+	// fair-share scheduling and deployment concurrency.
+	var owners map[int64]*owner
+	if q.owners != nil {
+		owners = q.owners.resolve(ctx, items)
+	}
+
+	now := time.Now()
+	if q.fair != nil {
+		// items are dispatched in order of effective priority and
+		// then by the recent usage of the repository or namespace.
+		q.fair.prune(now)
+		q.fair.sort(items, owners, now)
+	} else {
+		// items are dispatched in order of effective priority. The
+		// sort is stable to preserve the existing ordering by age
+		// for items with the same effective priority.
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].EffectivePriority(now) > items[j].EffectivePriority(now)
+		})
+	}
+
+	// the quota usage is calculated from the stages that
+	// are running or have been handed to a runner.
+	usage := newUsage(quotas, owners, items, now)
+
 	q.Lock()
 	defer q.Unlock()
 	for _, item := range items {
@@ -140,6 +191,14 @@ func (q *queue) signal(ctx context.Context) error {
 			continue
 		}
 
+		// if the stage was handed to a runner and the lease
+		// has not yet expired, the stage cannot be handed to
+		// another runner. The lease is persisted so that it is
+		// respected across server restarts and replicas.
+		if item.Expires > now.Unix() {
+			continue
+		}
+
 		// if the stage defines concurrency limits we
 		// need to make sure those limits are not exceeded
 		// before proceeding.
@@ -154,6 +213,32 @@ func (q *queue) signal(ctx context.Context) error {
 			continue
 		}
 
+		// if the stage deploys to an environment we need to
+		// make sure no other deployment to the environment is
+		// in progress. If the system defines concurrency quotas
+		// for the namespace or user we need to make sure those
+		// quotas are not exceeded before proceeding. The reason
+		// is persisted so that users can see why the stage is
+		// pending.
+		reason := checkDeploy(item, items, owners)
+		if reason == "" {
+			reason = usage.check(item)
+		}
+		if reason != item.Reason {
+			item.Reason = reason
+			err := q.store.Update(ctx, item)
+			if err != nil {
+				logger := logrus.WithError(err).
+					WithField("build-id", item.BuildID).
+					WithField("stage-id", item.ID)
+				logger.Debugln("queue: cannot update queue item reason")
+				continue
+			}
+		}
+		if reason != "" {
+			continue
+		}
+
 	loop:
 		for w := range q.workers {
 			// the worker must match the resource kind and type
@@ -182,28 +267,32 @@ func (q *queue) signal(ctx context.Context) error {
 				}
 			}
 
-			if len(item.Labels) > 0 || len(w.labels) > 0 {
-				if !checkLabels(item.Labels, w.labels) {
-					continue
-				}
+			if !checkLabels(item.Labels, w.labels) {
+				continue
+			}
+
+			// the runner has a limited amount of time to accept
+			// the item, otherwise it is eligible for processing by
+			// another runner. The update uses optimistic locking,
+			// which prevents multiple servers sharing the same
+			// database from leasing the same item.
+			item.Expires = now.Add(q.lease).Unix()
+			err := q.store.Update(ctx, item)
+			if err != nil {
+				logger := logrus.WithError(err).
+					WithField("build-id", item.BuildID).
+					WithField("stage-id", item.ID)
+				logger.Debugln("queue: cannot lease queue item")
+				break loop
 			}
 
-			// // the queue has 60 seconds to ack the item, otherwise
-			// // it is eligible for processing by another worker.
-			// // item.Expires = time.Now().Add(time.Minute).Unix()
-			// err := q.store.Update(ctx, item)
-
-			// if err != nil {
-			// 	log.Ctx(ctx).Warn().
-			// 		Err(err).
-			// 		Int64("build_id", item.BuildID).
-			// 		Int64("stage_id", item.ID).
-			// 		Msg("cannot update queue item")
-			// 	continue
-			// }
 			select {
 			case w.channel <- item:
 				delete(q.workers, w)
+				usage.add(item)
+				if q.fair != nil {
+					q.fair.record(item, owners, now)
+				}
 				break loop
 			}
 		}
@@ -239,18 +328,6 @@ type counter struct {
 	counts map[string]int
 }
 
-func checkLabels(a, b map[string]string) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for k, v := range a {
-		if w, ok := b[k]; !ok || v != w {
-			return false
-		}
-	}
-	return true
-}
-
 func withinLimits(stage *core.Stage, siblings []*core.Stage) bool {
 	if stage.Limit == 0 {
 		return true
@@ -13,6 +13,7 @@ import (
 	"github.com/drone/drone-go/drone"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/mock"
+	"github.com/drone/drone/store/shared/db"
 
 	"github.com/golang/mock/gomock"
 )
@@ -32,8 +33,9 @@ func TestQueue(t *testing.T) {
 	store.EXPECT().ListIncomplete(ctx).Return(items, nil).Times(1)
 	store.EXPECT().ListIncomplete(ctx).Return(items[1:], nil).Times(1)
 	store.EXPECT().ListIncomplete(ctx).Return(items[2:], nil).Times(1)
+	store.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(3)
 
-	q := newQueue(store)
+	q := newQueue(store, nil, nil, Config{})
 	for _, item := range items {
 		next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
 		if err != nil {
@@ -54,7 +56,7 @@ func TestQueueCancel(t *testing.T) {
 	store := mock.NewMockStageStore(controller)
 	store.EXPECT().ListIncomplete(ctx).Return(nil, nil)
 
-	q := newQueue(store)
+	q := newQueue(store, nil, nil, Config{})
 	q.ctx = ctx
 
 	var wg sync.WaitGroup
@@ -84,6 +86,97 @@ func TestQueueCancel(t *testing.T) {
 	wg.Wait()
 }
 
+// this test verifies that stages are dispatched in order of
+// effective priority, and then in order of age.
+func TestQueuePriority(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	items := []*core.Stage{
+		{ID: 1, OS: "linux", Arch: "amd64", Priority: core.PriorityLow, Created: now.Unix()},
+		{ID: 2, OS: "linux", Arch: "amd64", Priority: core.PriorityNormal, Created: now.Unix()},
+		{ID: 3, OS: "linux", Arch: "amd64", Priority: core.PriorityHigh, Created: now.Unix()},
+		{ID: 4, OS: "linux", Arch: "amd64", Priority: core.PriorityNormal, Created: now.Unix()},
+		{ID: 5, OS: "linux", Arch: "amd64", Priority: core.PriorityLow, Created: now.Add(-time.Hour).Unix()},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil).AnyTimes()
+	store.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(5)
+
+	q := newQueue(store, nil, nil, Config{})
+	for _, want := range []int64{5, 3, 2, 4, 1} {
+		next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got := next.ID; got != want {
+			t.Errorf("Want stage %d, got %d", want, got)
+		}
+	}
+}
+
+// this test verifies that a stage leased to a runner is not
+// handed to another runner until the lease expires.
+func TestQueueLeased(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, OS: "linux", Arch: "amd64", Expires: time.Now().Add(time.Hour).Unix()},
+		{ID: 2, OS: "linux", Arch: "amd64", Expires: time.Now().Add(-time.Hour).Unix()},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil)
+	store.EXPECT().Update(ctx, items[1]).Return(nil)
+
+	q := newQueue(store, nil, nil, Config{Lease: time.Minute})
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next.ID, items[1].ID; got != want {
+		t.Errorf("Want stage %d, got %d", want, got)
+	}
+	if next.Expires <= time.Now().Unix() {
+		t.Errorf("Want lease expiration in the future")
+	}
+}
+
+// this test verifies that a stage is not handed to a runner
+// if the lease is acquired by another server.
+func TestQueueLeaseConflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, OS: "linux", Arch: "amd64"},
+		{ID: 2, OS: "linux", Arch: "amd64"},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil)
+	store.EXPECT().Update(ctx, items[0]).Return(db.ErrOptimisticLock)
+	store.EXPECT().Update(ctx, items[1]).Return(nil)
+
+	q := newQueue(store, nil, nil, Config{})
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next.ID, items[1].ID; got != want {
+		t.Errorf("Want stage %d, got %d", want, got)
+	}
+}
+
 func TestQueuePush(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -356,3 +449,85 @@ func TestWithinLimits_Old(t *testing.T) {
 		}
 	}
 }
+
+// this test verifies that a stage is held with a visible
+// reason when its namespace quota is exhausted, and that
+// stages in other namespaces are dispatched.
+func TestQueueQuota(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, RepoID: 1, BuildID: 1, Status: core.StatusRunning, OS: "linux", Arch: "amd64"},
+		{ID: 2, RepoID: 1, BuildID: 2, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+		{ID: 3, RepoID: 2, BuildID: 3, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+	}
+	quotas := []*core.Quota{
+		{Kind: core.QuotaNamespace, Name: "octocat", Limit: 1},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil)
+	store.EXPECT().Update(ctx, items[1]).Return(nil)
+	store.EXPECT().Update(ctx, items[2]).Return(nil)
+
+	quotaStore := mock.NewMockQuotaStore(controller)
+	quotaStore.EXPECT().List(ctx).Return(quotas, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(ctx, int64(1)).Return(&core.Repository{Namespace: "octocat"}, nil).Times(2)
+	repos.EXPECT().Find(ctx, int64(2)).Return(&core.Repository{Namespace: "spaceghost"}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(ctx, gomock.Any()).Return(&core.Build{Sender: "octocat"}, nil).Times(3)
+
+	q := newQueue(store, quotaStore, newOwners(repos, builds), Config{})
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next.ID, items[2].ID; got != want {
+		t.Errorf("Want stage %d, got %d", want, got)
+	}
+	if got, want := items[1].Reason, "waiting on quota: namespace octocat is limited to 1 concurrent stages"; got != want {
+		t.Errorf("Want reason %q, got %q", want, got)
+	}
+}
+
+// this test verifies the global and user quotas are
+// enforced.
+func TestQuotaCheck(t *testing.T) {
+	u := &usage{
+		limits: map[string]*core.Quota{
+			quotaKey(core.QuotaGlobal, ""):      {Kind: core.QuotaGlobal, Limit: 3},
+			quotaKey(core.QuotaUser, "octocat"): {Kind: core.QuotaUser, Name: "octocat", Limit: 1},
+		},
+		counts: map[string]int{},
+		owners: map[int64]*owner{
+			1: {namespace: "octocat", user: "octocat"},
+			2: {namespace: "octocat", user: "spaceghost"},
+		},
+	}
+	stage1 := &core.Stage{BuildID: 1}
+	stage2 := &core.Stage{BuildID: 2}
+
+	if reason := u.check(stage1); reason != "" {
+		t.Errorf("Want stage dispatched, got reason %q", reason)
+	}
+	u.add(stage1)
+	if got, want := u.check(stage1), "waiting on quota: user octocat is limited to 1 concurrent stages"; got != want {
+		t.Errorf("Want reason %q, got %q", want, got)
+	}
+	u.add(stage2)
+	u.add(stage2)
+	if got, want := u.check(stage2), "waiting on quota: limited to 3 concurrent stages"; got != want {
+		t.Errorf("Want reason %q, got %q", want, got)
+	}
+
+	var nilUsage *usage
+	if reason := nilUsage.check(stage1); reason != "" {
+		t.Errorf("Want nil usage to ignore quotas, got reason %q", reason)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// newUsage returns the quota usage for the incomplete stages.
+// A nil usage is returned if no quotas are defined.
+func newUsage(quotas []*core.Quota, owners map[int64]*owner, items []*core.Stage, now time.Time) *usage {
+	if len(quotas) == 0 {
+		return nil
+	}
+	u := &usage{
+		limits: map[string]*core.Quota{},
+		counts: map[string]int{},
+		owners: owners,
+	}
+	for _, quota := range quotas {
+		u.limits[quotaKey(quota.Kind, quota.Name)] = quota
+	}
+	for _, item := range items {
+		if isActive(item, now) {
+			u.add(item)
+		}
+	}
+	return u
+}
+
+// usage tracks the number of active stages that count
+// against each quota.
+type usage struct {
+	limits map[string]*core.Quota
+	counts map[string]int
+	owners map[int64]*owner
+}
+
+// add counts the stage against its quotas.
+func (u *usage) add(stage *core.Stage) {
+	if u == nil {
+		return
+	}
+	for _, key := range u.keys(stage) {
+		u.counts[key]++
+	}
+}
+
+// check returns a reason the stage must wait for its quota,
+// or an empty string if the stage can be dispatched.
+func (u *usage) check(stage *core.Stage) string {
+	if u == nil {
+		return ""
+	}
+	for _, key := range u.keys(stage) {
+		quota, ok := u.limits[key]
+		if !ok || u.counts[key] < quota.Limit {
+			continue
+		}
+		switch quota.Kind {
+		case core.QuotaGlobal:
+			return fmt.Sprintf("waiting on quota: limited to %d concurrent stages", quota.Limit)
+		default:
+			return fmt.Sprintf("waiting on quota: %s %s is limited to %d concurrent stages", quota.Kind, quota.Name, quota.Limit)
+		}
+	}
+	return ""
+}
+
+// keys returns the quota keys that apply to the stage.
+func (u *usage) keys(stage *core.Stage) []string {
+	keys := []string{quotaKey(core.QuotaGlobal, "")}
+	if owner, ok := u.owners[stage.BuildID]; ok {
+		if owner.namespace != "" {
+			keys = append(keys, quotaKey(core.QuotaNamespace, owner.namespace))
+		}
+		if owner.user != "" {
+			keys = append(keys, quotaKey(core.QuotaUser, owner.user))
+		}
+	}
+	return keys
+}
+
+// helper function returns true if the stage is running, or
+// is pending but already assigned or leased to a runner.
+func isActive(stage *core.Stage, now time.Time) bool {
+	return stage.Status == core.StatusRunning ||
+		stage.Machine != "" ||
+		stage.Expires > now.Unix()
+}
+
+// helper function returns the unique key for a quota.
+func quotaKey(kind, name string) string {
+	return kind + "/" + name
+}
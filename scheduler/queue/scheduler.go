@@ -27,9 +27,15 @@ type scheduler struct {
 }
 
 // New creates a new scheduler.
-func New(store core.StageStore) core.Scheduler {
+func New(
+	store core.StageStore,
+	quotas core.QuotaStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	config Config,
+) core.Scheduler {
 	return &scheduler{
-		queue:     newQueue(store),
+		queue:     newQueue(store, quotas, newOwners(repos, builds), config),
 		canceller: newCanceller(),
 	}
 }
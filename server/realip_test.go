@@ -0,0 +1,77 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	tests := []struct {
+		remote string
+		header map[string]string
+		want   string
+	}{
+		// untrusted peer, headers are ignored
+		{
+			remote: "203.0.113.10:1234",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:   "203.0.113.10:1234",
+		},
+		// trusted peer, client address is forwarded
+		{
+			remote: "10.0.0.1:1234",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:   "198.51.100.1",
+		},
+		// trusted peer, address prepended by the client is skipped
+		{
+			remote: "10.0.0.1:1234",
+			header: map[string]string{"X-Forwarded-For": "192.0.2.1, 198.51.100.1, 10.0.0.2"},
+			want:   "198.51.100.1",
+		},
+		// trusted peer, real ip header
+		{
+			remote: "192.168.1.1:1234",
+			header: map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:   "198.51.100.1",
+		},
+		// trusted peer, invalid header
+		{
+			remote: "10.0.0.1:1234",
+			header: map[string]string{"X-Real-IP": "unknown"},
+			want:   "10.0.0.1:1234",
+		},
+	}
+	for i, test := range tests {
+		var got string
+		h := RealIP([]string{"10.0.0.0/8", "192.168.1.1"})(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}),
+		)
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = test.remote
+		for k, v := range test.header {
+			r.Header.Set(k, v)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if got != test.want {
+			t.Errorf("Want remote address %s, got %s at index %d", test.want, got, i)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+)
+
+// helper function returns the current time.
+var now = time.Now
+
+// Watchdog finds and cancels builds that exceed the build,
+// queue or step timeouts defined in the pipeline configuration.
+type Watchdog struct {
+	Repos    core.RepositoryStore
+	Builds   core.BuildStore
+	Stages   core.StageStore
+	Canceler core.Canceler
+}
+
+// New returns a new Watchdog.
+func New(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	canceler core.Canceler,
+) *Watchdog {
+	return &Watchdog{
+		Repos:    repos,
+		Builds:   builds,
+		Stages:   stages,
+		Canceler: canceler,
+	}
+}
+
+// Start starts the watchdog.
+func (w *Watchdog) Start(ctx context.Context, dur time.Duration) error {
+	ticker := time.NewTicker(dur)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.watch(ctx)
+		}
+	}
+}
+
+func (w *Watchdog) watch(ctx context.Context) error {
+	defer func() {
+		// taking the paranoid approach to recover from
+		// a panic that should absolutely never happen.
+		if r := recover(); r != nil {
+			logrus.Errorf("watchdog: unexpected panic: %s", r)
+			debug.PrintStack()
+		}
+	}()
+
+	logrus.Traceln("watchdog: finding expired builds")
+
+	var result error
+	pending, err := w.Builds.Pending(ctx)
+	if err != nil {
+		logrus.WithError(err).
+			Errorf("watchdog: cannot get pending builds")
+		result = multierror.Append(result, err)
+	}
+	running, err := w.Builds.Running(ctx)
+	if err != nil {
+		logrus.WithError(err).
+			Errorf("watchdog: cannot get running builds")
+		result = multierror.Append(result, err)
+	}
+
+	for _, build := range append(pending, running...) {
+		err := w.check(ctx, build)
+		if err != nil {
+			logrus.WithError(err).
+				WithField("build.id", build.ID).
+				WithField("build.number", build.Number).
+				WithField("build.repo_id", build.RepoID).
+				Errorln("watchdog: cannot cancel build")
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// check cancels the build if the build, or any of the build
+// stages, exceeded a timeout.
+func (w *Watchdog) check(ctx context.Context, build *core.Build) error {
+	stages, err := w.Stages.ListSteps(ctx, build.ID)
+	if err != nil {
+		return err
+	}
+
+	stage, reason := expired(build, stages, now().Unix())
+	if stage == nil {
+		return nil
+	}
+
+	logger := logrus.
+		WithField("build.id", build.ID).
+		WithField("build.number", build.Number).
+		WithField("build.repo_id", build.RepoID).
+		WithField("stage.id", stage.ID).
+		WithField("stage.name", stage.Name).
+		WithField("reason", reason)
+	logger.Debugln("watchdog: cancel build: timeout exceeded")
+
+	repo, err := w.Repos.Find(ctx, build.RepoID)
+	if err != nil {
+		return err
+	}
+
+	// the canceler signals runners to stop execution and
+	// marks the build and all incomplete stages as killed.
+	err = w.Canceler.Cancel(ctx, repo, build)
+	if err != nil {
+		return err
+	}
+
+	// if the build is not killed it completed before it
+	// could be cancelled and should be ignored.
+	if build.Status != core.StatusKilled {
+		return nil
+	}
+
+	build.Status = core.StatusError
+	build.Error = reason
+	err = w.Builds.Update(ctx, build)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("watchdog: cannot update build status")
+	}
+
+	// the stage is re-fetched because the canceler updated
+	// the stage status and version.
+	stage, err = w.Stages.Find(ctx, stage.ID)
+	if err != nil {
+		return err
+	}
+	stage.Status = core.StatusError
+	stage.Error = reason
+	return w.Stages.Update(ctx, stage)
+}
+
+// helper function returns the stage that exceeded a timeout,
+// and the reason, or nil if no timeout is exceeded.
+func expired(build *core.Build, stages []*core.Stage, now int64) (*core.Stage, string) {
+	for _, stage := range stages {
+		if stage.IsDone() {
+			continue
+		}
+		if stage.Timeout.QueueExpired(stage, now) {
+			return stage, fmt.Sprintf("Stage exceeded the queue timeout of %s",
+				seconds(stage.Timeout.Queue))
+		}
+		if stage.Status != core.StatusRunning {
+			continue
+		}
+		for _, step := range stage.Steps {
+			if stage.Timeout.StepExpired(step, now) {
+				return stage, fmt.Sprintf("Step %s exceeded the timeout of %s",
+					step.Name, seconds(stage.Timeout.Steps[step.Name]))
+			}
+		}
+	}
+
+	if build.Timeout <= 0 || build.Started == 0 {
+		return nil, ""
+	}
+	if now <= build.Started+build.Timeout {
+		return nil, ""
+	}
+
+	// the build timeout is recorded on the first running
+	// stage, or the first incomplete stage if no stages are
+	// running.
+	var expired *core.Stage
+	for _, stage := range stages {
+		if stage.IsDone() {
+			continue
+		}
+		if expired == nil || (stage.Status == core.StatusRunning &&
+			expired.Status != core.StatusRunning) {
+			expired = stage
+		}
+	}
+	if expired == nil {
+		return nil, ""
+	}
+	return expired, fmt.Sprintf("Build exceeded the timeout of %s",
+		seconds(build.Timeout))
+}
+
+// helper function returns the duration in seconds.
+func seconds(v int64) time.Duration {
+	return time.Duration(v) * time.Second
+}
@@ -0,0 +1,205 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var nocontext = context.Background()
+
+// this test confirms that a build with a running step that
+// exceeds the step timeout is cancelled, and the timeout is
+// recorded on the build and stage.
+func TestWatchStepTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer func() {
+		now = time.Now
+	}()
+	now = func() time.Time {
+		return time.Unix(10000, 0)
+	}
+
+	mockRepo := &core.Repository{
+		ID: 2,
+	}
+	mockBuild := &core.Build{
+		ID:      1,
+		RepoID:  mockRepo.ID,
+		Status:  core.StatusRunning,
+		Started: 9000,
+	}
+	mockStage := &core.Stage{
+		ID:      3,
+		BuildID: mockBuild.ID,
+		Name:    "default",
+		Status:  core.StatusRunning,
+		Started: 9000,
+		Timeout: &core.TimeoutPolicy{
+			Steps: map[string]int64{"test": 600},
+		},
+		Steps: []*core.Step{
+			{Name: "clone", Status: core.StatusPassing, Started: 9000},
+			{Name: "test", Status: core.StatusRunning, Started: 9100},
+		},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockBuild.RepoID).Return(mockRepo, nil)
+
+	checkBuild := func(_ context.Context, build *core.Build) error {
+		if got, want := build.Status, core.StatusError; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if got, want := build.Error, "Step test exceeded the timeout of 10m0s"; got != want {
+			t.Errorf("Want build error %q, got %q", want, got)
+		}
+		return nil
+	}
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Pending(gomock.Any()).Return(nil, nil)
+	builds.EXPECT().Running(gomock.Any()).Return([]*core.Build{mockBuild}, nil)
+	builds.EXPECT().Update(gomock.Any(), mockBuild).DoAndReturn(checkBuild)
+
+	killed := &core.Stage{ID: mockStage.ID, Status: core.StatusKilled}
+	checkStage := func(_ context.Context, stage *core.Stage) error {
+		if got, want := stage.Status, core.StatusError; got != want {
+			t.Errorf("Want stage status %s, got %s", want, got)
+		}
+		if got, want := stage.Error, "Step test exceeded the timeout of 10m0s"; got != want {
+			t.Errorf("Want stage error %q, got %q", want, got)
+		}
+		return nil
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return([]*core.Stage{mockStage}, nil)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(killed, nil)
+	stages.EXPECT().Update(gomock.Any(), killed).DoAndReturn(checkStage)
+
+	canceler := mock.NewMockCanceler(controller)
+	canceler.EXPECT().Cancel(gomock.Any(), mockRepo, mockBuild).DoAndReturn(
+		func(_ context.Context, _ *core.Repository, build *core.Build) error {
+			build.Status = core.StatusKilled
+			return nil
+		},
+	)
+
+	w := New(repos, builds, stages, canceler)
+	if err := w.watch(nocontext); err != nil {
+		t.Error(err)
+	}
+}
+
+// this test confirms that builds that do not exceed any
+// timeout are ignored.
+func TestWatchIgnore(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer func() {
+		now = time.Now
+	}()
+	now = func() time.Time {
+		return time.Unix(10000, 0)
+	}
+
+	mockBuild := &core.Build{
+		ID:      1,
+		Status:  core.StatusRunning,
+		Started: 9000,
+		Timeout: 3600,
+	}
+	mockStages := []*core.Stage{
+		{
+			Status:  core.StatusPending,
+			Updated: 9500,
+			Timeout: &core.TimeoutPolicy{Queue: 600},
+		},
+	}
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Pending(gomock.Any()).Return(nil, nil)
+	builds.EXPECT().Running(gomock.Any()).Return([]*core.Build{mockBuild}, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+
+	w := New(nil, builds, stages, nil)
+	if err := w.watch(nocontext); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	tests := []struct {
+		build  *core.Build
+		stages []*core.Stage
+		stage  int
+		reason string
+	}{
+		// queue timeout exceeded
+		{
+			build: &core.Build{Status: core.StatusPending},
+			stages: []*core.Stage{
+				{Status: core.StatusPending, Updated: 1000, Timeout: &core.TimeoutPolicy{Queue: 1800}},
+			},
+			stage:  0,
+			reason: "Stage exceeded the queue timeout of 30m0s",
+		},
+		// queue timeout not exceeded
+		{
+			build: &core.Build{Status: core.StatusPending},
+			stages: []*core.Stage{
+				{Status: core.StatusPending, Updated: 9000, Timeout: &core.TimeoutPolicy{Queue: 1800}},
+			},
+			stage: -1,
+		},
+		// build timeout exceeded, recorded on the running stage
+		{
+			build: &core.Build{Status: core.StatusRunning, Started: 1000, Timeout: 3600},
+			stages: []*core.Stage{
+				{Status: core.StatusPassing},
+				{Status: core.StatusWaiting},
+				{Status: core.StatusRunning},
+			},
+			stage:  2,
+			reason: "Build exceeded the timeout of 1h0m0s",
+		},
+		// build timeout not defined
+		{
+			build: &core.Build{Status: core.StatusRunning, Started: 1000},
+			stages: []*core.Stage{
+				{Status: core.StatusRunning},
+			},
+			stage: -1,
+		},
+	}
+	for i, test := range tests {
+		stage, reason := expired(test.build, test.stages, 10000)
+		if test.stage == -1 {
+			if stage != nil {
+				t.Errorf("Want no expired stage at index %d", i)
+			}
+			continue
+		}
+		if stage != test.stages[test.stage] {
+			t.Errorf("Want expired stage %d at index %d", test.stage, i)
+		}
+		if got, want := reason, test.reason; got != want {
+			t.Errorf("Want reason %q at index %d, got %q", want, i, got)
+		}
+	}
+}
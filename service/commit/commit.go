@@ -123,7 +123,47 @@ func (s *service) ListChanges(ctx context.Context, user *core.User, repo, sha, r
 		Token:   user.Token,
 		Refresh: user.Refresh,
 	})
-	out, _, err := s.client.Git.ListChanges(ctx, repo, sha, scm.ListOptions{Size: 100})
+	var out []*scm.Change
+	// pull request changes are fetched using the pull request
+	// number, since the commit changeset only includes the
+	// files modified by the most recent commit.
+	if scm.IsPullRequest(ref) {
+		number := scm.ExtractPullRequest(ref)
+		out, err = listAll(maxChanges, func(opts scm.ListOptions) ([]*scm.Change, *scm.Response, error) {
+			return s.client.PullRequests.ListChanges(ctx, repo, number, opts)
+		})
+	} else {
+		out, err = listAll(maxChanges, func(opts scm.ListOptions) ([]*scm.Change, *scm.Response, error) {
+			return s.client.Git.ListChanges(ctx, repo, sha, opts)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	var changes []*core.Change
+	for _, change := range out {
+		changes = append(changes, &core.Change{
+			Path:    change.Path,
+			Added:   change.Added,
+			Renamed: change.Renamed,
+			Deleted: change.Deleted,
+		})
+	}
+	return changes, nil
+}
+
+func (s *service) CompareChanges(ctx context.Context, user *core.User, repo, source, target string) ([]*core.Change, error) {
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+	out, err := listAll(0, func(opts scm.ListOptions) ([]*scm.Change, *scm.Response, error) {
+		return s.client.Git.CompareChanges(ctx, repo, source, target, opts)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -138,3 +178,30 @@ func (s *service) ListChanges(ctx context.Context, user *core.User, repo, sha, r
 	}
 	return changes, nil
 }
+
+// maxChanges defines the maximum number of changed files
+// fetched for a commit or pull request. The trigger runs every
+// pipeline when the changeset reaches this limit, so there is
+// no need to fetch additional pages.
+const maxChanges = 300
+
+// helper function fetches every page of changed files, up to
+// the limit. A zero limit fetches every page.
+func listAll(limit int, fn func(scm.ListOptions) ([]*scm.Change, *scm.Response, error)) ([]*scm.Change, error) {
+	var out []*scm.Change
+	opts := scm.ListOptions{Page: 1, Size: 100}
+	for {
+		changes, res, err := fn(opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, changes...)
+		if limit > 0 && len(out) >= limit {
+			return out, nil
+		}
+		if res == nil || res.Page.Next <= opts.Page {
+			return out, nil
+		}
+		opts.Page = res.Page.Next
+	}
+}
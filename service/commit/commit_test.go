@@ -306,6 +306,75 @@ func TestListChanges(t *testing.T) {
 	}
 }
 
+func TestListChanges_Paginated(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockPage1 := []*scm.Change{{Path: "file1"}}
+	mockPage2 := []*scm.Change{{Path: "file2"}}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().ListChanges(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", scm.ListOptions{Page: 1, Size: 100}).Return(mockPage1, &scm.Response{Page: scm.Page{Next: 2}}, nil)
+	mockGit.EXPECT().ListChanges(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", scm.ListOptions{Page: 2, Size: 100}).Return(mockPage2, &scm.Response{}, nil)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	want := []*core.Change{
+		{Path: "file1"},
+		{Path: "file2"},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.ListChanges(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestListChanges_PullRequest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockChanges := []*scm.Change{
+		{Path: "file1"},
+		{Path: "file2", Added: true},
+	}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListChanges(gomock.Any(), "octocat/hello-world", 12, gomock.Any()).Return(mockChanges, nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	want := []*core.Change{
+		{Path: "file1"},
+		{Path: "file2", Added: true},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.ListChanges(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "refs/pull/12/head")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
 func TestListChanges_Err(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -343,3 +412,94 @@ func TestListChanges_ErrRenew(t *testing.T) {
 		t.Errorf("Want not authorized error, got %v", err)
 	}
 }
+
+func TestCompareChanges(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockChanges := []*scm.Change{
+		{Path: "file1"},
+		{Path: "file2", Added: true},
+	}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().CompareChanges(gomock.Any(), "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", gomock.Any()).Return(mockChanges, nil, nil)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	want := []*core.Change{
+		{Path: "file1"},
+		{Path: "file2", Added: true},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.CompareChanges(noContext, mockUser, "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestCompareChanges_Paginated(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockPage1 := []*scm.Change{{Path: "file1"}}
+	mockPage2 := []*scm.Change{{Path: "file2"}}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().CompareChanges(gomock.Any(), "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", scm.ListOptions{Page: 1, Size: 100}).Return(mockPage1, &scm.Response{Page: scm.Page{Next: 2}}, nil)
+	mockGit.EXPECT().CompareChanges(gomock.Any(), "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", scm.ListOptions{Page: 2, Size: 100}).Return(mockPage2, &scm.Response{}, nil)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	want := []*core.Change{
+		{Path: "file1"},
+		{Path: "file2"},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.CompareChanges(noContext, mockUser, "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestCompareChanges_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().CompareChanges(gomock.Any(), "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", gomock.Any()).Return(nil, nil, scm.ErrNotFound)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	service := New(client, mockRenewer)
+	_, err := service.CompareChanges(noContext, mockUser, "octocat/hello-world", "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa")
+	if err != scm.ErrNotFound {
+		t.Errorf("Want not found error, got %v", err)
+	}
+}
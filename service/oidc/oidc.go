@@ -0,0 +1,181 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var errKeyActive = errors.New("oidc: active signing key not found")
+
+// Config provides the identity token configuration.
+type Config struct {
+	// Issuer is the token issuer, typically the server
+	// address. The discovery document and public keys
+	// are published relative to the issuer.
+	Issuer string
+
+	// Audience is the intended token audience. If empty,
+	// the issuer is used as the audience.
+	Audience string
+
+	// Expiry is the token lifetime.
+	Expiry time.Duration
+
+	// Keys provides named PEM encoded RSA private keys.
+	Keys map[string][]byte
+
+	// Active is the name of the key used to sign tokens.
+	Active string
+}
+
+// claims defines the pipeline identity token claims.
+type claims struct {
+	jwt.StandardClaims
+	Repo         string `json:"repo"`
+	Ref          string `json:"ref"`
+	Event        string `json:"event"`
+	BuildNumber  int64  `json:"build_number"`
+	DeployTarget string `json:"deploy_target,omitempty"`
+	Pipeline     string `json:"pipeline"`
+}
+
+// New returns a new identity service. If no signing keys are
+// configured, identity tokens are disabled.
+func New(config Config) (core.IdentityService, error) {
+	if len(config.Keys) == 0 {
+		return &noop{issuer: config.Issuer}, nil
+	}
+	keys := map[string]*rsa.PrivateKey{}
+	for id, data := range config.Keys {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("oidc: cannot parse signing key %q: %s", id, err)
+		}
+		keys[id] = key
+	}
+	if _, ok := keys[config.Active]; !ok {
+		return nil, errKeyActive
+	}
+	audience := config.Audience
+	if audience == "" {
+		audience = config.Issuer
+	}
+	return &service{
+		issuer:   config.Issuer,
+		audience: audience,
+		expiry:   config.Expiry,
+		keys:     keys,
+		active:   config.Active,
+	}, nil
+}
+
+type service struct {
+	issuer   string
+	audience string
+	expiry   time.Duration
+	keys     map[string]*rsa.PrivateKey
+	active   string
+}
+
+func (s *service) Issuer() string {
+	return s.issuer
+}
+
+func (s *service) Token(ctx context.Context, args *core.IdentityArgs) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, &claims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    s.issuer,
+			Subject:   subject(args.Repo, args.Build),
+			Audience:  s.audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(s.expiry).Unix(),
+		},
+		Repo:         args.Repo.Slug,
+		Ref:          args.Build.Ref,
+		Event:        args.Build.Event,
+		BuildNumber:  args.Build.Number,
+		DeployTarget: args.Build.Deploy,
+		Pipeline:     args.Stage.Name,
+	})
+	token.Header["kid"] = s.active
+	return token.SignedString(s.keys[s.active])
+}
+
+func (s *service) Keys(ctx context.Context) ([]*core.JSONWebKey, error) {
+	var keys []*core.JSONWebKey
+	for id, key := range s.keys {
+		keys = append(keys, &core.JSONWebKey{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: id,
+			N:   encode(key.PublicKey.N.Bytes()),
+			E:   encode(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Kid < keys[j].Kid
+	})
+	return keys, nil
+}
+
+// helper function returns the token subject. The subject
+// uniquely identifies the repository and the reference, or
+// the deployment target, so that trust policies can be
+// scoped to a branch or environment.
+func subject(repo *core.Repository, build *core.Build) string {
+	switch build.Event {
+	case core.EventPromote, core.EventRollback:
+		return fmt.Sprintf("repo:%s:deploy:%s", repo.Slug, build.Deploy)
+	default:
+		return fmt.Sprintf("repo:%s:ref:%s", repo.Slug, build.Ref)
+	}
+}
+
+// helper function returns the unpadded base64 url encoding
+// of the big-endian integer bytes.
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+type noop struct {
+	issuer string
+}
+
+func (s *noop) Issuer() string {
+	return s.issuer
+}
+
+func (s *noop) Token(context.Context, *core.IdentityArgs) (string, error) {
+	return "", nil
+}
+
+func (s *noop) Keys(context.Context) ([]*core.JSONWebKey, error) {
+	return []*core.JSONWebKey{}, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var noContext = context.Background()
+
+func TestToken(t *testing.T) {
+	key1, data1 := generateKey(t)
+	_, data2 := generateKey(t)
+
+	service, err := New(Config{
+		Issuer: "https://drone.company.com",
+		Expiry: time.Hour,
+		Keys:   map[string][]byte{"k1": data1, "k2": data2},
+		Active: "k1",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	args := &core.IdentityArgs{
+		Repo:  &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{Number: 42, Ref: "refs/heads/master", Event: core.EventPush},
+		Stage: &core.Stage{Name: "default"},
+	}
+	signed, err := service.Token(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	out := new(claims)
+	token, err := jwt.ParseWithClaims(signed, out, func(token *jwt.Token) (interface{}, error) {
+		if got, want := token.Header["kid"], "k1"; got != want {
+			t.Errorf("Want key id %q, got %q", want, got)
+		}
+		return &key1.PublicKey, nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !token.Valid {
+		t.Errorf("Want valid token")
+	}
+	if got, want := out.Subject, "repo:octocat/hello-world:ref:refs/heads/master"; got != want {
+		t.Errorf("Want subject %q, got %q", want, got)
+	}
+	if got, want := out.Issuer, "https://drone.company.com"; got != want {
+		t.Errorf("Want issuer %q, got %q", want, got)
+	}
+	if got, want := out.Audience, "https://drone.company.com"; got != want {
+		t.Errorf("Want audience %q, got %q", want, got)
+	}
+	if got, want := out.BuildNumber, int64(42); got != want {
+		t.Errorf("Want build number %d, got %d", want, got)
+	}
+	if got, want := out.Pipeline, "default"; got != want {
+		t.Errorf("Want pipeline %q, got %q", want, got)
+	}
+	if got, want := out.ExpiresAt-out.IssuedAt, int64(3600); got != want {
+		t.Errorf("Want token expiry %d, got %d", want, got)
+	}
+}
+
+func TestSubject_Deploy(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{Event: core.EventPromote, Deploy: "production"}
+	if got, want := subject(repo, build), "repo:octocat/hello-world:deploy:production"; got != want {
+		t.Errorf("Want subject %q, got %q", want, got)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	key, data := generateKey(t)
+	service, err := New(Config{
+		Keys:   map[string][]byte{"k1": data},
+		Active: "k1",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	keys, err := service.Keys(noContext)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(keys), 1; got != want {
+		t.Errorf("Want %d keys, got %d", want, got)
+		return
+	}
+	if got, want := keys[0].Kid, "k1"; got != want {
+		t.Errorf("Want key id %q, got %q", want, got)
+	}
+	n, _ := base64.RawURLEncoding.DecodeString(keys[0].N)
+	if new(big.Int).SetBytes(n).Cmp(key.PublicKey.N) != 0 {
+		t.Errorf("Want public key modulus published")
+	}
+	if got, want := keys[0].E, "AQAB"; got != want {
+		t.Errorf("Want public key exponent %q, got %q", want, got)
+	}
+}
+
+func TestNew_ActiveKeyNotFound(t *testing.T) {
+	_, data := generateKey(t)
+	_, err := New(Config{
+		Keys:   map[string][]byte{"k1": data},
+		Active: "k2",
+	})
+	if err != errKeyActive {
+		t.Errorf("Want error %v, got %v", errKeyActive, err)
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	service, err := New(Config{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	token, err := service.Token(noContext, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if token != "" {
+		t.Errorf("Want empty token when disabled")
+	}
+}
+
+func generateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
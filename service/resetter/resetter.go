@@ -0,0 +1,107 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resetter
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+type service struct {
+	artifacts core.ArtifactStore
+	blobs     core.ArtifactBlobStore
+	logs      core.LogStore
+	steps     core.StepStore
+	tests     core.TestStore
+}
+
+// New returns a new reset service that purges the data stored
+// for the steps of a stage before the stage is executed again.
+func New(
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
+	logs core.LogStore,
+	steps core.StepStore,
+	tests core.TestStore,
+) core.Resetter {
+	return &service{
+		artifacts: artifacts,
+		blobs:     blobs,
+		logs:      logs,
+		steps:     steps,
+		tests:     tests,
+	}
+}
+
+// Reset purges the steps of the stage and the logs, artifacts
+// and test results of each step, and clears the stage execution
+// state. The logs and artifact contents may be kept in external
+// storage that does not cascade when the step is deleted, and are
+// therefore deleted explicitly.
+func (s *service) Reset(ctx context.Context, stage *core.Stage) error {
+	logger := logrus.WithField("stage.id", stage.ID)
+
+	steps, err := s.steps.List(ctx, stage.ID)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		// the logs may not exist if the step did not run,
+		// and the error is therefore ignored.
+		s.logs.Delete(ctx, step.ID)
+
+		err := s.tests.Delete(ctx, step.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	artifacts, err := s.artifacts.List(ctx, stage.ID)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		err := s.blobs.Delete(ctx, artifact.ID)
+		if err != nil {
+			logger.WithError(err).
+				WithField("artifact.id", artifact.ID).
+				Warnln("resetter: cannot delete the artifact contents")
+		}
+		err = s.artifacts.Delete(ctx, artifact)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the steps are re-created by the runner when the stage
+	// is accepted.
+	err = s.steps.Purge(ctx, stage.ID)
+	if err != nil {
+		return err
+	}
+
+	stage.Error = ""
+	stage.ExitCode = 0
+	stage.Machine = ""
+	stage.Started = 0
+	stage.Stopped = 0
+	stage.Steps = nil
+	stage.Updated = time.Now().Unix()
+	return nil
+}
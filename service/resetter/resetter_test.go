@@ -0,0 +1,71 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package resetter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var noContext = context.Background()
+
+func TestReset(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStage := &core.Stage{
+		ID:       1,
+		Status:   core.StatusFailing,
+		Error:    "oom",
+		ExitCode: 137,
+		Machine:  "runner-1",
+		Started:  1000,
+		Stopped:  1060,
+	}
+	mockSteps := []*core.Step{
+		{ID: 2, StageID: 1},
+		{ID: 3, StageID: 1},
+	}
+	mockArtifact := &core.Artifact{ID: 4, StageID: 1, StepID: 3, Name: "dist/app"}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().List(gomock.Any(), mockStage.ID).Return(mockSteps, nil)
+	steps.EXPECT().Purge(gomock.Any(), mockStage.ID).Return(nil)
+
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
+	logs.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+
+	tests := mock.NewMockTestStore(controller)
+	tests.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
+	tests.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().List(gomock.Any(), mockStage.ID).Return([]*core.Artifact{mockArtifact}, nil)
+	artifacts.EXPECT().Delete(gomock.Any(), mockArtifact).Return(nil)
+
+	blobs := mock.NewMockArtifactBlobStore(controller)
+	blobs.EXPECT().Delete(gomock.Any(), mockArtifact.ID).Return(nil)
+
+	err := New(artifacts, blobs, logs, steps, tests).Reset(noContext, mockStage)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if mockStage.Error != "" || mockStage.ExitCode != 0 || mockStage.Machine != "" {
+		t.Errorf("Want stage execution details reset")
+	}
+	if mockStage.Started != 0 || mockStage.Stopped != 0 {
+		t.Errorf("Want stage timestamps reset")
+	}
+	if got, want := mockStage.Status, core.StatusFailing; got != want {
+		t.Errorf("Want stage status unchanged, got %s", got)
+	}
+}
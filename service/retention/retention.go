@@ -0,0 +1,358 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention purges builds, logs and artifacts that are
+// older than the configured retention policy.
+package retention
+
+import (
+	"context"
+	"math"
+	"runtime/debug"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+)
+
+// page defines the number of records loaded from the
+// datastore in each batch.
+const page = 100
+
+// day is the duration of a retention day.
+const day = time.Hour * 24
+
+// Retention purges builds and logs that are older than the
+// configured retention policy.
+type Retention struct {
+	Repos     core.RepositoryStore
+	Builds    core.BuildStore
+	Stages    core.StageStore
+	Logs      core.LogStore
+	Artifacts core.ArtifactStore
+	Blobs     core.ArtifactBlobStore
+	Policies  core.RetentionStore
+	Default   core.Retention // Default is the default global policy
+
+	// purged tracks the highest build number, per repository,
+	// for which logs have already been purged. This prevents
+	// purging the same logs on every run.
+	purged map[int64]int64
+}
+
+// New returns a new Retention.
+func New(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	logs core.LogStore,
+	artifacts core.ArtifactStore,
+	blobs core.ArtifactBlobStore,
+	policies core.RetentionStore,
+	defaults core.Retention,
+) *Retention {
+	defaults.Kind = core.RetentionGlobal
+	return &Retention{
+		Repos:     repos,
+		Builds:    builds,
+		Stages:    stages,
+		Logs:      logs,
+		Artifacts: artifacts,
+		Blobs:     blobs,
+		Policies:  policies,
+		Default:   defaults,
+		purged:    map[int64]int64{},
+	}
+}
+
+// Start starts the retention process.
+func (r *Retention) Start(ctx context.Context, dur time.Duration) error {
+	ticker := time.NewTicker(dur)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.run(ctx, time.Now())
+		}
+	}
+}
+
+func (r *Retention) run(ctx context.Context, now time.Time) error {
+	defer func() {
+		// taking the paranoid approach to recover from
+		// a panic that should absolutely never happen.
+		if r := recover(); r != nil {
+			logrus.Errorf("retention: unexpected panic: %s", r)
+			debug.PrintStack()
+		}
+	}()
+
+	policies, err := r.policies(ctx)
+	if err != nil {
+		logrus.WithError(err).
+			Errorln("retention: cannot list retention policies")
+		return err
+	}
+	if policies.isZero() {
+		logrus.Traceln("retention: no retention policies defined")
+		return nil
+	}
+
+	var result error
+	for offset := 0; ; offset += page {
+		repos, err := r.Repos.ListAll(ctx, page, offset)
+		if err != nil {
+			logrus.WithError(err).
+				Errorln("retention: cannot list repositories")
+			return multierror.Append(result, err)
+		}
+		for _, repo := range repos {
+			policy := policies.match(repo)
+			if policy.IsZero() {
+				continue
+			}
+			err := r.purge(ctx, repo, policy, now)
+			if err != nil {
+				logrus.WithError(err).
+					WithField("repo", repo.Slug).
+					Errorln("retention: cannot purge repository")
+				result = multierror.Append(result, err)
+			}
+		}
+		if len(repos) < page {
+			return result
+		}
+	}
+}
+
+// purge purges the builds and logs of the repository that are
+// not retained by the policy. The builds are loaded from the
+// datastore one page at a time, in descending order.
+func (r *Retention) purge(ctx context.Context, repo *core.Repository, policy *core.Retention, now time.Time) error {
+	var (
+		result  error
+		purged  int64 // highest build number with purged logs
+		skipped int64 // lowest build number that was not purged
+		index   int
+		before  = int64(math.MaxInt64)
+	)
+	skip := func(build *core.Build) {
+		if skipped == 0 || build.Number < skipped {
+			skipped = build.Number
+		}
+	}
+
+	// if the policy only limits logs, builds at or below the
+	// watermark were purged in a previous run and the older
+	// builds do not need to be loaded.
+	logsOnly := policy.Builds == 0 && policy.Days == 0
+
+loop:
+	for {
+		builds, err := r.Builds.ListBefore(ctx, repo.ID, before, page)
+		if err != nil {
+			return multierror.Append(result, err)
+		}
+		for _, build := range builds {
+			i := index
+			index++
+			if logsOnly && build.Number <= r.purged[repo.ID] {
+				break loop
+			}
+
+			expired := isExpired(policy, build, i, now)
+			logExpired := isLogExpired(policy, build, now) && build.Number > r.purged[repo.ID]
+			if !expired && !logExpired {
+				continue
+			}
+			// incomplete builds are skipped, and the watermark
+			// does not advance past them so that they are
+			// purged once complete.
+			if !build.IsDone() {
+				skip(build)
+				continue
+			}
+
+			logger := logrus.
+				WithField("repo", repo.Slug).
+				WithField("build.number", build.Number)
+			if expired {
+				logger.Debugln("retention: purge build")
+			} else {
+				logger.Debugln("retention: purge build logs")
+			}
+			if err := r.deleteLogs(ctx, build); err != nil {
+				result = multierror.Append(result, err)
+				skip(build)
+				continue
+			}
+			if err := r.deleteArtifacts(ctx, build); err != nil {
+				result = multierror.Append(result, err)
+				skip(build)
+				continue
+			}
+			if expired {
+				if err := r.Builds.Delete(ctx, build); err != nil {
+					result = multierror.Append(result, err)
+					skip(build)
+					continue
+				}
+			}
+			if build.Number > purged {
+				purged = build.Number
+			}
+		}
+		if len(builds) < page {
+			break
+		}
+		before = builds[len(builds)-1].Number
+	}
+
+	// the watermark advances to the highest purged build, but
+	// not past a build that was skipped.
+	if skipped != 0 && skipped <= purged {
+		purged = skipped - 1
+	}
+	if purged > r.purged[repo.ID] {
+		r.purged[repo.ID] = purged
+	}
+	return result
+}
+
+// deleteLogs deletes the logs of every build step from the
+// log store.
+func (r *Retention) deleteLogs(ctx context.Context, build *core.Build) error {
+	stages, err := r.Stages.ListSteps(ctx, build.ID)
+	if err != nil {
+		return err
+	}
+	for _, stage := range stages {
+		for _, step := range stage.Steps {
+			if err := r.Logs.Delete(ctx, step.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteArtifacts deletes the build artifacts, and their
+// contents, from the artifact store. Artifacts expire with
+// the build logs.
+func (r *Retention) deleteArtifacts(ctx context.Context, build *core.Build) error {
+	artifacts, err := r.Artifacts.ListBuild(ctx, build.ID)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		if err := r.Blobs.Delete(ctx, artifact.ID); err != nil {
+			return err
+		}
+		if err := r.Artifacts.Delete(ctx, artifact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policies returns the retention policies.
+func (r *Retention) policies(ctx context.Context) (*policySet, error) {
+	list, err := r.Policies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := &policySet{
+		global:     &r.Default,
+		namespaces: map[string]*core.Retention{},
+		repos:      map[string]*core.Retention{},
+	}
+	for _, policy := range list {
+		switch policy.Kind {
+		case core.RetentionGlobal:
+			set.global = policy
+		case core.RetentionNamespace:
+			set.namespaces[policy.Name] = policy
+		case core.RetentionRepository:
+			set.repos[policy.Name] = policy
+		}
+	}
+	return set, nil
+}
+
+// policySet provides the global retention policy, and the
+// namespace and repository policies that override it.
+type policySet struct {
+	global     *core.Retention
+	namespaces map[string]*core.Retention
+	repos      map[string]*core.Retention
+}
+
+// match returns the most specific policy for the repository.
+func (s *policySet) match(repo *core.Repository) *core.Retention {
+	if policy, ok := s.repos[repo.Slug]; ok {
+		return policy
+	}
+	if policy, ok := s.namespaces[repo.Namespace]; ok {
+		return policy
+	}
+	return s.global
+}
+
+// isZero returns true if no policy limits builds or logs.
+func (s *policySet) isZero() bool {
+	if !s.global.IsZero() {
+		return false
+	}
+	for _, policy := range s.namespaces {
+		if !policy.IsZero() {
+			return false
+		}
+	}
+	for _, policy := range s.repos {
+		if !policy.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// helper function returns true if the build is not retained
+// by the policy. The index is the position of the build in the
+// build history, where zero is the most recent build.
+func isExpired(policy *core.Retention, build *core.Build, index int, now time.Time) bool {
+	if policy.Builds > 0 && index >= policy.Builds {
+		return true
+	}
+	if policy.Days > 0 && isOlder(build, policy.Days, now) {
+		return true
+	}
+	return false
+}
+
+// helper function returns true if the build logs are not
+// retained by the policy.
+func isLogExpired(policy *core.Retention, build *core.Build, now time.Time) bool {
+	return policy.LogDays > 0 && isOlder(build, policy.LogDays, now)
+}
+
+// helper function returns true if the build was created
+// more than the number of days ago.
+func isOlder(build *core.Build, days int, now time.Time) bool {
+	return build.Created < now.Add(-time.Duration(days)*day).Unix()
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package retention
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var nocontext = context.Background()
+
+// this test confirms that builds exceeding the number of
+// builds to keep are deleted along with their logs, that logs
+// exceeding the log retention are deleted while the build is
+// kept, and that incomplete builds are ignored.
+func TestRun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	day := int64(24 * 60 * 60)
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Slug: "octocat/hello-world"}
+	mockBuilds := []*core.Build{
+		{ID: 5, Number: 5, Status: core.StatusRunning, Created: now.Unix()},
+		{ID: 4, Number: 4, Status: core.StatusPassing, Created: now.Unix()},
+		{ID: 3, Number: 3, Status: core.StatusPassing, Created: now.Unix() - 10*day},
+		{ID: 2, Number: 2, Status: core.StatusPassing, Created: now.Unix() - 20*day},
+	}
+	mockPolicies := []*core.Retention{
+		{Kind: core.RetentionNamespace, Name: "octocat", Builds: 3, LogDays: 7},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListAll(gomock.Any(), page, 0).Return([]*core.Repository{mockRepo}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListBefore(gomock.Any(), mockRepo.ID, int64(math.MaxInt64), page).Return(mockBuilds, nil)
+	builds.EXPECT().Delete(gomock.Any(), mockBuilds[3]).Return(nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), int64(3)).Return([]*core.Stage{{Steps: []*core.Step{{ID: 30}}}}, nil)
+	stages.EXPECT().ListSteps(gomock.Any(), int64(2)).Return([]*core.Stage{{Steps: []*core.Step{{ID: 20}, {ID: 21}}}}, nil)
+
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Delete(gomock.Any(), int64(30)).Return(nil)
+	logs.EXPECT().Delete(gomock.Any(), int64(20)).Return(nil)
+	logs.EXPECT().Delete(gomock.Any(), int64(21)).Return(nil)
+
+	mockArtifact := &core.Artifact{ID: 40, BuildID: 3, Name: "report.xml"}
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListBuild(gomock.Any(), int64(3)).Return([]*core.Artifact{mockArtifact}, nil)
+	artifacts.EXPECT().ListBuild(gomock.Any(), int64(2)).Return(nil, nil)
+	artifacts.EXPECT().Delete(gomock.Any(), mockArtifact).Return(nil)
+
+	blobs := mock.NewMockArtifactBlobStore(controller)
+	blobs.EXPECT().Delete(gomock.Any(), mockArtifact.ID).Return(nil)
+
+	policies := mock.NewMockRetentionStore(controller)
+	policies.EXPECT().List(gomock.Any()).Return(mockPolicies, nil)
+
+	r := New(repos, builds, stages, logs, artifacts, blobs, policies, core.Retention{})
+	err := r.run(nocontext, now)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := r.purged[mockRepo.ID], int64(3); got != want {
+		t.Errorf("Want purged logs up to build %d, got %d", want, got)
+	}
+}
+
+// this test confirms that logs are not purged again for
+// builds that were purged in a previous run.
+func TestRun_Purged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	day := int64(24 * 60 * 60)
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Slug: "octocat/hello-world"}
+	mockBuilds := []*core.Build{
+		{ID: 3, Number: 3, Status: core.StatusPassing, Created: now.Unix() - 10*day},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListAll(gomock.Any(), page, 0).Return([]*core.Repository{mockRepo}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListBefore(gomock.Any(), mockRepo.ID, int64(math.MaxInt64), page).Return(mockBuilds, nil)
+
+	policies := mock.NewMockRetentionStore(controller)
+	policies.EXPECT().List(gomock.Any()).Return(nil, nil)
+
+	r := New(repos, builds, nil, nil, nil, nil, policies, core.Retention{LogDays: 7})
+	r.purged[mockRepo.ID] = 3
+	err := r.run(nocontext, now)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test confirms that the logs of incomplete builds are
+// not purged, and that the watermark does not advance past
+// incomplete builds so that their logs are purged once the
+// builds complete.
+func TestRun_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	day := int64(24 * 60 * 60)
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Slug: "octocat/hello-world"}
+	mockBuilds := []*core.Build{
+		{ID: 4, Number: 4, Status: core.StatusPassing, Created: now.Unix() - 10*day},
+		{ID: 3, Number: 3, Status: core.StatusRunning, Created: now.Unix() - 10*day},
+		{ID: 2, Number: 2, Status: core.StatusPassing, Created: now.Unix() - 10*day},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListAll(gomock.Any(), page, 0).Return([]*core.Repository{mockRepo}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListBefore(gomock.Any(), mockRepo.ID, int64(math.MaxInt64), page).Return(mockBuilds, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), int64(4)).Return(nil, nil)
+	stages.EXPECT().ListSteps(gomock.Any(), int64(2)).Return(nil, nil)
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListBuild(gomock.Any(), int64(4)).Return(nil, nil)
+	artifacts.EXPECT().ListBuild(gomock.Any(), int64(2)).Return(nil, nil)
+
+	policies := mock.NewMockRetentionStore(controller)
+	policies.EXPECT().List(gomock.Any()).Return(nil, nil)
+
+	r := New(repos, builds, stages, nil, artifacts, nil, policies, core.Retention{LogDays: 7})
+	err := r.run(nocontext, now)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := r.purged[mockRepo.ID], int64(2); got != want {
+		t.Errorf("Want purged logs up to build %d, got %d", want, got)
+	}
+}
+
+// this test confirms that builds are loaded one page at a
+// time, and that the build position is counted across pages.
+func TestRun_Paged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Slug: "octocat/hello-world"}
+	var mockPage []*core.Build
+	for i := 0; i < page; i++ {
+		number := int64(200 - i)
+		mockPage = append(mockPage, &core.Build{ID: number, Number: number, Status: core.StatusPassing, Created: now.Unix()})
+	}
+	mockBuild := &core.Build{ID: 100, Number: 100, Status: core.StatusPassing, Created: now.Unix()}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListAll(gomock.Any(), page, 0).Return([]*core.Repository{mockRepo}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListBefore(gomock.Any(), mockRepo.ID, int64(math.MaxInt64), page).Return(mockPage, nil)
+	builds.EXPECT().ListBefore(gomock.Any(), mockRepo.ID, int64(101), page).Return([]*core.Build{mockBuild}, nil)
+	builds.EXPECT().Delete(gomock.Any(), mockBuild).Return(nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(nil, nil)
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().ListBuild(gomock.Any(), mockBuild.ID).Return(nil, nil)
+
+	policies := mock.NewMockRetentionStore(controller)
+	policies.EXPECT().List(gomock.Any()).Return(nil, nil)
+
+	r := New(repos, builds, stages, nil, artifacts, nil, policies, core.Retention{Builds: page})
+	err := r.run(nocontext, now)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test confirms that repositories are not listed when
+// no policy limits builds or logs.
+func TestRun_NoPolicies(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	policies := mock.NewMockRetentionStore(controller)
+	policies.EXPECT().List(gomock.Any()).Return([]*core.Retention{
+		{Kind: core.RetentionRepository, Name: "octocat/hello-world"},
+	}, nil)
+
+	r := New(nil, nil, nil, nil, nil, nil, policies, core.Retention{})
+	err := r.run(nocontext, time.Now())
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test confirms the most specific policy is applied.
+func TestPolicyMatch(t *testing.T) {
+	global := &core.Retention{Kind: core.RetentionGlobal, Builds: 100}
+	namespace := &core.Retention{Kind: core.RetentionNamespace, Name: "octocat", Builds: 10}
+	repo := &core.Retention{Kind: core.RetentionRepository, Name: "octocat/hello-world", Builds: 1}
+	set := &policySet{
+		global:     global,
+		namespaces: map[string]*core.Retention{"octocat": namespace},
+		repos:      map[string]*core.Retention{"octocat/hello-world": repo},
+	}
+
+	tests := []struct {
+		repo *core.Repository
+		want *core.Retention
+	}{
+		{repo: &core.Repository{Namespace: "octocat", Slug: "octocat/hello-world"}, want: repo},
+		{repo: &core.Repository{Namespace: "octocat", Slug: "octocat/spoon-knife"}, want: namespace},
+		{repo: &core.Repository{Namespace: "spaceghost", Slug: "spaceghost/hello-world"}, want: global},
+	}
+	for _, test := range tests {
+		if got := set.match(test.repo); got != test.want {
+			t.Errorf("Want policy %v for %s, got %v", test.want, test.repo.Slug, got)
+		}
+	}
+}
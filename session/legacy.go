@@ -32,12 +32,13 @@ type legacy struct {
 
 // Legacy returns a session manager that is capable of mapping
 // legacy tokens to 1.0 users using a mapping file.
-func Legacy(users core.UserStore, config Config) (core.Session, error) {
+func Legacy(users core.UserStore, tokens core.AccessTokenStore, config Config) (core.Session, error) {
 	base := &session{
 		secret:  []byte(config.Secret),
 		secure:  config.Secure,
 		timeout: config.Timeout,
 		users:   users,
+		tokens:  tokens,
 	}
 	out, err := ioutil.ReadFile(config.MappingFile)
 	if err != nil {
@@ -68,7 +69,7 @@ func (s *legacy) fromToken(r *http.Request) (*core.User, error) {
 	// determine if the token is a legacy token based on length.
 	// legacy tokens are > 64 characters.
 	if len(extracted) < 64 {
-		return s.users.FindToken(r.Context(), extracted)
+		return s.session.fromToken(r)
 	}
 
 	token, err := jwt.Parse(extracted, func(token *jwt.Token) (interface{}, error) {
@@ -105,4 +106,3 @@ func (s *legacy) fromToken(r *http.Request) (*core.User, error) {
 		token.Claims.(jwt.MapClaims)["text"].(string),
 	)
 }
-
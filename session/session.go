@@ -15,6 +15,7 @@
 package session
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -24,18 +25,28 @@ import (
 	"github.com/dchest/authcookie"
 )
 
+// errTokenExpired is returned when the personal access
+// token is expired.
+var errTokenExpired = errors.New("Token expired")
+
+// touchInterval defines the minimum interval at which the
+// personal access token last used timestamp is updated.
+const touchInterval = 60
+
 // New returns a new cookie-based session management.
-func New(users core.UserStore, config Config) core.Session {
+func New(users core.UserStore, tokens core.AccessTokenStore, config Config) core.Session {
 	return &session{
 		secret:  []byte(config.Secret),
 		secure:  config.Secure,
 		timeout: config.Timeout,
 		users:   users,
+		tokens:  tokens,
 	}
 }
 
 type session struct {
 	users   core.UserStore
+	tokens  core.AccessTokenStore
 	secret  []byte
 	secure  bool
 	timeout time.Duration
@@ -91,9 +102,34 @@ func (s *session) fromSession(r *http.Request) (*core.User, error) {
 }
 
 func (s *session) fromToken(r *http.Request) (*core.User, error) {
-	return s.users.FindToken(r.Context(),
-		extractToken(r),
-	)
+	extracted := extractToken(r)
+
+	// personal access tokens are stored as a hash and take
+	// precedence over the legacy per-user token.
+	token, err := s.tokens.FindHash(r.Context(), core.HashToken(extracted))
+	if err != nil {
+		return s.users.FindToken(r.Context(), extracted)
+	}
+	return s.fromAccessToken(r, token)
+}
+
+func (s *session) fromAccessToken(r *http.Request, token *core.AccessToken) (*core.User, error) {
+	now := time.Now()
+	if token.Expired(now) {
+		return nil, errTokenExpired
+	}
+	user, err := s.users.Find(r.Context(), token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// the last used timestamp is updated at most once per
+	// interval to avoid a database write for every request.
+	if now.Unix()-token.LastUsed >= touchInterval {
+		token.LastUsed = now.Unix()
+		s.tokens.Touch(r.Context(), token)
+	}
+	user.Access = token
+	return user, nil
 }
 
 func isAuthorizationToken(r *http.Request) bool {
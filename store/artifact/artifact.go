@@ -0,0 +1,281 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new ArtifactStore.
+func New(db *db.DB) core.ArtifactStore {
+	return &artifactStore{db}
+}
+
+type artifactStore struct {
+	db *db.DB
+}
+
+func (s *artifactStore) List(ctx context.Context, id int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_stage_id": id}
+		stmt, args, err := binder.BindNamed(queryStage, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) ListBuild(ctx context.Context, id int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) ListRepo(ctx context.Context, id int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) ListPurge(ctx context.Context, id, before int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"artifact_repo_id": id,
+			"build_number":     before,
+		}
+		stmt, args, err := binder.BindNamed(queryPurge, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) Find(ctx context.Context, id int64) (*core.Artifact, error) {
+	out := &core.Artifact{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *artifactStore) FindName(ctx context.Context, id int64, name string) (*core.Artifact, error) {
+	out := &core.Artifact{StageID: id, Name: name}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryName, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *artifactStore) Create(ctx context.Context, artifact *core.Artifact) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, artifact)
+	}
+	return s.create(ctx, artifact)
+}
+
+func (s *artifactStore) create(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		artifact.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *artifactStore) createPostgres(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&artifact.ID)
+	})
+}
+
+func (s *artifactStore) Update(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *artifactStore) Delete(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ artifact_id
+,artifact_repo_id
+,artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+,artifact_updated
+`
+
+const queryKey = queryBase + `
+FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const queryName = queryBase + `
+FROM artifacts
+WHERE artifact_stage_id = :artifact_stage_id
+  AND artifact_name = :artifact_name
+`
+
+const queryStage = queryBase + `
+FROM artifacts
+WHERE artifact_stage_id = :artifact_stage_id
+ORDER BY artifact_name ASC
+`
+
+const queryBuild = queryBase + `
+FROM artifacts
+WHERE artifact_build_id = :artifact_build_id
+ORDER BY artifact_stage_id ASC, artifact_name ASC
+`
+
+const queryRepo = queryBase + `
+FROM artifacts
+WHERE artifact_repo_id = :artifact_repo_id
+ORDER BY artifact_id ASC
+`
+
+const queryPurge = queryBase + `
+FROM artifacts
+INNER JOIN builds ON builds.build_id = artifacts.artifact_build_id
+WHERE artifact_repo_id = :artifact_repo_id
+  AND build_number < :build_number
+ORDER BY artifact_id ASC
+`
+
+const stmtUpdate = `
+UPDATE artifacts SET
+ artifact_step_id = :artifact_step_id
+,artifact_size = :artifact_size
+,artifact_updated = :artifact_updated
+WHERE artifact_id = :artifact_id
+`
+
+const stmtDelete = `
+DELETE FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const stmtInsert = `
+INSERT INTO artifacts (
+ artifact_repo_id
+,artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+,artifact_updated
+) VALUES (
+ :artifact_repo_id
+,:artifact_build_id
+,:artifact_stage_id
+,:artifact_step_id
+,:artifact_name
+,:artifact_size
+,:artifact_created
+,:artifact_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING artifact_id
+`
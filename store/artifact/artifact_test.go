@@ -0,0 +1,209 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package artifact
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestArtifact(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1, Name: "default"}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	store := New(conn).(*artifactStore)
+	item := &core.Artifact{
+		RepoID:  arepo.ID,
+		BuildID: abuild.ID,
+		StageID: stage.ID,
+		StepID:  1,
+		Name:    "dist/drone",
+		Size:    42,
+		Created: 1522878684,
+		Updated: 1522878684,
+	}
+	t.Run("Create", testArtifactCreate(store, item))
+	t.Run("Find", testArtifactFind(store, item))
+	t.Run("FindName", testArtifactFindName(store, item))
+	t.Run("List", testArtifactList(store, item))
+	t.Run("Update", testArtifactUpdate(store, item))
+	t.Run("Delete", testArtifactDelete(store, item))
+}
+
+func testArtifactCreate(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want artifact ID assigned, got %d", item.ID)
+		}
+	}
+}
+
+func testArtifactFind(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.Find(noContext, item.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		t.Run("Fields", testArtifact(result))
+	}
+}
+
+func testArtifactFindName(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.FindName(noContext, item.StageID, item.Name)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		t.Run("Fields", testArtifact(result))
+	}
+}
+
+func testArtifactList(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, item.StageID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		t.Run("Fields", testArtifact(list[0]))
+
+		list, err = store.ListBuild(noContext, item.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		t.Run("Fields", testArtifact(list[0]))
+
+		list, err = store.ListRepo(noContext, item.RepoID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		t.Run("Fields", testArtifact(list[0]))
+
+		// the artifact belongs to build number 1, and is
+		// only included when purging builds before 2.
+		list, err = store.ListPurge(noContext, item.RepoID, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		list, err = store.ListPurge(noContext, item.RepoID, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		t.Run("Fields", testArtifact(list[0]))
+	}
+}
+
+func testArtifactUpdate(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		before := &core.Artifact{}
+		*before = *item
+		before.Size = 84
+		err := store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, item.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Size, int64(84); got != want {
+			t.Errorf("Want size %d, got %d", want, got)
+		}
+	}
+}
+
+func testArtifactDelete(store *artifactStore, item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, item)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, item.ID)
+		if got, want := err, sql.ErrNoRows; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testArtifact(item *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Name, "dist/drone"; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+		if got, want := item.Size, int64(42); got != want {
+			t.Errorf("Want size %d, got %d", want, got)
+		}
+		if got, want := item.StepID, int64(1); got != want {
+			t.Errorf("Want step ID %d, got %d", want, got)
+		}
+		if got, want := item.Created, int64(1522878684); got != want {
+			t.Errorf("Want created %d, got %d", want, got)
+		}
+	}
+}
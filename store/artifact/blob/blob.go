@@ -0,0 +1,185 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// chunkSize defines the size of each chunk of the artifact
+// contents stored in the database. The contents are streamed
+// to and from the database one chunk at a time, so that large
+// artifacts are never buffered in memory.
+const chunkSize = 1 << 20
+
+// New returns a new database ArtifactBlobStore.
+func New(db *db.DB) core.ArtifactBlobStore {
+	return &blobStore{db}
+}
+
+type blobStore struct {
+	db *db.DB
+}
+
+func (s *blobStore) Find(ctx context.Context, artifact int64) (io.ReadCloser, error) {
+	r := &chunkReader{db: s.db, artifact: artifact}
+	if err := r.next(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *blobStore) Create(ctx context.Context, artifact int64, r io.Reader) error {
+	return s.write(artifact, r)
+}
+
+func (s *blobStore) Update(ctx context.Context, artifact int64, r io.Reader) error {
+	if err := s.Delete(ctx, artifact); err != nil {
+		return err
+	}
+	return s.write(artifact, r)
+}
+
+func (s *blobStore) Delete(ctx context.Context, artifact int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := &chunk{Artifact: artifact}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+// helper function copies the contents from Reader r to the
+// database in fixed size chunks. The first chunk is always
+// written, even if empty, so that empty artifacts can be found.
+func (s *blobStore) write(artifact int64, r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	for number := 0; ; number++ {
+		n, err := io.ReadFull(r, buf)
+		eof := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !eof {
+			return err
+		}
+		if n > 0 || number == 0 {
+			err = s.insert(&chunk{
+				Artifact: artifact,
+				Number:   number,
+				Data:     buf[:n],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if eof {
+			return nil
+		}
+	}
+}
+
+func (s *blobStore) insert(chunk *chunk) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		stmt, args, err := binder.BindNamed(stmtInsert, chunk)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+// chunkReader reads the artifact contents from the database
+// one chunk at a time.
+type chunkReader struct {
+	db       *db.DB
+	artifact int64
+	number   int
+	buf      []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		err := r.next()
+		if err == sql.ErrNoRows {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *chunkReader) Close() error {
+	return nil
+}
+
+// helper function loads the next chunk from the database.
+func (r *chunkReader) next() error {
+	out := &chunk{Artifact: r.artifact, Number: r.number}
+	err := r.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		query, args, err := binder.BindNamed(queryChunk, out)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return row.Scan(&out.Data)
+	})
+	if err != nil {
+		return err
+	}
+	r.number++
+	r.buf = out.Data
+	return nil
+}
+
+type chunk struct {
+	Artifact int64  `db:"chunk_artifact_id"`
+	Number   int    `db:"chunk_number"`
+	Data     []byte `db:"chunk_data"`
+}
+
+const queryChunk = `
+SELECT chunk_data
+FROM artifact_chunks
+WHERE chunk_artifact_id = :chunk_artifact_id
+  AND chunk_number      = :chunk_number
+`
+
+const stmtInsert = `
+INSERT INTO artifact_chunks (
+ chunk_artifact_id
+,chunk_number
+,chunk_data
+) VALUES (
+ :chunk_artifact_id
+,:chunk_number
+,:chunk_data
+)
+`
+
+const stmtDelete = `
+DELETE FROM artifact_chunks
+WHERE chunk_artifact_id = :chunk_artifact_id
+`
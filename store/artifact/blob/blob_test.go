@@ -0,0 +1,134 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/artifact"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestBlob(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	// seed with a dummy artifact
+	aartifact := &core.Artifact{BuildID: abuild.ID, StageID: stage.ID, Name: "coverage.out"}
+	artifacts := artifact.New(conn)
+	artifacts.Create(noContext, aartifact)
+
+	testBlobStore(t, New(conn), aartifact.ID)
+	testBlobChunks(t, New(conn), aartifact.ID)
+}
+
+func TestFileSystem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	testBlobStore(t, NewFileSystem(dir), 1)
+}
+
+func testBlobStore(t *testing.T, store core.ArtifactBlobStore, id int64) {
+	err := store.Create(noContext, id, bytes.NewBufferString("hello world"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := readBlob(t, store, id), "hello world"; got != want {
+		t.Errorf("Want contents %q, got %q", want, got)
+	}
+
+	err = store.Update(noContext, id, bytes.NewBufferString("hola mundo"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := readBlob(t, store, id), "hola mundo"; got != want {
+		t.Errorf("Want contents %q, got %q", want, got)
+	}
+
+	err = store.Delete(noContext, id)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := store.Find(noContext, id); err == nil {
+		t.Errorf("Want error finding deleted contents")
+	}
+}
+
+// this test verifies that contents larger than a single chunk
+// are split across chunks and read back in order, and that
+// empty contents can be found.
+func testBlobChunks(t *testing.T, store core.ArtifactBlobStore, id int64) {
+	data := bytes.Repeat([]byte("0123456789"), chunkSize/5+1)
+	err := store.Create(noContext, id, bytes.NewBuffer(data))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := readBlob(t, store, id), string(data); got != want {
+		t.Errorf("Want contents of size %d, got size %d", len(want), len(got))
+	}
+
+	err = store.Update(noContext, id, new(bytes.Buffer))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := readBlob(t, store, id), ""; got != want {
+		t.Errorf("Want empty contents, got size %d", len(got))
+	}
+}
+
+func readBlob(t *testing.T, store core.ArtifactBlobStore, id int64) string {
+	r, err := store.Find(noContext, id)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Error(err)
+	}
+	return string(data)
+}
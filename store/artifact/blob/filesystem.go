@@ -0,0 +1,77 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/drone/drone/core"
+)
+
+// NewFileSystem returns a new ArtifactBlobStore that persists
+// artifact contents to the local filesystem.
+func NewFileSystem(root string) core.ArtifactBlobStore {
+	return &fileSystem{root: root}
+}
+
+type fileSystem struct {
+	root string
+}
+
+func (s *fileSystem) Find(ctx context.Context, artifact int64) (io.ReadCloser, error) {
+	return os.Open(s.path(artifact))
+}
+
+func (s *fileSystem) Create(ctx context.Context, artifact int64, r io.Reader) error {
+	if err := os.MkdirAll(s.root, 0700); err != nil {
+		return err
+	}
+	// the contents are written to a temporary file and renamed,
+	// to prevent partially written artifacts from being served.
+	f, err := ioutil.TempFile(s.root, fmt.Sprintf("%d.*.tmp", artifact))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path(artifact))
+}
+
+func (s *fileSystem) Update(ctx context.Context, artifact int64, r io.Reader) error {
+	return s.Create(ctx, artifact, r)
+}
+
+func (s *fileSystem) Delete(ctx context.Context, artifact int64) error {
+	err := os.Remove(s.path(artifact))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *fileSystem) path(artifact int64) string {
+	return filepath.Join(s.root, fmt.Sprint(artifact))
+}
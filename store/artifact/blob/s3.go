@@ -0,0 +1,90 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/drone/drone/core"
+)
+
+// NewS3Env returns a new S3 artifact blob store.
+func NewS3Env(bucket, prefix, endpoint string, pathStyle bool) core.ArtifactBlobStore {
+	disableSSL := false
+
+	if endpoint != "" {
+		disableSSL = !strings.HasPrefix(endpoint, "https://")
+	}
+
+	return &s3store{
+		bucket: bucket,
+		prefix: prefix,
+		session: session.Must(
+			session.NewSession(&aws.Config{
+				Endpoint:         aws.String(endpoint),
+				DisableSSL:       aws.Bool(disableSSL),
+				S3ForcePathStyle: aws.Bool(pathStyle),
+			}),
+		),
+	}
+}
+
+type s3store struct {
+	bucket  string
+	prefix  string
+	session *session.Session
+}
+
+func (s *s3store) Find(ctx context.Context, artifact int64) (io.ReadCloser, error) {
+	svc := s3.New(s.session)
+	out, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3store) Create(ctx context.Context, artifact int64, r io.Reader) error {
+	uploader := s3manager.NewUploader(s.session)
+	input := &s3manager.UploadInput{
+		ACL:    aws.String("private"),
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+		Body:   r,
+	}
+	_, err := uploader.UploadWithContext(ctx, input)
+	return err
+}
+
+func (s *s3store) Update(ctx context.Context, artifact int64, r io.Reader) error {
+	return s.Create(ctx, artifact, r)
+}
+
+func (s *s3store) Delete(ctx context.Context, artifact int64) error {
+	svc := s3.New(s.session)
+	_, err := svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+	})
+	return err
+}
+
+func (s *s3store) key(artifact int64) string {
+	return path.Join("/", s.prefix, "artifacts", fmt.Sprint(artifact))
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package audit
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// defaultLimit is the default number of audit events
+// returned when the filter does not specify a limit.
+const defaultLimit = 100
+
+// New returns a new AuditStore.
+func New(db *db.DB) core.AuditStore {
+	return &auditStore{db}
+}
+
+type auditStore struct {
+	db *db.DB
+}
+
+func (s *auditStore) List(ctx context.Context, filter *core.AuditFilter) ([]*core.AuditEvent, error) {
+	var out []*core.AuditEvent
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toFilterParams(filter)
+		stmt, args, err := binder.BindNamed(queryFilter, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *auditStore) Create(ctx context.Context, event *core.AuditEvent) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, event)
+	}
+	return s.create(ctx, event)
+}
+
+func (s *auditStore) create(ctx context.Context, event *core.AuditEvent) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(event)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		event.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *auditStore) createPostgres(ctx context.Context, event *core.AuditEvent) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(event)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&event.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ audit_id
+,audit_actor
+,audit_action
+,audit_target
+,audit_diff
+,audit_ip
+,audit_created
+`
+
+const queryFilter = queryBase + `
+FROM audit_log
+WHERE (:audit_actor = '' OR audit_actor = :audit_actor)
+  AND (:audit_action = '' OR audit_action = :audit_action)
+  AND (:audit_target = '' OR audit_target = :audit_target)
+  AND (:audit_since = 0 OR audit_created >= :audit_since)
+  AND (:audit_until = 0 OR audit_created <= :audit_until)
+ORDER BY audit_id DESC
+LIMIT :limit OFFSET :offset
+`
+
+const stmtInsert = `
+INSERT INTO audit_log (
+ audit_actor
+,audit_action
+,audit_target
+,audit_diff
+,audit_ip
+,audit_created
+) VALUES (
+ :audit_actor
+,:audit_action
+,:audit_target
+,:audit_diff
+,:audit_ip
+,:audit_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING audit_id
+`
@@ -0,0 +1,95 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestAudit(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*auditStore)
+	t.Run("Create", testAuditCreate(store))
+}
+
+func testAuditCreate(store *auditStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		events := []*core.AuditEvent{
+			{Actor: "octocat", Action: core.AuditRepoEnable, Target: "octocat/hello-world", IP: "127.0.0.1", Created: 100},
+			{Actor: "octocat", Action: core.AuditSecretUpdate, Target: "octocat/hello-world/password", Diff: `{"data":{"before":"[redacted]","after":"[redacted]"}}`, Created: 200},
+			{Actor: "spaceghost", Action: core.AuditUserCreate, Target: "janedoe", Created: 300},
+		}
+		for _, event := range events {
+			err := store.Create(noContext, event)
+			if err != nil {
+				t.Error(err)
+			}
+			if event.ID == 0 {
+				t.Errorf("Want audit event ID assigned, got %d", event.ID)
+			}
+		}
+
+		t.Run("List", testAuditList(store, events))
+	}
+}
+
+func testAuditList(store *auditStore, events []*core.AuditEvent) func(t *testing.T) {
+	return func(t *testing.T) {
+		tests := []struct {
+			filter *core.AuditFilter
+			want   []int64
+		}{
+			{filter: &core.AuditFilter{}, want: []int64{events[2].ID, events[1].ID, events[0].ID}},
+			{filter: &core.AuditFilter{Actor: "octocat"}, want: []int64{events[1].ID, events[0].ID}},
+			{filter: &core.AuditFilter{Action: core.AuditUserCreate}, want: []int64{events[2].ID}},
+			{filter: &core.AuditFilter{Target: "octocat/hello-world"}, want: []int64{events[0].ID}},
+			{filter: &core.AuditFilter{Since: 150, Until: 250}, want: []int64{events[1].ID}},
+			{filter: &core.AuditFilter{Limit: 1, Offset: 1}, want: []int64{events[1].ID}},
+		}
+		for i, test := range tests {
+			list, err := store.List(noContext, test.filter)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			var got []int64
+			for _, event := range list {
+				got = append(got, event.ID)
+			}
+			if len(got) != len(test.want) {
+				t.Errorf("Want audit events %v, got %v at index %d", test.want, got, i)
+				continue
+			}
+			for j := range got {
+				if got[j] != test.want[j] {
+					t.Errorf("Want audit events %v, got %v at index %d", test.want, got, i)
+					break
+				}
+			}
+		}
+
+		list, _ := store.List(noContext, &core.AuditFilter{Action: core.AuditSecretUpdate})
+		if len(list) == 1 && list[0].Diff != events[1].Diff {
+			t.Errorf("Want audit diff %q, got %q", events[1].Diff, list[0].Diff)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package audit
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the AuditEvent structure to a set
+// of named query parameters.
+func toParams(event *core.AuditEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"audit_id":      event.ID,
+		"audit_actor":   event.Actor,
+		"audit_action":  event.Action,
+		"audit_target":  event.Target,
+		"audit_diff":    event.Diff,
+		"audit_ip":      event.IP,
+		"audit_created": event.Created,
+	}
+}
+
+// helper function converts the AuditFilter structure to a set
+// of named query parameters.
+func toFilterParams(filter *core.AuditFilter) map[string]interface{} {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	return map[string]interface{}{
+		"audit_actor":  filter.Actor,
+		"audit_action": filter.Action,
+		"audit_target": filter.Target,
+		"audit_since":  filter.Since,
+		"audit_until":  filter.Until,
+		"limit":        limit,
+		"offset":       filter.Offset,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.AuditEvent) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Actor,
+		&dst.Action,
+		&dst.Target,
+		&dst.Diff,
+		&dst.IP,
+		&dst.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.AuditEvent, error) {
+	defer rows.Close()
+
+	events := []*core.AuditEvent{}
+	for rows.Next() {
+		event := new(core.AuditEvent)
+		err := scanRow(rows, event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -180,6 +180,7 @@ const stmtInsertBase = `
 ,repo_config
 ,repo_timeout
 ,repo_throttle
+,repo_priority
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
@@ -210,6 +211,7 @@ const stmtInsertBase = `
 ,:repo_config
 ,:repo_timeout
 ,:repo_throttle
+,:repo_priority
 ,:repo_trusted
 ,:repo_protected
 ,:repo_no_forks
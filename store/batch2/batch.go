@@ -238,6 +238,7 @@ const stmtInsertBase = `
 ,repo_config
 ,repo_timeout
 ,repo_throttle
+,repo_priority
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
@@ -268,6 +269,7 @@ const stmtInsertBase = `
 ,:repo_config
 ,:repo_timeout
 ,:repo_throttle
+,:repo_priority
 ,:repo_trusted
 ,:repo_protected
 ,:repo_no_forks
@@ -105,6 +105,30 @@ func (s *buildStore) List(ctx context.Context, repo int64, limit, offset int) ([
 	return out, err
 }
 
+// ListBefore returns a list of builds from the datastore by
+// repository id where the build number is less than n.
+func (s *buildStore) ListBefore(ctx context.Context, repo, n int64, limit int) ([]*core.Build, error) {
+	var out []*core.Build
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"build_repo_id": repo,
+			"build_number":  n,
+			"limit":         limit,
+		}
+		stmt, args, err := binder.BindNamed(queryBefore, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
 // ListRef returns a list of builds from the datastore by ref.
 func (s *buildStore) ListRef(ctx context.Context, repo int64, ref string, limit, offset int) ([]*core.Build, error) {
 	var out []*core.Build
@@ -129,6 +153,31 @@ func (s *buildStore) ListRef(ctx context.Context, repo int64, ref string, limit,
 	return out, err
 }
 
+// ListDeploys returns a list of builds from the datastore by
+// target deployment environment.
+func (s *buildStore) ListDeploys(ctx context.Context, repo int64, target string, limit, offset int) ([]*core.Build, error) {
+	var out []*core.Build
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"build_repo_id": repo,
+			"build_deploy":  target,
+			"limit":         limit,
+			"offset":        offset,
+		}
+		stmt, args, err := binder.BindNamed(queryDeploy, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
 // LatestBranches returns a list of the latest build by branch.
 func (s *buildStore) LatestBranches(ctx context.Context, repo int64) ([]*core.Build, error) {
 	return s.latest(ctx, repo, "branch")
@@ -144,6 +193,29 @@ func (s *buildStore) LatestDeploys(ctx context.Context, repo int64) ([]*core.Bui
 	return s.latest(ctx, repo, "deployment")
 }
 
+// LatestDeploysNamespace returns a list of the latest builds by
+// target deploy for every repository in the namespace.
+func (s *buildStore) LatestDeploysNamespace(ctx context.Context, namespace string) ([]*core.Build, error) {
+	var out []*core.Build
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"repo_namespace": namespace,
+			"latest_type":    "deployment",
+		}
+		stmt, args, err := binder.BindNamed(queryLatestNamespace, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
 func (s *buildStore) latest(ctx context.Context, repo int64, event string) ([]*core.Build, error) {
 	var out []*core.Build
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
@@ -471,6 +543,7 @@ SELECT
 ,build_deploy
 ,build_deploy_id
 ,build_debug
+,build_timeout
 ,build_started
 ,build_finished
 ,build_created
@@ -512,6 +585,22 @@ ORDER BY build_id DESC
 LIMIT :limit OFFSET :offset
 `
 
+const queryBefore = queryBase + `
+FROM builds
+WHERE build_repo_id = :build_repo_id
+  AND build_number < :build_number
+ORDER BY build_number DESC
+LIMIT :limit
+`
+
+const queryDeploy = queryBase + `
+FROM builds
+WHERE build_repo_id = :build_repo_id
+  AND build_deploy  = :build_deploy
+ORDER BY build_id DESC
+LIMIT :limit OFFSET :offset
+`
+
 const queryPending = queryBase + `
 FROM builds
 WHERE EXISTS (
@@ -609,6 +698,7 @@ INSERT INTO builds (
 ,build_deploy
 ,build_deploy_id
 ,build_debug
+,build_timeout
 ,build_started
 ,build_finished
 ,build_created
@@ -643,6 +733,7 @@ INSERT INTO builds (
 ,:build_deploy
 ,:build_deploy_id
 ,:build_debug
+,:build_timeout
 ,:build_started
 ,:build_finished
 ,:build_created
@@ -678,11 +769,17 @@ INSERT INTO stages (
 ,stage_stopped
 ,stage_created
 ,stage_updated
+,stage_expires
+,stage_priority
+,stage_reason
 ,stage_version
 ,stage_on_success
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_attempt
+,stage_retry
+,stage_timeout
 ) VALUES (
  :stage_repo_id
 ,:stage_build_id
@@ -705,11 +802,17 @@ INSERT INTO stages (
 ,:stage_stopped
 ,:stage_created
 ,:stage_updated
+,:stage_expires
+,:stage_priority
+,:stage_reason
 ,:stage_version
 ,:stage_on_success
 ,:stage_on_failure
 ,:stage_depends_on
 ,:stage_labels
+,:stage_attempt
+,:stage_retry
+,:stage_timeout
 )
 `
 
@@ -810,3 +913,12 @@ WHERE build_id IN (
 	  AND latest_type     = :latest_type
 )
 `
+
+const queryLatestNamespace = queryBase + `
+FROM builds
+INNER JOIN latest ON latest.latest_build_id = builds.build_id
+INNER JOIN repos  ON repos.repo_id = builds.build_repo_id
+WHERE repos.repo_namespace = :repo_namespace
+  AND latest.latest_type   = :latest_type
+ORDER BY repos.repo_slug ASC, builds.build_id DESC
+`
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
 	"github.com/drone/drone/store/shared/db"
 
 	"github.com/drone/drone/store/shared/db/dbtest"
@@ -31,20 +32,24 @@ func TestBuild(t *testing.T) {
 	store := New(conn).(*buildStore)
 	t.Run("Create", testBuildCreate(store))
 	t.Run("Purge", testBuildPurge(store))
+	t.Run("ListBefore", testBuildListBefore(store))
 	t.Run("Count", testBuildCount(store))
 	t.Run("Pending", testBuildPending(store))
 	t.Run("Running", testBuildRunning(store))
 	t.Run("Latest", testBuildLatest(store))
+	t.Run("LatestNamespace", testBuildLatestNamespace(store))
 }
 
 func testBuildCreate(store *buildStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		build := &core.Build{
-			RepoID: 1,
-			Number: 99,
-			Event:  core.EventPush,
-			Ref:    "refs/heads/master",
-			Target: "master",
+			RepoID:  1,
+			Number:  99,
+			Event:   core.EventPush,
+			Ref:     "refs/heads/master",
+			Target:  "master",
+			Deploy:  "production",
+			Timeout: 7200,
 		}
 		stage := &core.Stage{
 			RepoID: 42,
@@ -65,6 +70,7 @@ func testBuildCreate(store *buildStore) func(t *testing.T) {
 		t.Run("FindRef", testBuildFindRef(store, build))
 		t.Run("List", testBuildList(store, build))
 		t.Run("ListRef", testBuildListRef(store, build))
+		t.Run("ListDeploys", testBuildListDeploys(store, build))
 		t.Run("Update", testBuildUpdate(store, build))
 		t.Run("Locking", testBuildLocking(store, build))
 		t.Run("Delete", testBuildDelete(store, build))
@@ -134,6 +140,30 @@ func testBuildListRef(store *buildStore, build *core.Build) func(t *testing.T) {
 	}
 }
 
+func testBuildListDeploys(store *buildStore, build *core.Build) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListDeploys(noContext, build.RepoID, build.Deploy, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want list count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testBuild(list[0]))
+		}
+
+		list, err = store.ListDeploys(noContext, build.RepoID, "staging", 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want list count %d, got %d", want, got)
+		}
+	}
+}
+
 func testBuildUpdate(store *buildStore, build *core.Build) func(t *testing.T) {
 	return func(t *testing.T) {
 		before := &core.Build{
@@ -236,6 +266,44 @@ func testBuildPurge(store *buildStore) func(t *testing.T) {
 	}
 }
 
+func testBuildListBefore(store *buildStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.db.Update(func(execer db.Execer, binder db.Binder) error {
+			_, err := execer.Exec("DELETE FROM builds")
+			return err
+		})
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 98}, nil)
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 99}, nil)
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 100}, nil)
+		store.Create(noContext, &core.Build{RepoID: 2, Number: 99}, nil)
+
+		list, err := store.ListBefore(noContext, 1, 100, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want build count %d, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Number, int64(99); got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+		if got, want := list[1].Number, int64(98); got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+
+		list, err = store.ListBefore(noContext, 1, 100, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want build count %d, got %d", want, got)
+		}
+	}
+}
+
 func testBuildCount(store *buildStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		store.db.Update(func(execer db.Execer, binder db.Binder) error {
@@ -428,5 +496,55 @@ func testBuild(item *core.Build) func(t *testing.T) {
 		if got, want := item.Ref, "refs/heads/master"; got != want {
 			t.Errorf("Want build ref %q, got %q", want, got)
 		}
+		if got, want := item.Timeout, int64(7200); got != want {
+			t.Errorf("Want build timeout %d, got %d", want, got)
+		}
+	}
+}
+
+func testBuildLatestNamespace(store *buildStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.db.Update(func(execer db.Execer, binder db.Binder) error {
+			execer.Exec("DELETE FROM stages")
+			execer.Exec("DELETE FROM latest")
+			execer.Exec("DELETE FROM builds")
+			return nil
+		})
+
+		octocat := &core.Repository{UID: "42", Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+		spaceghost := &core.Repository{UID: "43", Namespace: "spaceghost", Name: "hello-world", Slug: "spaceghost/hello-world"}
+		repos := repos.New(store.db)
+		for _, repo := range []*core.Repository{octocat, spaceghost} {
+			if err := repos.Create(noContext, repo); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		builds := []*core.Build{
+			{RepoID: octocat.ID, Number: 1, Event: core.EventPromote, Deploy: "production"},
+			{RepoID: octocat.ID, Number: 2, Event: core.EventPromote, Deploy: "production"},
+			{RepoID: octocat.ID, Number: 3, Event: core.EventPush, Target: "master"},
+			{RepoID: spaceghost.ID, Number: 1, Event: core.EventPromote, Deploy: "production"},
+		}
+		for _, build := range builds {
+			if err := store.Create(noContext, build, []*core.Stage{}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		list, err := store.LatestDeploysNamespace(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want latest deploy count %d, got %d", want, got)
+			return
+		}
+		if got, want := list[0].ID, builds[1].ID; got != want {
+			t.Errorf("Want latest deploy id %d, got %d", want, got)
+		}
 	}
 }
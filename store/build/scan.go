@@ -57,6 +57,7 @@ func toParams(build *core.Build) map[string]interface{} {
 		"build_deploy":        build.Deploy,
 		"build_deploy_id":     build.DeployID,
 		"build_debug":         build.Debug,
+		"build_timeout":       build.Timeout,
 		"build_started":       build.Started,
 		"build_finished":      build.Finished,
 		"build_created":       build.Created,
@@ -91,14 +92,30 @@ func toStageParams(stage *core.Stage) map[string]interface{} {
 		"stage_stopped":    stage.Stopped,
 		"stage_created":    stage.Created,
 		"stage_updated":    stage.Updated,
+		"stage_expires":    stage.Expires,
+		"stage_priority":   stage.Priority,
+		"stage_reason":     stage.Reason,
 		"stage_version":    stage.Version,
 		"stage_on_success": stage.OnSuccess,
 		"stage_on_failure": stage.OnFailure,
 		"stage_depends_on": encodeSlice(stage.DependsOn),
 		"stage_labels":     encodeParams(stage.Labels),
+		"stage_attempt":    stage.Attempt,
+		"stage_retry":      encodeRetry(stage.Retry),
+		"stage_timeout":    encodeTimeout(stage.Timeout),
 	}
 }
 
+func encodeRetry(v *core.RetryPolicy) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+func encodeTimeout(v *core.TimeoutPolicy) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
 func encodeParams(v map[string]string) types.JSONText {
 	raw, _ := json.Marshal(v)
 	return types.JSONText(raw)
@@ -143,6 +160,7 @@ func scanRow(scanner db.Scanner, dest *core.Build) error {
 		&dest.Deploy,
 		&dest.DeployID,
 		&dest.Debug,
+		&dest.Timeout,
 		&dest.Started,
 		&dest.Finished,
 		&dest.Created,
@@ -0,0 +1,294 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package delivery
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new WebhookDelivery database store.
+func New(db *db.DB) core.WebhookDeliveryStore {
+	return &deliveryStore{
+		db: db,
+	}
+}
+
+type deliveryStore struct {
+	db *db.DB
+}
+
+func (s *deliveryStore) List(ctx context.Context, limit int) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"limit": limit,
+		}
+		stmt, args, err := binder.BindNamed(queryRecent, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) ListWebhook(ctx context.Context, webhook int64, limit int) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_webhook_id": webhook,
+			"limit":               limit,
+		}
+		stmt, args, err := binder.BindNamed(queryWebhook, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) ListPending(ctx context.Context, now int64) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status":     core.DeliveryPending,
+			"delivery_next_retry": now,
+		}
+		stmt, args, err := binder.BindNamed(queryPending, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Find(ctx context.Context, id int64) (*core.WebhookDelivery, error) {
+	out := &core.WebhookDelivery{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, delivery)
+	}
+	return s.create(ctx, delivery)
+}
+
+func (s *deliveryStore) create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		delivery.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *deliveryStore) createPostgres(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsertPostgres, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&delivery.ID)
+	})
+}
+
+func (s *deliveryStore) Update(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *deliveryStore) Claim(ctx context.Context, delivery *core.WebhookDelivery, until int64) error {
+	err := s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		params["delivery_claim"] = until
+		stmt, args, err := binder.BindNamed(stmtClaim, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		effected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if effected == 0 {
+			return db.ErrOptimisticLock
+		}
+		return nil
+	})
+	if err == nil {
+		delivery.NextRetry = until
+	}
+	return err
+}
+
+func (s *deliveryStore) Purge(ctx context.Context, before int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_created": before,
+		}
+		stmt, args, err := binder.BindNamed(stmtPurge, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ delivery_id
+,delivery_webhook_id
+,delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_status
+,delivery_attempts
+,delivery_code
+,delivery_error
+,delivery_latency
+,delivery_payload
+,delivery_next_retry
+,delivery_created
+,delivery_updated
+`
+
+const queryKey = queryBase + `
+FROM deliveries
+WHERE delivery_id = :delivery_id
+LIMIT 1
+`
+
+const queryRecent = queryBase + `
+FROM deliveries
+ORDER BY delivery_id DESC
+LIMIT :limit
+`
+
+const queryWebhook = queryBase + `
+FROM deliveries
+WHERE delivery_webhook_id = :delivery_webhook_id
+ORDER BY delivery_id DESC
+LIMIT :limit
+`
+
+const queryPending = queryBase + `
+FROM deliveries
+WHERE delivery_status = :delivery_status
+  AND delivery_next_retry <= :delivery_next_retry
+ORDER BY delivery_id ASC
+`
+
+const stmtInsert = `
+INSERT INTO deliveries (
+ delivery_webhook_id
+,delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_status
+,delivery_attempts
+,delivery_code
+,delivery_error
+,delivery_latency
+,delivery_payload
+,delivery_next_retry
+,delivery_created
+,delivery_updated
+) VALUES (
+ :delivery_webhook_id
+,:delivery_endpoint
+,:delivery_event
+,:delivery_action
+,:delivery_status
+,:delivery_attempts
+,:delivery_code
+,:delivery_error
+,:delivery_latency
+,:delivery_payload
+,:delivery_next_retry
+,:delivery_created
+,:delivery_updated
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING delivery_id
+`
+
+const stmtUpdate = `
+UPDATE deliveries SET
+ delivery_status = :delivery_status
+,delivery_attempts = :delivery_attempts
+,delivery_code = :delivery_code
+,delivery_error = :delivery_error
+,delivery_latency = :delivery_latency
+,delivery_next_retry = :delivery_next_retry
+,delivery_updated = :delivery_updated
+WHERE delivery_id = :delivery_id
+`
+
+const stmtClaim = `
+UPDATE deliveries SET
+ delivery_next_retry = :delivery_claim
+WHERE delivery_id = :delivery_id
+  AND delivery_status = :delivery_status
+  AND delivery_next_retry = :delivery_next_retry
+`
+
+const stmtPurge = `
+DELETE FROM deliveries
+WHERE delivery_created < :delivery_created
+  AND delivery_status != 'pending'
+`
@@ -0,0 +1,242 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package delivery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestDelivery(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*deliveryStore)
+	t.Run("Create", testDeliveryCreate(store))
+}
+
+func testDeliveryCreate(store *deliveryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.WebhookDelivery{
+			WebhookID: 1,
+			Endpoint:  "https://company.com/hooks",
+			Event:     core.WebhookEventBuild,
+			Action:    core.WebhookActionUpdated,
+			Status:    core.DeliveryPending,
+			Attempts:  1,
+			Code:      502,
+			Error:     "bad gateway",
+			Latency:   120,
+			Payload:   `{"event":"build"}`,
+			NextRetry: 100,
+			Created:   1,
+			Updated:   2,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want delivery ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testDeliveryFind(store, item))
+		t.Run("List", testDeliveryList(store))
+		t.Run("ListWebhook", testDeliveryListWebhook(store))
+		t.Run("ListPending", testDeliveryListPending(store))
+		t.Run("Claim", testDeliveryClaim(store, item))
+		t.Run("Update", testDeliveryUpdate(store, item))
+		t.Run("Purge", testDeliveryPurge(store))
+	}
+}
+
+func testDeliveryFind(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testDelivery(item))
+		}
+	}
+}
+
+func testDeliveryList(store *deliveryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testDelivery(list[0]))
+		}
+	}
+}
+
+func testDeliveryListWebhook(store *deliveryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListWebhook(noContext, 1, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testDelivery(list[0]))
+		}
+		list, err = store.ListWebhook(noContext, 2, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testDeliveryListPending(store *deliveryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListPending(noContext, 99)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d before next retry, got %d", want, got)
+		}
+		list, err = store.ListPending(noContext, 100)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testDeliveryClaim(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		first, _ := store.Find(noContext, delivery.ID)
+		second, _ := store.Find(noContext, delivery.ID)
+
+		err := store.Claim(noContext, first, 200)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := first.NextRetry, int64(200); got != want {
+			t.Errorf("Want next retry %d, got %d", want, got)
+		}
+		err = store.Claim(noContext, second, 200)
+		if got, want := err, db.ErrOptimisticLock; got != want {
+			t.Errorf("Want optimistic lock error, got %v", got)
+		}
+		list, _ := store.ListPending(noContext, 100)
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want claimed delivery excluded, got %d pending", got)
+		}
+	}
+}
+
+func testDeliveryUpdate(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Status = core.DeliverySuccess
+		before.Attempts = 2
+		before.Code = 200
+		before.Error = ""
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Status, core.DeliverySuccess; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if got, want := after.Attempts, 2; got != want {
+			t.Errorf("Want attempts %d, got %d", want, got)
+		}
+		if got, want := after.Code, 200; got != want {
+			t.Errorf("Want response code %d, got %d", want, got)
+		}
+	}
+}
+
+func testDeliveryPurge(store *deliveryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Purge(noContext, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testDelivery(item *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.WebhookID, int64(1); got != want {
+			t.Errorf("Want webhook id %d, got %d", want, got)
+		}
+		if got, want := item.Endpoint, "https://company.com/hooks"; got != want {
+			t.Errorf("Want endpoint %q, got %q", want, got)
+		}
+		if got, want := item.Event, core.WebhookEventBuild; got != want {
+			t.Errorf("Want event %q, got %q", want, got)
+		}
+		if got, want := item.Action, core.WebhookActionUpdated; got != want {
+			t.Errorf("Want action %q, got %q", want, got)
+		}
+		if got, want := item.Status, core.DeliveryPending; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+		if got, want := item.Code, 502; got != want {
+			t.Errorf("Want response code %d, got %d", want, got)
+		}
+		if got, want := item.Payload, `{"event":"build"}`; got != want {
+			t.Errorf("Want payload %q, got %q", want, got)
+		}
+		if got, want := item.NextRetry, int64(100); got != want {
+			t.Errorf("Want next retry %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package delivery
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the WebhookDelivery structure to a
+// set of named query parameters.
+func toParams(delivery *core.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"delivery_id":         delivery.ID,
+		"delivery_webhook_id": delivery.WebhookID,
+		"delivery_endpoint":   delivery.Endpoint,
+		"delivery_event":      delivery.Event,
+		"delivery_action":     delivery.Action,
+		"delivery_status":     delivery.Status,
+		"delivery_attempts":   delivery.Attempts,
+		"delivery_code":       delivery.Code,
+		"delivery_error":      delivery.Error,
+		"delivery_latency":    delivery.Latency,
+		"delivery_payload":    delivery.Payload,
+		"delivery_next_retry": delivery.NextRetry,
+		"delivery_created":    delivery.Created,
+		"delivery_updated":    delivery.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.WebhookDelivery) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.WebhookID,
+		&dst.Endpoint,
+		&dst.Event,
+		&dst.Action,
+		&dst.Status,
+		&dst.Attempts,
+		&dst.Code,
+		&dst.Error,
+		&dst.Latency,
+		&dst.Payload,
+		&dst.NextRetry,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []*core.WebhookDelivery{}
+	for rows.Next() {
+		delivery := new(core.WebhookDelivery)
+		err := scanRow(rows, delivery)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
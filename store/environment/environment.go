@@ -0,0 +1,195 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environment
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new Environment database store.
+func New(db *db.DB) core.EnvironmentStore {
+	return &environmentStore{db}
+}
+
+type environmentStore struct {
+	db *db.DB
+}
+
+func (s *environmentStore) List(ctx context.Context, id int64) ([]*core.Environment, error) {
+	var out []*core.Environment
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"env_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *environmentStore) Find(ctx context.Context, id int64) (*core.Environment, error) {
+	out := &core.Environment{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *environmentStore) FindName(ctx context.Context, id int64, name string) (*core.Environment, error) {
+	out := &core.Environment{RepoID: id, Name: name}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryName, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *environmentStore) Create(ctx context.Context, env *core.Environment) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, env)
+	}
+	return s.create(ctx, env)
+}
+
+func (s *environmentStore) create(ctx context.Context, env *core.Environment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(env)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		env.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *environmentStore) createPostgres(ctx context.Context, env *core.Environment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(env)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&env.ID)
+	})
+}
+
+func (s *environmentStore) Update(ctx context.Context, env *core.Environment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(env)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *environmentStore) Delete(ctx context.Context, env *core.Environment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(env)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ env_id
+,env_repo_id
+,env_name
+,env_branches
+,env_approvers
+,env_wait
+,env_created
+,env_updated
+`
+
+const queryRepo = queryBase + `
+FROM environments
+WHERE env_repo_id = :env_repo_id
+ORDER BY env_name
+`
+
+const queryKey = queryBase + `
+FROM environments
+WHERE env_id = :env_id
+`
+
+const queryName = queryBase + `
+FROM environments
+WHERE env_repo_id = :env_repo_id
+  AND env_name = :env_name
+`
+
+const stmtUpdate = `
+UPDATE environments SET
+ env_name      = :env_name
+,env_branches  = :env_branches
+,env_approvers = :env_approvers
+,env_wait      = :env_wait
+,env_updated   = :env_updated
+WHERE env_id = :env_id
+`
+
+const stmtDelete = `
+DELETE FROM environments
+WHERE env_id = :env_id
+`
+
+const stmtInsert = `
+INSERT INTO environments (
+ env_repo_id
+,env_name
+,env_branches
+,env_approvers
+,env_wait
+,env_created
+,env_updated
+) VALUES (
+ :env_repo_id
+,:env_name
+,:env_branches
+,:env_approvers
+,:env_wait
+,:env_created
+,:env_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING env_id
+`
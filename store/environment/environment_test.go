@@ -0,0 +1,146 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environment
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestEnvironment(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn).(*environmentStore)
+	t.Run("Create", testEnvironmentCreate(store, repo))
+}
+
+func testEnvironmentCreate(store *environmentStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Environment{
+			RepoID:    repo.ID,
+			Name:      "production",
+			Branches:  []string{"master", "release/*"},
+			Approvers: []string{"octocat"},
+			Wait:      300,
+			Created:   1,
+			Updated:   2,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want environment ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testEnvironmentFind(store, item))
+		t.Run("FindName", testEnvironmentFindName(store, item))
+		t.Run("List", testEnvironmentList(store, item))
+		t.Run("Update", testEnvironmentUpdate(store, item))
+		t.Run("Delete", testEnvironmentDelete(store, item))
+	}
+}
+
+func testEnvironmentFind(store *environmentStore, env *core.Environment) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, env.ID)
+		if err != nil {
+			t.Error(err)
+		} else if diff := cmp.Diff(item, env); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testEnvironmentFindName(store *environmentStore, env *core.Environment) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.FindName(noContext, env.RepoID, env.Name)
+		if err != nil {
+			t.Error(err)
+		} else if diff := cmp.Diff(item, env); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testEnvironmentList(store *environmentStore, env *core.Environment) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, env.RepoID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], env); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testEnvironmentUpdate(store *environmentStore, env *core.Environment) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, env.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Approvers = []string{"octocat", "spaceghost"}
+		before.Wait = 0
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, env.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(after, before); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testEnvironmentDelete(store *environmentStore, env *core.Environment) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, env)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, env.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package environment
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Environment structure to a set
+// of named query parameters.
+func toParams(env *core.Environment) map[string]interface{} {
+	branches, _ := json.Marshal(env.Branches)
+	approvers, _ := json.Marshal(env.Approvers)
+	return map[string]interface{}{
+		"env_id":        env.ID,
+		"env_repo_id":   env.RepoID,
+		"env_name":      env.Name,
+		"env_branches":  string(branches),
+		"env_approvers": string(approvers),
+		"env_wait":      env.Wait,
+		"env_created":   env.Created,
+		"env_updated":   env.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Environment) error {
+	var branches, approvers string
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Name,
+		&branches,
+		&approvers,
+		&dst.Wait,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return err
+	}
+	json.Unmarshal([]byte(branches), &dst.Branches)
+	json.Unmarshal([]byte(approvers), &dst.Approvers)
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Environment, error) {
+	defer rows.Close()
+
+	envs := []*core.Environment{}
+	for rows.Next() {
+		env := new(core.Environment)
+		err := scanRow(rows, env)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
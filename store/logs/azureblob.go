@@ -8,6 +8,7 @@ package logs
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"net/url"
@@ -40,12 +41,21 @@ func (az *azureBlobStore) Find(ctx context.Context, step int64) (io.ReadCloser,
 	}
 	blobURL := az.containerURL.NewBlockBlobURL(fmt.Sprintf("%d", step))
 	out, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	// a missing blob is reported the same as a missing log
+	// in the database.
+	if serr, ok := err.(azblob.StorageError); ok && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+		return nil, sql.ErrNoRows
+	}
 	if err != nil {
 		return nil, err
 	}
 	return out.Body(azblob.RetryReaderOptions{}), nil
 }
 
+func (az *azureBlobStore) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	return query(ctx, az, step, q)
+}
+
 func (az *azureBlobStore) Create(ctx context.Context, step int64, r io.Reader) error {
 	err := az.getContainerURL()
 	if err != nil {
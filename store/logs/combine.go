@@ -44,6 +44,14 @@ func (s *combined) Find(ctx context.Context, step int64) (io.ReadCloser, error)
 	return s.secondary.Find(ctx, step)
 }
 
+func (s *combined) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	lines, err := s.primary.Query(ctx, step, q)
+	if err == nil {
+		return lines, err
+	}
+	return s.secondary.Query(ctx, step, q)
+}
+
 func (s *combined) Create(ctx context.Context, step int64, r io.Reader) error {
 	return s.primary.Create(ctx, step, r)
 }
@@ -0,0 +1,166 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/drone/drone/core"
+)
+
+// Compression algorithms.
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+)
+
+// ErrCompression is returned when the compression algorithm
+// is not supported.
+var ErrCompression = errors.New("logs: unsupported compression algorithm")
+
+// gzipMagic is the header that marks a compressed log. A log
+// stored without compression is a json-encoded array, and
+// cannot start with the gzip header.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// NewCompressed returns a new LogStore that compresses logs
+// before they are written to the underlying store, and
+// decompresses logs when they are read. Logs that were written
+// without compression are read without modification, which
+// means compression can be enabled and disabled at any time.
+func NewCompressed(store core.LogStore, algorithm string) (core.LogStore, error) {
+	switch algorithm {
+	case CompressionNone, CompressionGzip:
+	default:
+		return nil, ErrCompression
+	}
+	return &compressed{
+		store:     store,
+		algorithm: algorithm,
+	}, nil
+}
+
+type compressed struct {
+	store     core.LogStore
+	algorithm string
+}
+
+func (s *compressed) Find(ctx context.Context, step int64) (io.ReadCloser, error) {
+	rc, err := s.store.Find(ctx, step)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(rc)
+}
+
+func (s *compressed) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	return query(ctx, s, step, q)
+}
+
+func (s *compressed) Create(ctx context.Context, step int64, r io.Reader) error {
+	r, err := s.compress(r)
+	if err != nil {
+		return err
+	}
+	return s.store.Create(ctx, step, r)
+}
+
+func (s *compressed) Update(ctx context.Context, step int64, r io.Reader) error {
+	r, err := s.compress(r)
+	if err != nil {
+		return err
+	}
+	return s.store.Update(ctx, step, r)
+}
+
+func (s *compressed) Delete(ctx context.Context, step int64) error {
+	return s.store.Delete(ctx, step)
+}
+
+// compress returns a reader that compresses the log using
+// the configured algorithm.
+func (s *compressed) compress(r io.Reader) (io.Reader, error) {
+	if s.algorithm == CompressionNone {
+		return r, nil
+	}
+	return compress(r)
+}
+
+// helper function compresses the reader with gzip.
+func compress(r io.Reader) (io.Reader, error) {
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	if _, err := io.Copy(zw, r); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// helper function returns a reader that decompresses the
+// log if it was written with compression.
+func decompress(rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	if !isCompressed(br) {
+		return &readCloser{Reader: br, closer: rc}, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return &readCloser{Reader: zr, closer: rc}, nil
+}
+
+// helper function returns true if the buffered log starts
+// with the gzip header.
+func isCompressed(br *bufio.Reader) bool {
+	header, _ := br.Peek(len(gzipMagic))
+	return bytes.Equal(header, gzipMagic)
+}
+
+// readCloser reads from a decompressing reader and closes
+// the underlying log stream.
+type readCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *readCloser) Close() error {
+	if c, ok := r.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	return r.closer.Close()
+}
+
+// helper function returns the log data and true if the raw log
+// is stored without compression.
+func readUncompressed(rc io.ReadCloser) ([]byte, bool, error) {
+	defer rc.Close()
+	br := bufio.NewReader(rc)
+	if isCompressed(br) {
+		return nil, false, nil
+	}
+	data, err := ioutil.ReadAll(br)
+	return data, true, err
+}
@@ -0,0 +1,193 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+)
+
+func TestCompressed(t *testing.T) {
+	raw := newMemStore()
+	store, err := NewCompressed(raw, CompressionGzip)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = store.Create(noContext, 1, strings.NewReader(dummyLogs))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// verify the log is compressed in the underlying store.
+	rc, err := raw.Find(noContext, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data, _ := ioutil.ReadAll(rc)
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Errorf("Want log compressed in the underlying store")
+	}
+	if len(data) >= len(dummyLogs) {
+		t.Errorf("Want compressed log smaller than %d bytes, got %d", len(dummyLogs), len(data))
+	}
+
+	// verify the log is decompressed when read.
+	rc, err = store.Find(noContext, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data, _ = ioutil.ReadAll(rc)
+	rc.Close()
+	if got, want := string(data), dummyLogs; got != want {
+		t.Errorf("Want decompressed log %q, got %q", want, got)
+	}
+
+	// verify queries read the decompressed log.
+	lines, err := store.Query(noContext, 1, &core.LogQuery{Tail: 1})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(lines) != 1 || lines[0].Number != 4 {
+		t.Errorf("Want the last log line")
+	}
+}
+
+func TestCompressed_Uncompressed(t *testing.T) {
+	// an existing log stored without compression.
+	raw := newMemStore()
+	raw.Create(noContext, 1, strings.NewReader(dummyLogs))
+
+	for _, algorithm := range []string{CompressionNone, CompressionGzip} {
+		store, _ := NewCompressed(raw, algorithm)
+		rc, err := store.Find(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		if got, want := string(data), dummyLogs; got != want {
+			t.Errorf("Want uncompressed log %q, got %q", want, got)
+		}
+	}
+}
+
+func TestCompressed_Unsupported(t *testing.T) {
+	_, err := NewCompressed(nil, "lz4")
+	if err != ErrCompression {
+		t.Errorf("Want unsupported compression error")
+	}
+}
+
+func TestRecompress(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository, build and stage
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos.New(conn).Create(noContext, arepo)
+	stage := &core.Stage{Number: 1}
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	build.New(conn).Create(noContext, abuild, []*core.Stage{stage})
+
+	// seed with steps with uncompressed, compressed and
+	// missing logs.
+	steps := step.New(conn)
+	raw := New(conn)
+	store, _ := NewCompressed(raw, CompressionGzip)
+	for i := 1; i <= 3; i++ {
+		s := &core.Step{Number: i, StageID: stage.ID}
+		steps.Create(noContext, s)
+		switch i {
+		case 1:
+			raw.Create(noContext, s.ID, strings.NewReader(dummyLogs))
+		case 2:
+			store.Create(noContext, s.ID, strings.NewReader(dummyLogs))
+		}
+	}
+
+	result, err := Recompress(noContext, conn, raw)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := *result, (RecompressResult{Compressed: 1, Skipped: 2}); got != want {
+		t.Errorf("Want result %+v, got %+v", want, got)
+	}
+
+	list, _ := steps.List(noContext, stage.ID)
+	for _, s := range list[:2] {
+		rc, _ := raw.Find(noContext, s.ID)
+		data, _ := ioutil.ReadAll(rc)
+		if !bytes.HasPrefix(data, gzipMagic) {
+			t.Errorf("Want log for step %d compressed", s.Number)
+		}
+		rc, _ = store.Find(noContext, s.ID)
+		data, _ = ioutil.ReadAll(rc)
+		if got, want := string(data), dummyLogs; got != want {
+			t.Errorf("Want log for step %d %q, got %q", s.Number, want, got)
+		}
+	}
+}
+
+// memStore is an in-memory log store used for testing.
+type memStore struct {
+	logs map[int64][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{logs: map[int64][]byte{}}
+}
+
+func (s *memStore) Find(ctx context.Context, step int64) (io.ReadCloser, error) {
+	data, ok := s.logs[step]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memStore) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	return query(ctx, s, step, q)
+}
+
+func (s *memStore) Create(ctx context.Context, step int64, r io.Reader) error {
+	return s.Update(ctx, step, r)
+}
+
+func (s *memStore) Update(ctx context.Context, step int64, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	s.logs[step] = data
+	return err
+}
+
+func (s *memStore) Delete(ctx context.Context, step int64) error {
+	delete(s.logs, step)
+	return nil
+}
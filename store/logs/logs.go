@@ -48,6 +48,10 @@ func (s *logStore) Find(ctx context.Context, step int64) (io.ReadCloser, error)
 	), err
 }
 
+func (s *logStore) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	return query(ctx, s, step, q)
+}
+
 func (s *logStore) Create(ctx context.Context, step int64, r io.Reader) error {
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
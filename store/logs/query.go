@@ -0,0 +1,110 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+// query returns the log lines from the log store that match
+// the query. The log is decoded as a stream to avoid loading
+// the full log into memory.
+func query(ctx context.Context, store core.LogStore, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	rc, err := store.Find(ctx, step)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return queryLines(rc, q)
+}
+
+// queryLines returns the log lines from the json-encoded
+// reader that match the query.
+func queryLines(r io.Reader, q *core.LogQuery) ([]*core.Line, error) {
+	match, err := matcher(q)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return []*core.Line{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a log stored with a null value is treated as empty.
+	if tok == nil {
+		return []*core.Line{}, nil
+	}
+
+	lines := []*core.Line{}
+	index := 0
+	for dec.More() {
+		line := new(core.Line)
+		if err := dec.Decode(line); err != nil {
+			return nil, err
+		}
+		if !match(line) {
+			continue
+		}
+		switch {
+		case q.Tail > 0:
+			// retain the last N matching lines.
+			lines = append(lines, line)
+			if len(lines) > q.Tail {
+				lines = lines[1:]
+			}
+		case index < q.Offset:
+			// skip lines before the offset.
+		case q.Limit > 0 && len(lines) >= q.Limit:
+			// the limit is reached and the remaining
+			// lines do not need to be decoded.
+			return lines, nil
+		default:
+			lines = append(lines, line)
+		}
+		index++
+	}
+	return lines, nil
+}
+
+// helper function returns a function that matches log
+// lines against the query search string.
+func matcher(q *core.LogQuery) (func(*core.Line) bool, error) {
+	switch {
+	case q.Search == "":
+		return func(*core.Line) bool { return true }, nil
+	case q.Regexp:
+		re, err := regexp.Compile(q.Search)
+		if err != nil {
+			return nil, err
+		}
+		return func(line *core.Line) bool {
+			return re.MatchString(line.Message)
+		}, nil
+	default:
+		return func(line *core.Line) bool {
+			return strings.Contains(line.Message, q.Search)
+		}, nil
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+var dummyLogs = `[
+{"pos":0,"out":"+ go build\n","time":0},
+{"pos":1,"out":"+ go test\n","time":1},
+{"pos":2,"out":"ok  github.com/octocat/hello-world\n","time":2},
+{"pos":3,"out":"FAIL github.com/octocat/hello-world/foo\n","time":3},
+{"pos":4,"out":"+ go vet\n","time":4}
+]`
+
+func TestQueryLines(t *testing.T) {
+	tests := []struct {
+		query *core.LogQuery
+		want  []int
+	}{
+		{query: &core.LogQuery{}, want: []int{0, 1, 2, 3, 4}},
+		{query: &core.LogQuery{Offset: 1, Limit: 2}, want: []int{1, 2}},
+		{query: &core.LogQuery{Offset: 3}, want: []int{3, 4}},
+		{query: &core.LogQuery{Offset: 10}, want: []int{}},
+		{query: &core.LogQuery{Limit: 1}, want: []int{0}},
+		{query: &core.LogQuery{Tail: 2}, want: []int{3, 4}},
+		{query: &core.LogQuery{Tail: 10}, want: []int{0, 1, 2, 3, 4}},
+		{query: &core.LogQuery{Search: "+ go"}, want: []int{0, 1, 4}},
+		{query: &core.LogQuery{Search: "+ go", Tail: 1}, want: []int{4}},
+		{query: &core.LogQuery{Search: "+ go", Offset: 1, Limit: 1}, want: []int{1}},
+		{query: &core.LogQuery{Search: "^(ok|FAIL) ", Regexp: true}, want: []int{2, 3}},
+		{query: &core.LogQuery{Search: "^(ok|FAIL) "}, want: []int{}},
+	}
+	for i, test := range tests {
+		lines, err := queryLines(strings.NewReader(dummyLogs), test.query)
+		if err != nil {
+			t.Errorf("Want no error at index %d, got %s", i, err)
+			continue
+		}
+		var got []int
+		for _, line := range lines {
+			got = append(got, line.Number)
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Want lines %v at index %d, got %v", test.want, i, got)
+			continue
+		}
+		for j := range got {
+			if got[j] != test.want[j] {
+				t.Errorf("Want lines %v at index %d, got %v", test.want, i, got)
+				break
+			}
+		}
+	}
+}
+
+func TestQueryLines_Empty(t *testing.T) {
+	for _, data := range []string{"", "null", "[]"} {
+		lines, err := queryLines(strings.NewReader(data), &core.LogQuery{})
+		if err != nil {
+			t.Error(err)
+		}
+		if len(lines) != 0 {
+			t.Errorf("Want empty lines for %q", data)
+		}
+	}
+}
+
+func TestQueryLines_Invalid(t *testing.T) {
+	_, err := queryLines(strings.NewReader(dummyLogs), &core.LogQuery{Search: "(", Regexp: true})
+	if err == nil {
+		t.Errorf("Want invalid regular expression error")
+	}
+	_, err = queryLines(strings.NewReader(`[{"pos":0`), &core.LogQuery{})
+	if err == nil {
+		t.Errorf("Want invalid json error")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recompressBatch defines the number of steps loaded from
+// the database in each batch.
+const recompressBatch = 100
+
+// RecompressResult provides the results of recompression.
+type RecompressResult struct {
+	// Compressed is the number of logs compressed.
+	Compressed int
+
+	// Skipped is the number of logs that were already
+	// compressed, or could not be found.
+	Skipped int
+
+	// Failed is the number of logs that could not be
+	// compressed.
+	Failed int
+}
+
+// Recompress compresses the existing logs for every step in
+// the database. Logs are read from and written to the raw
+// store, which must not be wrapped with NewCompressed. Logs
+// that are already compressed are skipped, which means the
+// migration can be stopped and resumed at any time.
+func Recompress(ctx context.Context, conn *db.DB, store core.LogStore) (*RecompressResult, error) {
+	result := new(RecompressResult)
+	var last int64
+	for {
+		steps, err := listSteps(conn, last)
+		if err != nil {
+			return result, err
+		}
+		if len(steps) == 0 {
+			return result, nil
+		}
+		for _, step := range steps {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			last = step
+			recompress(ctx, store, step, result)
+		}
+	}
+}
+
+// helper function compresses the log for a single step.
+func recompress(ctx context.Context, store core.LogStore, step int64, result *RecompressResult) {
+	logger := logrus.WithField("step-id", step)
+
+	rc, err := store.Find(ctx, step)
+	if err != nil {
+		result.Skipped++
+		return
+	}
+	data, ok, err := readUncompressed(rc)
+	if err != nil {
+		logger.WithError(err).Warnln("logs: cannot read log")
+		result.Failed++
+		return
+	}
+	if !ok {
+		result.Skipped++
+		return
+	}
+	r, err := compress(bytes.NewReader(data))
+	if err == nil {
+		err = store.Update(ctx, step, r)
+	}
+	if err != nil {
+		logger.WithError(err).Warnln("logs: cannot compress log")
+		result.Failed++
+		return
+	}
+	logger.Debugln("logs: compressed log")
+	result.Compressed++
+}
+
+// helper function returns the next batch of step identifiers
+// greater than the given identifier.
+func listSteps(conn *db.DB, after int64) ([]int64, error) {
+	var out []int64
+	err := conn.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"step_id": after,
+			"limit":   recompressBatch,
+		}
+		stmt, args, err := binder.BindNamed(queryStepsAfter, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			out = append(out, id)
+		}
+		return rows.Err()
+	})
+	return out, err
+}
+
+const queryStepsAfter = `
+SELECT step_id
+FROM steps
+WHERE step_id > :step_id
+ORDER BY step_id ASC
+LIMIT :limit
+`
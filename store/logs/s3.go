@@ -8,12 +8,14 @@ package logs
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"path"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -63,12 +65,21 @@ func (s *s3store) Find(ctx context.Context, step int64) (io.ReadCloser, error) {
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(s.key(step)),
 	})
+	// a missing object is reported the same as a missing
+	// log in the database.
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, sql.ErrNoRows
+	}
 	if err != nil {
 		return nil, err
 	}
 	return out.Body, nil
 }
 
+func (s *s3store) Query(ctx context.Context, step int64, q *core.LogQuery) ([]*core.Line, error) {
+	return query(ctx, s, step, q)
+}
+
 func (s *s3store) Create(ctx context.Context, step int64, r io.Reader) error {
 	uploader := s3manager.NewUploader(s.session)
 	input := &s3manager.UploadInput{
@@ -6,7 +6,17 @@
 
 package logs
 
-import "testing"
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
 
 func TestKey(t *testing.T) {
 	tests := []struct {
@@ -35,3 +45,28 @@ func TestKey(t *testing.T) {
 		}
 	}
 }
+
+// this test verifies that a missing object is reported the
+// same as a missing log in the database.
+func TestS3_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		Endpoint:         aws.String(server.URL),
+		Region:           aws.String("us-east-1"),
+		DisableSSL:       aws.Bool(true),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	}))
+	store := NewS3(sess, "test-bucket", "drone/logs")
+	_, err := store.Find(context.Background(), 1)
+	if got, want := err, sql.ErrNoRows; got != want {
+		t.Errorf("Want error %v, got %v", want, got)
+	}
+}
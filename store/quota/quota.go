@@ -0,0 +1,189 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package quota
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new Quota database store.
+func New(db *db.DB) core.QuotaStore {
+	return &quotaStore{
+		db: db,
+	}
+}
+
+type quotaStore struct {
+	db *db.DB
+}
+
+func (s *quotaStore) List(ctx context.Context) ([]*core.Quota, error) {
+	var out []*core.Quota
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{}
+		stmt, args, err := binder.BindNamed(queryAll, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *quotaStore) Find(ctx context.Context, id int64) (*core.Quota, error) {
+	out := &core.Quota{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *quotaStore) FindName(ctx context.Context, kind, name string) (*core.Quota, error) {
+	out := &core.Quota{Kind: kind, Name: name}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryName, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *quotaStore) Create(ctx context.Context, quota *core.Quota) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, quota)
+	}
+	return s.create(ctx, quota)
+}
+
+func (s *quotaStore) create(ctx context.Context, quota *core.Quota) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(quota)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		quota.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *quotaStore) createPostgres(ctx context.Context, quota *core.Quota) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(quota)
+		stmt, args, err := binder.BindNamed(stmtInsertPostgres, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&quota.ID)
+	})
+}
+
+func (s *quotaStore) Update(ctx context.Context, quota *core.Quota) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(quota)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *quotaStore) Delete(ctx context.Context, quota *core.Quota) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(quota)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ quota_id
+,quota_kind
+,quota_name
+,quota_limit
+,quota_created
+,quota_updated
+`
+
+const queryKey = queryBase + `
+FROM quotas
+WHERE quota_id = :quota_id
+LIMIT 1
+`
+
+const queryName = queryBase + `
+FROM quotas
+WHERE quota_kind = :quota_kind
+  AND quota_name = :quota_name
+LIMIT 1
+`
+
+const queryAll = queryBase + `
+FROM quotas
+ORDER BY quota_kind, quota_name
+`
+
+const stmtInsert = `
+INSERT INTO quotas (
+ quota_kind
+,quota_name
+,quota_limit
+,quota_created
+,quota_updated
+) VALUES (
+ :quota_kind
+,:quota_name
+,:quota_limit
+,:quota_created
+,:quota_updated
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING quota_id
+`
+
+const stmtUpdate = `
+UPDATE quotas SET
+ quota_limit = :quota_limit
+,quota_updated = :quota_updated
+WHERE quota_id = :quota_id
+`
+
+const stmtDelete = `
+DELETE FROM quotas
+WHERE quota_id = :quota_id
+`
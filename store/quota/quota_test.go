@@ -0,0 +1,153 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package quota
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestQuota(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*quotaStore)
+	t.Run("Create", testQuotaCreate(store))
+}
+
+func testQuotaCreate(store *quotaStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Quota{
+			Kind:    core.QuotaNamespace,
+			Name:    "octocat",
+			Limit:   2,
+			Created: 1,
+			Updated: 2,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want quota ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testQuotaFind(store, item))
+		t.Run("FindName", testQuotaFindName(store))
+		t.Run("List", testQuotaList(store))
+		t.Run("Update", testQuotaUpdate(store))
+		t.Run("Delete", testQuotaDelete(store))
+	}
+}
+
+func testQuotaFind(store *quotaStore, quota *core.Quota) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, quota.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testQuota(item))
+		}
+	}
+}
+
+func testQuotaFindName(store *quotaStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.FindName(noContext, core.QuotaNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testQuota(item))
+		}
+	}
+}
+
+func testQuotaList(store *quotaStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testQuota(list[0]))
+		}
+	}
+}
+
+func testQuotaUpdate(store *quotaStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.FindName(noContext, core.QuotaNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Limit = 5
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, before.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Limit, 5; got != want {
+			t.Errorf("Want quota limit %d, got %d", want, got)
+		}
+	}
+}
+
+func testQuotaDelete(store *quotaStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		quota, err := store.FindName(noContext, core.QuotaNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = store.Delete(noContext, quota)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, quota.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testQuota(item *core.Quota) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Kind, core.QuotaNamespace; got != want {
+			t.Errorf("Want quota kind %q, got %q", want, got)
+		}
+		if got, want := item.Name, "octocat"; got != want {
+			t.Errorf("Want quota name %q, got %q", want, got)
+		}
+		if got, want := item.Limit, 2; got != want {
+			t.Errorf("Want quota limit %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package quota
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Quota structure to a set
+// of named query parameters.
+func toParams(quota *core.Quota) map[string]interface{} {
+	return map[string]interface{}{
+		"quota_id":      quota.ID,
+		"quota_kind":    quota.Kind,
+		"quota_name":    quota.Name,
+		"quota_limit":   quota.Limit,
+		"quota_created": quota.Created,
+		"quota_updated": quota.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Quota) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Kind,
+		&dst.Name,
+		&dst.Limit,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Quota, error) {
+	defer rows.Close()
+
+	quotas := []*core.Quota{}
+	for rows.Next() {
+		quota := new(core.Quota)
+		err := scanRow(rows, quota)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
@@ -284,6 +284,7 @@ SELECT
 ,repo_config
 ,repo_timeout
 ,repo_throttle
+,repo_priority
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
@@ -328,6 +329,7 @@ const queryColsBuilds = queryCols + `
 ,build_deploy
 ,build_deploy_id
 ,build_debug
+,build_timeout
 ,build_started
 ,build_finished
 ,build_created
@@ -380,6 +382,7 @@ INSERT INTO repos (
 ,repo_config
 ,repo_timeout
 ,repo_throttle
+,repo_priority
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
@@ -410,6 +413,7 @@ INSERT INTO repos (
 ,:repo_config
 ,:repo_timeout
 ,:repo_throttle
+,:repo_priority
 ,:repo_trusted
 ,:repo_protected
 ,:repo_no_forks
@@ -465,6 +469,7 @@ UPDATE repos SET
 ,repo_cancel_push = :repo_cancel_push
 ,repo_timeout = :repo_timeout
 ,repo_throttle = :repo_throttle
+,repo_priority = :repo_priority
 ,repo_counter = :repo_counter
 ,repo_synced = :repo_synced
 ,repo_created = :repo_created
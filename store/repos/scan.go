@@ -48,6 +48,7 @@ func ToParams(v *core.Repository) map[string]interface{} {
 		"repo_cancel_push":  v.CancelPush,
 		"repo_timeout":      v.Timeout,
 		"repo_throttle":     v.Throttle,
+		"repo_priority":     v.Priority,
 		"repo_counter":      v.Counter,
 		"repo_synced":       v.Synced,
 		"repo_created":      v.Created,
@@ -80,6 +81,7 @@ func scanRow(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Config,
 		&dest.Timeout,
 		&dest.Throttle,
+		&dest.Priority,
 		&dest.Trusted,
 		&dest.Protected,
 		&dest.IgnoreForks,
@@ -135,6 +137,7 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Config,
 		&dest.Timeout,
 		&dest.Throttle,
+		&dest.Priority,
 		&dest.Trusted,
 		&dest.Protected,
 		&dest.IgnoreForks,
@@ -177,6 +180,7 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&build.Deploy,
 		&build.DeployID,
 		&build.Debug,
+		&build.Timeout,
 		&build.Started,
 		&build.Finished,
 		&build.Created,
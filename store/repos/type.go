@@ -54,6 +54,7 @@ type nullBuild struct {
 	Deploy       sql.NullString
 	DeployID     sql.NullInt64
 	Debug        sql.NullBool
+	Timeout      sql.NullInt64
 	Started      sql.NullInt64
 	Finished     sql.NullInt64
 	Created      sql.NullInt64
@@ -95,6 +96,7 @@ func (b *nullBuild) value() *core.Build {
 		Deploy:       b.Deploy.String,
 		DeployID:     b.DeployID.Int64,
 		Debug:        b.Debug.Bool,
+		Timeout:      b.Timeout.Int64,
 		Started:      b.Started.Int64,
 		Finished:     b.Finished.Int64,
 		Created:      b.Created.Int64,
@@ -0,0 +1,197 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retention
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new Retention database store.
+func New(db *db.DB) core.RetentionStore {
+	return &retentionStore{
+		db: db,
+	}
+}
+
+type retentionStore struct {
+	db *db.DB
+}
+
+func (s *retentionStore) List(ctx context.Context) ([]*core.Retention, error) {
+	var out []*core.Retention
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{}
+		stmt, args, err := binder.BindNamed(queryAll, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *retentionStore) Find(ctx context.Context, id int64) (*core.Retention, error) {
+	out := &core.Retention{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *retentionStore) FindName(ctx context.Context, kind, name string) (*core.Retention, error) {
+	out := &core.Retention{Kind: kind, Name: name}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryName, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *retentionStore) Create(ctx context.Context, policy *core.Retention) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, policy)
+	}
+	return s.create(ctx, policy)
+}
+
+func (s *retentionStore) create(ctx context.Context, policy *core.Retention) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		policy.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *retentionStore) createPostgres(ctx context.Context, policy *core.Retention) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtInsertPostgres, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&policy.ID)
+	})
+}
+
+func (s *retentionStore) Update(ctx context.Context, policy *core.Retention) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *retentionStore) Delete(ctx context.Context, policy *core.Retention) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ retention_id
+,retention_kind
+,retention_name
+,retention_builds
+,retention_days
+,retention_log_days
+,retention_created
+,retention_updated
+`
+
+const queryKey = queryBase + `
+FROM retention
+WHERE retention_id = :retention_id
+LIMIT 1
+`
+
+const queryName = queryBase + `
+FROM retention
+WHERE retention_kind = :retention_kind
+  AND retention_name = :retention_name
+LIMIT 1
+`
+
+const queryAll = queryBase + `
+FROM retention
+ORDER BY retention_kind, retention_name
+`
+
+const stmtInsert = `
+INSERT INTO retention (
+ retention_kind
+,retention_name
+,retention_builds
+,retention_days
+,retention_log_days
+,retention_created
+,retention_updated
+) VALUES (
+ :retention_kind
+,:retention_name
+,:retention_builds
+,:retention_days
+,:retention_log_days
+,:retention_created
+,:retention_updated
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING retention_id
+`
+
+const stmtUpdate = `
+UPDATE retention SET
+ retention_builds = :retention_builds
+,retention_days = :retention_days
+,retention_log_days = :retention_log_days
+,retention_updated = :retention_updated
+WHERE retention_id = :retention_id
+`
+
+const stmtDelete = `
+DELETE FROM retention
+WHERE retention_id = :retention_id
+`
@@ -0,0 +1,157 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestRetention(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*retentionStore)
+	t.Run("Create", testRetentionCreate(store))
+}
+
+func testRetentionCreate(store *retentionStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Retention{
+			Kind:    core.RetentionNamespace,
+			Name:    "octocat",
+			Builds:  2,
+			LogDays: 7,
+			Created: 1,
+			Updated: 2,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want policy ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testRetentionFind(store, item))
+		t.Run("FindName", testRetentionFindName(store))
+		t.Run("List", testRetentionList(store))
+		t.Run("Update", testRetentionUpdate(store))
+		t.Run("Delete", testRetentionDelete(store))
+	}
+}
+
+func testRetentionFind(store *retentionStore, policy *core.Retention) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, policy.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testRetention(item))
+		}
+	}
+}
+
+func testRetentionFindName(store *retentionStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.FindName(noContext, core.RetentionNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testRetention(item))
+		}
+	}
+}
+
+func testRetentionList(store *retentionStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testRetention(list[0]))
+		}
+	}
+}
+
+func testRetentionUpdate(store *retentionStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.FindName(noContext, core.RetentionNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Builds = 5
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, before.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Builds, 5; got != want {
+			t.Errorf("Want policy builds %d, got %d", want, got)
+		}
+	}
+}
+
+func testRetentionDelete(store *retentionStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		policy, err := store.FindName(noContext, core.RetentionNamespace, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = store.Delete(noContext, policy)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, policy.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testRetention(item *core.Retention) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Kind, core.RetentionNamespace; got != want {
+			t.Errorf("Want policy kind %q, got %q", want, got)
+		}
+		if got, want := item.Name, "octocat"; got != want {
+			t.Errorf("Want policy name %q, got %q", want, got)
+		}
+		if got, want := item.Builds, 2; got != want {
+			t.Errorf("Want policy builds %d, got %d", want, got)
+		}
+		if got, want := item.LogDays, 7; got != want {
+			t.Errorf("Want policy log days %d, got %d", want, got)
+		}
+	}
+}
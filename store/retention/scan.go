@@ -0,0 +1,61 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retention
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Retention structure to a set
+// of named query parameters.
+func toParams(policy *core.Retention) map[string]interface{} {
+	return map[string]interface{}{
+		"retention_id":       policy.ID,
+		"retention_kind":     policy.Kind,
+		"retention_name":     policy.Name,
+		"retention_builds":   policy.Builds,
+		"retention_days":     policy.Days,
+		"retention_log_days": policy.LogDays,
+		"retention_created":  policy.Created,
+		"retention_updated":  policy.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Retention) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Kind,
+		&dst.Name,
+		&dst.Builds,
+		&dst.Days,
+		&dst.LogDays,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Retention, error) {
+	defer rows.Close()
+
+	retention := []*core.Retention{}
+	for rows.Next() {
+		policy := new(core.Retention)
+		err := scanRow(rows, policy)
+		if err != nil {
+			return nil, err
+		}
+		retention = append(retention, policy)
+	}
+	return retention, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new RetryStore.
+func New(db *db.DB) core.RetryStore {
+	return &retryStore{db}
+}
+
+type retryStore struct {
+	db *db.DB
+}
+
+func (s *retryStore) List(ctx context.Context, id int64) ([]*core.Retry, error) {
+	var out []*core.Retry
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"retry_stage_id": id}
+		stmt, args, err := binder.BindNamed(queryStage, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *retryStore) ListFlaky(ctx context.Context, id int64) ([]*core.FlakyStep, error) {
+	var out []*core.FlakyStep
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"retry_repo_id": id,
+			"retry_passed":  true,
+		}
+		stmt, args, err := binder.BindNamed(queryFlaky, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanFlakyRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *retryStore) Create(ctx context.Context, retry *core.Retry) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, retry)
+	}
+	return s.create(ctx, retry)
+}
+
+func (s *retryStore) create(ctx context.Context, retry *core.Retry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(retry)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		retry.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *retryStore) createPostgres(ctx context.Context, retry *core.Retry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(retry)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&retry.ID)
+	})
+}
+
+func (s *retryStore) Pass(ctx context.Context, id int64, step string) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"retry_stage_id": id,
+			"retry_step":     step,
+			"retry_passed":   true,
+		}
+		stmt, args, err := binder.BindNamed(stmtPass, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ retry_id
+,retry_repo_id
+,retry_build_id
+,retry_stage_id
+,retry_stage
+,retry_step
+,retry_attempt
+,retry_exit_code
+,retry_error
+,retry_passed
+,retry_created
+`
+
+const queryStage = queryBase + `
+FROM retries
+WHERE retry_stage_id = :retry_stage_id
+ORDER BY retry_id ASC
+`
+
+const queryFlaky = `
+SELECT
+ retry_stage
+,retry_step
+,COUNT(*)
+,SUM(CASE WHEN retry_passed = :retry_passed THEN 1 ELSE 0 END)
+,MAX(retry_created)
+FROM retries
+WHERE retry_repo_id = :retry_repo_id
+GROUP BY retry_stage, retry_step
+HAVING SUM(CASE WHEN retry_passed = :retry_passed THEN 1 ELSE 0 END) > 0
+ORDER BY MAX(retry_created) DESC
+`
+
+const stmtInsert = `
+INSERT INTO retries (
+ retry_repo_id
+,retry_build_id
+,retry_stage_id
+,retry_stage
+,retry_step
+,retry_attempt
+,retry_exit_code
+,retry_error
+,retry_passed
+,retry_created
+) VALUES (
+ :retry_repo_id
+,:retry_build_id
+,:retry_stage_id
+,:retry_stage
+,:retry_step
+,:retry_attempt
+,:retry_exit_code
+,:retry_error
+,:retry_passed
+,:retry_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING retry_id
+`
+
+const stmtPass = `
+UPDATE retries
+SET retry_passed = :retry_passed
+WHERE retry_stage_id = :retry_stage_id
+  AND retry_step = :retry_step
+`
@@ -0,0 +1,161 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package retry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestRetry(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1, Name: "default"}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	store := New(conn).(*retryStore)
+	t.Run("Create", testRetryCreate(store, arepo, abuild, stage))
+}
+
+func testRetryCreate(store *retryStore, repo *core.Repository, build *core.Build, stage *core.Stage) func(t *testing.T) {
+	return func(t *testing.T) {
+		items := []*core.Retry{
+			{
+				RepoID:   repo.ID,
+				BuildID:  build.ID,
+				StageID:  stage.ID,
+				Stage:    stage.Name,
+				Step:     "test",
+				Attempt:  1,
+				ExitCode: 137,
+				Error:    "OOMKilled",
+				Created:  1522878684,
+			},
+			{
+				RepoID:   repo.ID,
+				BuildID:  build.ID,
+				StageID:  stage.ID,
+				Stage:    stage.Name,
+				Step:     "lint",
+				Attempt:  1,
+				ExitCode: 1,
+				Created:  1522878684,
+			},
+		}
+		for _, item := range items {
+			err := store.Create(noContext, item)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if item.ID == 0 {
+				t.Errorf("Want ID assigned, got %d", item.ID)
+			}
+		}
+
+		t.Run("List", testRetryList(store, stage))
+		t.Run("Pass", testRetryPass(store, repo, stage))
+	}
+}
+
+func testRetryList(store *retryStore, stage *core.Stage) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, stage.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		item := list[0]
+		if got, want := item.Step, "test"; got != want {
+			t.Errorf("Want Step %q, got %q", want, got)
+		}
+		if got, want := item.ExitCode, 137; got != want {
+			t.Errorf("Want ExitCode %d, got %d", want, got)
+		}
+		if got, want := item.Error, "OOMKilled"; got != want {
+			t.Errorf("Want Error %q, got %q", want, got)
+		}
+		if item.Passed {
+			t.Errorf("Want Passed false")
+		}
+	}
+}
+
+func testRetryPass(store *retryStore, repo *core.Repository, stage *core.Stage) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListFlaky(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want flaky count %d, got %d", want, got)
+		}
+
+		err = store.Pass(noContext, stage.ID, "test")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		list, err = store.ListFlaky(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want flaky count %d, got %d", want, got)
+			return
+		}
+		flaky := list[0]
+		if got, want := flaky.Stage, "default"; got != want {
+			t.Errorf("Want Stage %q, got %q", want, got)
+		}
+		if got, want := flaky.Step, "test"; got != want {
+			t.Errorf("Want Step %q, got %q", want, got)
+		}
+		if got, want := flaky.Retries, int64(1); got != want {
+			t.Errorf("Want Retries %d, got %d", want, got)
+		}
+		if got, want := flaky.Passed, int64(1); got != want {
+			t.Errorf("Want Passed %d, got %d", want, got)
+		}
+		if got, want := flaky.Last, int64(1522878684); got != want {
+			t.Errorf("Want Last %d, got %d", want, got)
+		}
+	}
+}
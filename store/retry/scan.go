@@ -0,0 +1,98 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Retry structure to a set
+// of named query parameters.
+func toParams(retry *core.Retry) map[string]interface{} {
+	return map[string]interface{}{
+		"retry_id":        retry.ID,
+		"retry_repo_id":   retry.RepoID,
+		"retry_build_id":  retry.BuildID,
+		"retry_stage_id":  retry.StageID,
+		"retry_stage":     retry.Stage,
+		"retry_step":      retry.Step,
+		"retry_attempt":   retry.Attempt,
+		"retry_exit_code": retry.ExitCode,
+		"retry_error":     retry.Error,
+		"retry_passed":    retry.Passed,
+		"retry_created":   retry.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.Retry) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.RepoID,
+		&dest.BuildID,
+		&dest.StageID,
+		&dest.Stage,
+		&dest.Step,
+		&dest.Attempt,
+		&dest.ExitCode,
+		&dest.Error,
+		&dest.Passed,
+		&dest.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Retry, error) {
+	defer rows.Close()
+
+	retries := []*core.Retry{}
+	for rows.Next() {
+		retry := new(core.Retry)
+		err := scanRow(rows, retry)
+		if err != nil {
+			return nil, err
+		}
+		retries = append(retries, retry)
+	}
+	return retries, nil
+}
+
+// helper function scans the sql.Row and copies the aggregated
+// column values to the destination object.
+func scanFlakyRows(rows *sql.Rows) ([]*core.FlakyStep, error) {
+	defer rows.Close()
+
+	steps := []*core.FlakyStep{}
+	for rows.Next() {
+		step := new(core.FlakyStep)
+		err := rows.Scan(
+			&step.Stage,
+			&step.Step,
+			&step.Retries,
+			&step.Passed,
+			&step.Last,
+		)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -8,6 +8,7 @@ package global
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
@@ -21,6 +22,10 @@ func toParams(encrypt encrypt.Encrypter, secret *core.Secret) (map[string]interf
 	if err != nil {
 		return nil, err
 	}
+	branches, _ := json.Marshal(secret.Branches)
+	refs, _ := json.Marshal(secret.Refs)
+	events, _ := json.Marshal(secret.Events)
+	images, _ := json.Marshal(secret.Images)
 	return map[string]interface{}{
 		"secret_id":                secret.ID,
 		"secret_namespace":         secret.Namespace,
@@ -29,6 +34,11 @@ func toParams(encrypt encrypt.Encrypter, secret *core.Secret) (map[string]interf
 		"secret_data":              ciphertext,
 		"secret_pull_request":      secret.PullRequest,
 		"secret_pull_request_push": secret.PullRequestPush,
+		"secret_version":           secret.Version,
+		"secret_branches":          string(branches),
+		"secret_refs":              string(refs),
+		"secret_events":            string(events),
+		"secret_images":            string(images),
 	}, nil
 }
 
@@ -36,6 +46,7 @@ func toParams(encrypt encrypt.Encrypter, secret *core.Secret) (map[string]interf
 // values to the destination object.
 func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) error {
 	var ciphertext []byte
+	var branches, refs, events, images string
 	err := scanner.Scan(
 		&dst.ID,
 		&dst.Namespace,
@@ -44,10 +55,19 @@ func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) er
 		&ciphertext,
 		&dst.PullRequest,
 		&dst.PullRequestPush,
+		&dst.Version,
+		&branches,
+		&refs,
+		&events,
+		&images,
 	)
 	if err != nil {
 		return err
 	}
+	json.Unmarshal([]byte(branches), &dst.Branches)
+	json.Unmarshal([]byte(refs), &dst.Refs)
+	json.Unmarshal([]byte(events), &dst.Events)
+	json.Unmarshal([]byte(images), &dst.Images)
 	plaintext, err := encrypt.Decrypt(ciphertext)
 	if err != nil {
 		return err
@@ -56,6 +76,23 @@ func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) er
 	return nil
 }
 
+// helper function converts the SecretVersion structure to a
+// set of named query parameters.
+func toVersionParams(encrypt encrypt.Encrypter, version *core.SecretVersion) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(version.Data)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"version_kind":       version.Kind,
+		"version_secret_id":  version.SecretID,
+		"version_number":     version.Version,
+		"version_data":       ciphertext,
+		"version_created_by": version.CreatedBy,
+		"version_created":    version.Created,
+	}, nil
+}
+
 // helper function scans the sql.Row and copies the column
 // values to the destination object.
 func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Secret, error) {
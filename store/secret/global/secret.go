@@ -8,6 +8,7 @@ package global
 
 import (
 	"context"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
@@ -93,6 +94,7 @@ func (s *secretStore) FindName(ctx context.Context, namespace, name string) (*co
 }
 
 func (s *secretStore) Create(ctx context.Context, secret *core.Secret) error {
+	secret.Version = 1
 	if s.db.Driver() == db.Postgres {
 		return s.createPostgres(ctx, secret)
 	}
@@ -100,7 +102,7 @@ func (s *secretStore) Create(ctx context.Context, secret *core.Secret) error {
 }
 
 func (s *secretStore) create(ctx context.Context, secret *core.Secret) error {
-	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
 		params, err := toParams(s.enc, secret)
 		if err != nil {
 			return err
@@ -114,12 +116,15 @@ func (s *secretStore) create(ctx context.Context, secret *core.Secret) error {
 			return err
 		}
 		secret.ID, err = res.LastInsertId()
-		return err
+		if err != nil {
+			return err
+		}
+		return s.createVersion(execer, binder, secret)
 	})
 }
 
 func (s *secretStore) createPostgres(ctx context.Context, secret *core.Secret) error {
-	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
 		params, err := toParams(s.enc, secret)
 		if err != nil {
 			return err
@@ -128,13 +133,43 @@ func (s *secretStore) createPostgres(ctx context.Context, secret *core.Secret) e
 		if err != nil {
 			return err
 		}
-		return execer.QueryRow(stmt, args...).Scan(&secret.ID)
+		err = execer.QueryRow(stmt, args...).Scan(&secret.ID)
+		if err != nil {
+			return err
+		}
+		return s.createVersion(execer, binder, secret)
 	})
 }
 
 func (s *secretStore) Update(ctx context.Context, secret *core.Secret) error {
-	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
-		params, err := toParams(s.enc, secret)
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
+		current := &core.Secret{ID: secret.ID}
+		params, err := toParams(s.enc, current)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		err = scanRow(s.enc, execer.QueryRow(query, args...), current)
+		if err != nil {
+			return err
+		}
+
+		// a new version is only created when the secret value
+		// changes. Updates to the secret settings, such as the
+		// pull request flags, do not create a new version.
+		secret.Version = current.Version
+		if secret.Data != current.Data {
+			secret.Version++
+			err = s.createVersion(execer, binder, secret)
+			if err != nil {
+				return err
+			}
+		}
+
+		params, err = toParams(s.enc, secret)
 		if err != nil {
 			return err
 		}
@@ -148,12 +183,21 @@ func (s *secretStore) Update(ctx context.Context, secret *core.Secret) error {
 }
 
 func (s *secretStore) Delete(ctx context.Context, secret *core.Secret) error {
-	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
 		params, err := toParams(s.enc, secret)
 		if err != nil {
 			return err
 		}
-		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		params["version_kind"] = core.SecretOrganization
+		stmt, args, err := binder.BindNamed(stmtDeleteVersions, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		stmt, args, err = binder.BindNamed(stmtDelete, params)
 		if err != nil {
 			return err
 		}
@@ -162,6 +206,28 @@ func (s *secretStore) Delete(ctx context.Context, secret *core.Secret) error {
 	})
 }
 
+// helper function records the current secret value as a
+// new secret version.
+func (s *secretStore) createVersion(execer db.Execer, binder db.Binder, secret *core.Secret) error {
+	params, err := toVersionParams(s.enc, &core.SecretVersion{
+		Kind:      core.SecretOrganization,
+		SecretID:  secret.ID,
+		Version:   secret.Version,
+		Data:      secret.Data,
+		CreatedBy: secret.Author,
+		Created:   time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	stmt, args, err := binder.BindNamed(stmtInsertVersion, params)
+	if err != nil {
+		return err
+	}
+	_, err = execer.Exec(stmt, args...)
+	return err
+}
+
 const queryBase = `
 SELECT
  secret_id
@@ -171,6 +237,11 @@ SELECT
 ,secret_data
 ,secret_pull_request
 ,secret_pull_request_push
+,secret_version
+,secret_branches
+,secret_refs
+,secret_events
+,secret_images
 `
 
 const queryKey = queryBase + `
@@ -202,6 +273,11 @@ UPDATE orgsecrets SET
  secret_data = :secret_data
 ,secret_pull_request = :secret_pull_request
 ,secret_pull_request_push = :secret_pull_request_push
+,secret_version = :secret_version
+,secret_branches = :secret_branches
+,secret_refs = :secret_refs
+,secret_events = :secret_events
+,secret_images = :secret_images
 WHERE secret_id = :secret_id
 `
 
@@ -218,6 +294,11 @@ INSERT INTO orgsecrets (
 ,secret_data
 ,secret_pull_request
 ,secret_pull_request_push
+,secret_version
+,secret_branches
+,secret_refs
+,secret_events
+,secret_images
 ) VALUES (
  :secret_namespace
 ,:secret_name
@@ -225,9 +306,38 @@ INSERT INTO orgsecrets (
 ,:secret_data
 ,:secret_pull_request
 ,:secret_pull_request_push
+,:secret_version
+,:secret_branches
+,:secret_refs
+,:secret_events
+,:secret_images
 )
 `
 
 const stmtInsertPg = stmtInsert + `
 RETURNING secret_id
 `
+
+const stmtDeleteVersions = `
+DELETE FROM secret_versions
+WHERE version_kind = :version_kind
+  AND version_secret_id = :secret_id
+`
+
+const stmtInsertVersion = `
+INSERT INTO secret_versions (
+ version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+) VALUES (
+ :version_kind
+,:version_secret_id
+,:version_number
+,:version_data
+,:version_created_by
+,:version_created
+)
+`
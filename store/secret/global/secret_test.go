@@ -122,13 +122,22 @@ func testSecretUpdate(store *secretStore) func(t *testing.T) {
 			t.Error(err)
 			return
 		}
+		if got, want := before.Version, int64(1); got != want {
+			t.Errorf("Want unchanged secret version %d, got %d", want, got)
+		}
+		before.Data = "tr0ub4dor&3"
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
 		after, err := store.Find(noContext, before.ID)
 		if err != nil {
 			t.Error(err)
 			return
 		}
-		if after == nil {
-			t.Fail()
+		if got, want := after.Version, int64(2); got != want {
+			t.Errorf("Want secret version %d, got %d", want, got)
 		}
 	}
 }
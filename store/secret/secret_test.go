@@ -15,6 +15,8 @@ import (
 	"github.com/drone/drone/store/repos"
 	"github.com/drone/drone/store/shared/db/dbtest"
 	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 var noContext = context.TODO()
@@ -45,9 +47,12 @@ func TestSecret(t *testing.T) {
 func testSecretCreate(store *secretStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
 	return func(t *testing.T) {
 		item := &core.Secret{
-			RepoID: repo.ID,
-			Name:   "password",
-			Data:   "correct-horse-battery-staple",
+			RepoID:   repo.ID,
+			Name:     "password",
+			Data:     "correct-horse-battery-staple",
+			Branches: []string{"master"},
+			Events:   []string{core.EventPromote},
+			Images:   []string{"plugins/docker"},
 		}
 		err := store.Create(noContext, item)
 		if err != nil {
@@ -73,6 +78,24 @@ func testSecretFind(store *secretStore, secret *core.Secret) func(t *testing.T)
 			t.Error(err)
 		} else {
 			t.Run("Fields", testSecret(item))
+			t.Run("Restrictions", testSecretRestrictions(item))
+		}
+	}
+}
+
+func testSecretRestrictions(item *core.Secret) func(t *testing.T) {
+	return func(t *testing.T) {
+		if diff := cmp.Diff(item.Branches, []string{"master"}); diff != "" {
+			t.Errorf(diff)
+		}
+		if diff := cmp.Diff(item.Events, []string{core.EventPromote}); diff != "" {
+			t.Errorf(diff)
+		}
+		if diff := cmp.Diff(item.Images, []string{"plugins/docker"}); diff != "" {
+			t.Errorf(diff)
+		}
+		if len(item.Refs) != 0 {
+			t.Errorf("Want empty secret refs, got %v", item.Refs)
 		}
 	}
 }
@@ -115,13 +138,25 @@ func testSecretUpdate(store *secretStore, repo *core.Repository) func(t *testing
 			t.Error(err)
 			return
 		}
+		if got, want := before.Version, int64(1); got != want {
+			t.Errorf("Want unchanged secret version %d, got %d", want, got)
+		}
+		before.Data = "tr0ub4dor&3"
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
 		after, err := store.Find(noContext, before.ID)
 		if err != nil {
 			t.Error(err)
 			return
 		}
-		if after == nil {
-			t.Fail()
+		if got, want := after.Version, int64(2); got != want {
+			t.Errorf("Want secret version %d, got %d", want, got)
+		}
+		if got, want := after.Data, "tr0ub4dor&3"; got != want {
+			t.Errorf("Want secret data %q, got %q", want, got)
 		}
 	}
 }
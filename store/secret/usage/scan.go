@@ -0,0 +1,65 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package usage
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the SecretUsage structure to a set
+// of named query parameters.
+func toParams(usage *core.SecretUsage) map[string]interface{} {
+	return map[string]interface{}{
+		"usage_id":           usage.ID,
+		"usage_repo_id":      usage.RepoID,
+		"usage_build_id":     usage.BuildID,
+		"usage_build_number": usage.BuildNumber,
+		"usage_stage_id":     usage.StageID,
+		"usage_kind":         usage.Kind,
+		"usage_secret_id":    usage.SecretID,
+		"usage_name":         usage.Name,
+		"usage_version":      usage.Version,
+		"usage_created":      usage.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.SecretUsage) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.BuildID,
+		&dst.BuildNumber,
+		&dst.StageID,
+		&dst.Kind,
+		&dst.SecretID,
+		&dst.Name,
+		&dst.Version,
+		&dst.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.SecretUsage, error) {
+	defer rows.Close()
+
+	usages := []*core.SecretUsage{}
+	for rows.Next() {
+		usage := new(core.SecretUsage)
+		err := scanRow(rows, usage)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
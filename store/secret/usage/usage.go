@@ -0,0 +1,153 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package usage
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new SecretUsage database store.
+func New(db *db.DB) core.SecretUsageStore {
+	return &usageStore{db}
+}
+
+type usageStore struct {
+	db *db.DB
+}
+
+func (s *usageStore) List(ctx context.Context, build int64) ([]*core.SecretUsage, error) {
+	var out []*core.SecretUsage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"usage_build_id": build}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *usageStore) ListVersion(ctx context.Context, kind string, secret, version int64) ([]*core.SecretUsage, error) {
+	var out []*core.SecretUsage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"usage_kind":      kind,
+			"usage_secret_id": secret,
+			"usage_version":   version,
+		}
+		stmt, args, err := binder.BindNamed(queryVersion, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *usageStore) Create(ctx context.Context, usage *core.SecretUsage) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, usage)
+	}
+	return s.create(ctx, usage)
+}
+
+func (s *usageStore) create(ctx context.Context, usage *core.SecretUsage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(usage)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		usage.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *usageStore) createPostgres(ctx context.Context, usage *core.SecretUsage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(usage)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&usage.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ usage_id
+,usage_repo_id
+,usage_build_id
+,usage_build_number
+,usage_stage_id
+,usage_kind
+,usage_secret_id
+,usage_name
+,usage_version
+,usage_created
+`
+
+const queryBuild = queryBase + `
+FROM secret_usage
+WHERE usage_build_id = :usage_build_id
+ORDER BY usage_stage_id, usage_name
+`
+
+const queryVersion = queryBase + `
+FROM secret_usage
+WHERE usage_kind = :usage_kind
+  AND usage_secret_id = :usage_secret_id
+  AND usage_version = :usage_version
+ORDER BY usage_id DESC
+`
+
+const stmtInsert = `
+INSERT INTO secret_usage (
+ usage_repo_id
+,usage_build_id
+,usage_build_number
+,usage_stage_id
+,usage_kind
+,usage_secret_id
+,usage_name
+,usage_version
+,usage_created
+) VALUES (
+ :usage_repo_id
+,:usage_build_id
+,:usage_build_number
+,:usage_stage_id
+,:usage_kind
+,:usage_secret_id
+,:usage_name
+,:usage_version
+,:usage_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING usage_id
+`
@@ -0,0 +1,43 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build oss
+
+package usage
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new SecretUsage database store.
+func New(db *db.DB) core.SecretUsageStore {
+	return new(noop)
+}
+
+type noop struct{}
+
+func (noop) List(context.Context, int64) ([]*core.SecretUsage, error) {
+	return nil, nil
+}
+
+func (noop) ListVersion(context.Context, string, int64, int64) ([]*core.SecretUsage, error) {
+	return nil, nil
+}
+
+func (noop) Create(context.Context, *core.SecretUsage) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestUsage(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository and build.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	if err := repos.New(conn).Create(noContext, repo); err != nil {
+		t.Error(err)
+		return
+	}
+	item := &core.Build{RepoID: repo.ID, Number: 1}
+	if err := build.New(conn).Create(noContext, item, nil); err != nil {
+		t.Error(err)
+		return
+	}
+
+	store := New(conn).(*usageStore)
+	t.Run("Create", testUsageCreate(store, item))
+}
+
+func testUsageCreate(store *usageStore, build *core.Build) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, version := range []int64{1, 2} {
+			item := &core.SecretUsage{
+				RepoID:      build.RepoID,
+				BuildID:     build.ID,
+				BuildNumber: build.Number,
+				StageID:     version,
+				Kind:        core.SecretRepository,
+				SecretID:    1,
+				Name:        "password",
+				Version:     version,
+				Created:     1,
+			}
+			err := store.Create(noContext, item)
+			if err != nil {
+				t.Error(err)
+			}
+			if item.ID == 0 {
+				t.Errorf("Want usage ID assigned, got %d", item.ID)
+			}
+		}
+
+		t.Run("List", testUsageList(store, build))
+		t.Run("ListVersion", testUsageListVersion(store, build))
+	}
+}
+
+func testUsageList(store *usageStore, build *core.Build) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, build.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d usage records, got %d", want, got)
+		}
+	}
+}
+
+func testUsageListVersion(store *usageStore, build *core.Build) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListVersion(noContext, core.SecretRepository, 1, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d usage records, got %d", want, got)
+			return
+		}
+		if got, want := list[0].BuildNumber, build.Number; got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+		if got, want := list[0].Name, "password"; got != want {
+			t.Errorf("Want secret name %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package version
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.SecretVersion) error {
+	var ciphertext []byte
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.Kind,
+		&dst.SecretID,
+		&dst.Version,
+		&ciphertext,
+		&dst.CreatedBy,
+		&dst.Created,
+	)
+	if err != nil {
+		return err
+	}
+	plaintext, err := encrypt.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	dst.Data = plaintext
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.SecretVersion, error) {
+	defer rows.Close()
+
+	versions := []*core.SecretVersion{}
+	for rows.Next() {
+		version := new(core.SecretVersion)
+		err := scanRow(encrypt, rows, version)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package version
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new SecretVersion database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.SecretVersionStore {
+	return &versionStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type versionStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *versionStore) List(ctx context.Context, kind string, secret int64) ([]*core.SecretVersion, error) {
+	var out []*core.SecretVersion
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"version_kind":      kind,
+			"version_secret_id": secret,
+		}
+		stmt, args, err := binder.BindNamed(querySecret, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *versionStore) Find(ctx context.Context, kind string, secret, version int64) (*core.SecretVersion, error) {
+	out := &core.SecretVersion{Kind: kind, SecretID: secret, Version: version}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"version_kind":      kind,
+			"version_secret_id": secret,
+			"version_number":    version,
+		}
+		query, args, err := binder.BindNamed(queryNumber, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(s.enc, row, out)
+	})
+	return out, err
+}
+
+const queryBase = `
+SELECT
+ version_id
+,version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+`
+
+const querySecret = queryBase + `
+FROM secret_versions
+WHERE version_kind = :version_kind
+  AND version_secret_id = :version_secret_id
+ORDER BY version_number DESC
+`
+
+const queryNumber = queryBase + `
+FROM secret_versions
+WHERE version_kind = :version_kind
+  AND version_secret_id = :version_secret_id
+  AND version_number = :version_number
+LIMIT 1
+`
@@ -0,0 +1,121 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package version
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/secret/global"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+var noContext = context.TODO()
+
+func TestVersion(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	enc, _ := encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	secrets := global.New(conn, enc)
+	secret := &core.Secret{
+		Namespace: "octocat",
+		Name:      "password",
+		Data:      "correct-horse-battery-staple",
+		Author:    "octocat",
+	}
+	if err := secrets.Create(noContext, secret); err != nil {
+		t.Error(err)
+		return
+	}
+	secret.Data = "tr0ub4dor&3"
+	secret.Author = "spaceghost"
+	if err := secrets.Update(noContext, secret); err != nil {
+		t.Error(err)
+		return
+	}
+
+	store := New(conn, enc).(*versionStore)
+	t.Run("List", testVersionList(store, secret))
+	t.Run("Find", testVersionFind(store, secret))
+	t.Run("Delete", testVersionDelete(store, secrets, secret))
+}
+
+func testVersionList(store *versionStore, secret *core.Secret) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, core.SecretOrganization, secret.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d versions, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Version, int64(2); got != want {
+			t.Errorf("Want newest version %d first, got %d", want, got)
+		}
+		if got, want := list[0].CreatedBy, "spaceghost"; got != want {
+			t.Errorf("Want version created by %q, got %q", want, got)
+		}
+		if got, want := list[0].Data, "tr0ub4dor&3"; got != want {
+			t.Errorf("Want version data %q, got %q", want, got)
+		}
+	}
+}
+
+func testVersionFind(store *versionStore, secret *core.Secret) func(t *testing.T) {
+	return func(t *testing.T) {
+		version, err := store.Find(noContext, core.SecretOrganization, secret.ID, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := version.Data, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want version data %q, got %q", want, got)
+		}
+		if got, want := version.CreatedBy, "octocat"; got != want {
+			t.Errorf("Want version created by %q, got %q", want, got)
+		}
+		if version.Created == 0 {
+			t.Errorf("Want version created timestamp")
+		}
+
+		_, err = store.Find(noContext, core.SecretRepository, secret.ID, 1)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows for mismatched secret kind, got %v", err)
+		}
+	}
+}
+
+func testVersionDelete(store *versionStore, secrets core.GlobalSecretStore, secret *core.Secret) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := secrets.Delete(noContext, secret)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, core.SecretOrganization, secret.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(list) != 0 {
+			t.Errorf("Want versions deleted with the secret, got %d", len(list))
+		}
+	}
+}
@@ -49,6 +49,11 @@ func Reset(d *db.DB) {
 	d.Lock(func(tx db.Execer, _ db.Binder) error {
 		tx.Exec("DELETE FROM cron")
 		tx.Exec("DELETE FROM logs")
+		tx.Exec("DELETE FROM secret_usage")
+		tx.Exec("DELETE FROM artifact_chunks")
+		tx.Exec("DELETE FROM artifacts")
+		tx.Exec("DELETE FROM tests")
+		tx.Exec("DELETE FROM retries")
 		tx.Exec("DELETE FROM steps")
 		tx.Exec("DELETE FROM stages")
 		tx.Exec("DELETE FROM latest")
@@ -57,6 +62,14 @@ func Reset(d *db.DB) {
 		tx.Exec("DELETE FROM repos")
 		tx.Exec("DELETE FROM users")
 		tx.Exec("DELETE FROM orgsecrets")
+		tx.Exec("DELETE FROM quotas")
+		tx.Exec("DELETE FROM retention")
+		tx.Exec("DELETE FROM deliveries")
+		tx.Exec("DELETE FROM webhooks")
+		tx.Exec("DELETE FROM secret_versions")
+		tx.Exec("DELETE FROM audit_log")
+		tx.Exec("DELETE FROM tokens")
+		tx.Exec("DELETE FROM environments")
 		return nil
 	})
 }
@@ -35,6 +35,11 @@ func New(key string) (Encrypter, error) {
 	if key == "" {
 		return &none{}, nil
 	}
+	return newAesgcm(key)
+}
+
+// helper function returns an AES-GCM encrypter for the key.
+func newAesgcm(key string) (*Aesgcm, error) {
 	if len(key) != 32 {
 		return nil, errKeySize
 	}
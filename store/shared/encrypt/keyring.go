@@ -0,0 +1,150 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+)
+
+// keyPrefix is prepended to ciphertext encrypted by the keyring,
+// followed by the key identifier and a separator. Ciphertext
+// without the prefix was encrypted with the legacy single key.
+const keyPrefix = "drone:"
+
+var (
+	errKeyID         = errors.New("encryption key identifier is invalid")
+	errKeyActive     = errors.New("active encryption key is not in the keyring")
+	errKeyNotFound   = errors.New("encryption key not found in the keyring")
+	errKeyringLegacy = errors.New("ciphertext requires the legacy encryption key")
+)
+
+// keyID regular expression
+var keyID = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// Keyring encrypts data with the active key and decrypts data
+// with the key used to encrypt it. Keys can be added to the
+// keyring and activated without losing access to ciphertext
+// encrypted with previous keys.
+type Keyring struct {
+	active string
+	keys   map[string]*Aesgcm
+	legacy *Aesgcm
+
+	// Compat returns the ciphertext as plain text if the
+	// ciphertext was not encrypted by the keyring and cannot
+	// be decrypted with the legacy key. This should be used
+	// when the database has a mix of encrypted and unencrypted
+	// content.
+	Compat bool
+}
+
+// NewKeyring returns a new Keyring that encrypts data with the
+// active key. The optional legacy key decrypts ciphertext that
+// was encrypted before the keyring was configured.
+func NewKeyring(active string, keys map[string]string, legacy string) (*Keyring, error) {
+	keyring := &Keyring{
+		active: active,
+		keys:   map[string]*Aesgcm{},
+	}
+	for id, key := range keys {
+		if !keyID.MatchString(id) {
+			return nil, errKeyID
+		}
+		aesgcm, err := newAesgcm(key)
+		if err != nil {
+			return nil, err
+		}
+		keyring.keys[id] = aesgcm
+	}
+	if _, ok := keyring.keys[active]; !ok {
+		return nil, errKeyActive
+	}
+	if legacy != "" {
+		aesgcm, err := newAesgcm(legacy)
+		if err != nil {
+			return nil, err
+		}
+		keyring.legacy = aesgcm
+	}
+	return keyring, nil
+}
+
+// Active returns the identifier of the active key.
+func (k *Keyring) Active() string {
+	return k.active
+}
+
+// Encrypt encrypts the plaintext with the active key.
+func (k *Keyring) Encrypt(plaintext string) ([]byte, error) {
+	ciphertext, err := k.keys[k.active].Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(k.header(k.active), ciphertext...), nil
+}
+
+// Decrypt decrypts the ciphertext with the key used to
+// encrypt it.
+func (k *Keyring) Decrypt(ciphertext []byte) (string, error) {
+	if id, rest, ok := k.split(ciphertext); ok {
+		aesgcm, ok := k.keys[id]
+		if !ok {
+			return "", errKeyNotFound
+		}
+		return aesgcm.Decrypt(rest)
+	}
+	if k.legacy != nil {
+		plaintext, err := k.legacy.Decrypt(ciphertext)
+		if err == nil || !k.Compat {
+			return plaintext, err
+		}
+	}
+	if k.Compat {
+		return string(ciphertext), nil
+	}
+	return "", errKeyringLegacy
+}
+
+// IsActive returns true if the ciphertext was encrypted with
+// the active key.
+func (k *Keyring) IsActive(ciphertext []byte) bool {
+	return bytes.HasPrefix(ciphertext, k.header(k.active))
+}
+
+// helper function returns the ciphertext header for the key.
+func (k *Keyring) header(id string) []byte {
+	return []byte(keyPrefix + id + ":")
+}
+
+// helper function splits the ciphertext into the key identifier
+// and the encrypted data. It returns false if the ciphertext
+// was not encrypted by the keyring.
+func (k *Keyring) split(ciphertext []byte) (string, []byte, bool) {
+	if !bytes.HasPrefix(ciphertext, []byte(keyPrefix)) {
+		return "", nil, false
+	}
+	rest := ciphertext[len(keyPrefix):]
+	i := bytes.IndexByte(rest, ':')
+	if i < 1 {
+		return "", nil, false
+	}
+	id := string(rest[:i])
+	if !keyID.MatchString(id) {
+		return "", nil, false
+	}
+	return id, rest[i+1:], true
+}
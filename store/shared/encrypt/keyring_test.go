@@ -0,0 +1,105 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import "testing"
+
+var testKeys = map[string]string{
+	"2019": "fb4b4d6267c8a5ce8231f8b186dbca92",
+	"2020": "ea1c5a9145c8a5ce8231f8b186dbcabc",
+}
+
+func TestKeyring(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	old, _ := NewKeyring("2019", testKeys, "")
+	ciphertext, err := old.Encrypt(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !old.IsActive(ciphertext) {
+		t.Errorf("Expect ciphertext encrypted with the active key")
+	}
+
+	// rotate to the new key and verify ciphertext encrypted
+	// with the previous key can still be decrypted.
+	k, _ := NewKeyring("2020", testKeys, "")
+	if k.IsActive(ciphertext) {
+		t.Errorf("Expect ciphertext encrypted with an inactive key")
+	}
+	plaintext, err := k.Decrypt(ciphertext)
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+}
+
+func TestKeyringKeyNotFound(t *testing.T) {
+	old, _ := NewKeyring("2019", testKeys, "")
+	ciphertext, _ := old.Encrypt("correct-horse-batter-staple")
+
+	k, _ := NewKeyring("2020", map[string]string{"2020": testKeys["2020"]}, "")
+	_, err := k.Decrypt(ciphertext)
+	if err != errKeyNotFound {
+		t.Errorf("Want errKeyNotFound, got %v", err)
+	}
+}
+
+func TestKeyringLegacy(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	legacy, _ := New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	ciphertext, _ := legacy.Encrypt(s)
+
+	k, _ := NewKeyring("2020", testKeys, "fb4b4d6267c8a5ce8231f8b186dbca92")
+	plaintext, err := k.Decrypt(ciphertext)
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+
+	k, _ = NewKeyring("2020", testKeys, "")
+	_, err = k.Decrypt(ciphertext)
+	if err != errKeyringLegacy {
+		t.Errorf("Want errKeyringLegacy, got %v", err)
+	}
+}
+
+func TestKeyringCompat(t *testing.T) {
+	k, _ := NewKeyring("2020", testKeys, "fb4b4d6267c8a5ce8231f8b186dbca92")
+	k.Compat = true
+	plaintext, err := k.Decrypt([]byte("correct-horse-batter-staple"))
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, "correct-horse-batter-staple"; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+}
+
+func TestKeyringInvalid(t *testing.T) {
+	if _, err := NewKeyring("2021", testKeys, ""); err != errKeyActive {
+		t.Errorf("Want errKeyActive, got %v", err)
+	}
+	if _, err := NewKeyring("a:b", map[string]string{"a:b": testKeys["2020"]}, ""); err != errKeyID {
+		t.Errorf("Want errKeyID, got %v", err)
+	}
+	if _, err := NewKeyring("2020", map[string]string{"2020": "short"}, ""); err != errKeySize {
+		t.Errorf("Want errKeySize, got %v", err)
+	}
+}
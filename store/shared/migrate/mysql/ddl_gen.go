@@ -176,6 +176,230 @@ var migrations = []struct {
 		name: "alter-table-steps-add-column-step-detached",
 		stmt: alterTableStepsAddColumnStepDetached,
 	},
+	{
+		name: "alter-table-stages-add-column-expires",
+		stmt: alterTableStagesAddColumnExpires,
+	},
+	{
+		name: "alter-table-stages-add-column-priority",
+		stmt: alterTableStagesAddColumnPriority,
+	},
+	{
+		name: "alter-table-repos-add-column-priority",
+		stmt: alterTableReposAddColumnPriority,
+	},
+	{
+		name: "create-table-quotas",
+		stmt: createTableQuotas,
+	},
+	{
+		name: "alter-table-stages-add-column-reason",
+		stmt: alterTableStagesAddColumnReason,
+	},
+	{
+		name: "create-table-retention",
+		stmt: createTableRetention,
+	},
+	{
+		name: "create-table-deliveries",
+		stmt: createTableDeliveries,
+	},
+	{
+		name: "create-index-deliveries-status",
+		stmt: createIndexDeliveriesStatus,
+	},
+	{
+		name: "create-table-webhooks",
+		stmt: createTableWebhooks,
+	},
+	{
+		name: "create-index-webhooks-repo",
+		stmt: createIndexWebhooksRepo,
+	},
+	{
+		name: "alter-table-deliveries-add-column-webhook-id",
+		stmt: alterTableDeliveriesAddColumnWebhookId,
+	},
+	{
+		name: "create-index-deliveries-webhook",
+		stmt: createIndexDeliveriesWebhook,
+	},
+	{
+		name: "alter-table-secrets-add-column-version",
+		stmt: alterTableSecretsAddColumnVersion,
+	},
+	{
+		name: "alter-table-orgsecrets-add-column-version",
+		stmt: alterTableOrgsecretsAddColumnVersion,
+	},
+	{
+		name: "create-table-secret-versions",
+		stmt: createTableSecretVersions,
+	},
+	{
+		name: "populate-secret-versions",
+		stmt: populateSecretVersions,
+	},
+	{
+		name: "populate-orgsecret-versions",
+		stmt: populateOrgsecretVersions,
+	},
+	{
+		name: "update-secrets-version",
+		stmt: updateSecretsVersion,
+	},
+	{
+		name: "update-orgsecrets-version",
+		stmt: updateOrgsecretsVersion,
+	},
+	{
+		name: "create-table-secret-usage",
+		stmt: createTableSecretUsage,
+	},
+	{
+		name: "create-index-secret-usage-build",
+		stmt: createIndexSecretUsageBuild,
+	},
+	{
+		name: "create-index-secret-usage-version",
+		stmt: createIndexSecretUsageVersion,
+	},
+	{
+		name: "alter-table-secrets-add-column-branches",
+		stmt: alterTableSecretsAddColumnBranches,
+	},
+	{
+		name: "alter-table-secrets-add-column-refs",
+		stmt: alterTableSecretsAddColumnRefs,
+	},
+	{
+		name: "alter-table-secrets-add-column-events",
+		stmt: alterTableSecretsAddColumnEvents,
+	},
+	{
+		name: "alter-table-secrets-add-column-images",
+		stmt: alterTableSecretsAddColumnImages,
+	},
+	{
+		name: "alter-table-orgsecrets-add-column-branches",
+		stmt: alterTableOrgsecretsAddColumnBranches,
+	},
+	{
+		name: "alter-table-orgsecrets-add-column-refs",
+		stmt: alterTableOrgsecretsAddColumnRefs,
+	},
+	{
+		name: "alter-table-orgsecrets-add-column-events",
+		stmt: alterTableOrgsecretsAddColumnEvents,
+	},
+	{
+		name: "alter-table-orgsecrets-add-column-images",
+		stmt: alterTableOrgsecretsAddColumnImages,
+	},
+	{
+		name: "create-table-audit-log",
+		stmt: createTableAuditLog,
+	},
+	{
+		name: "create-index-audit-log-created",
+		stmt: createIndexAuditLogCreated,
+	},
+	{
+		name: "create-index-audit-log-actor",
+		stmt: createIndexAuditLogActor,
+	},
+	{
+		name: "create-index-audit-log-target",
+		stmt: createIndexAuditLogTarget,
+	},
+	{
+		name: "create-table-tokens",
+		stmt: createTableTokens,
+	},
+	{
+		name: "create-index-tokens-user",
+		stmt: createIndexTokensUser,
+	},
+	{
+		name: "create-table-environments",
+		stmt: createTableEnvironments,
+	},
+	{
+		name: "create-index-environments-repo",
+		stmt: createIndexEnvironmentsRepo,
+	},
+	{
+		name: "create-index-builds-deploy",
+		stmt: createIndexBuildsDeploy,
+	},
+	{
+		name: "alter-table-stages-add-column-attempt",
+		stmt: alterTableStagesAddColumnAttempt,
+	},
+	{
+		name: "alter-table-stages-add-column-retry",
+		stmt: alterTableStagesAddColumnRetry,
+	},
+	{
+		name: "alter-table-steps-add-column-attempt",
+		stmt: alterTableStepsAddColumnAttempt,
+	},
+	{
+		name: "create-table-retries",
+		stmt: createTableRetries,
+	},
+	{
+		name: "create-index-retries-repo",
+		stmt: createIndexRetriesRepo,
+	},
+	{
+		name: "create-index-retries-stage",
+		stmt: createIndexRetriesStage,
+	},
+	{
+		name: "alter-table-builds-add-column-timeout",
+		stmt: alterTableBuildsAddColumnTimeout,
+	},
+	{
+		name: "alter-table-stages-add-column-timeout",
+		stmt: alterTableStagesAddColumnTimeout,
+	},
+	{
+		name: "create-table-artifacts",
+		stmt: createTableArtifacts,
+	},
+	{
+		name: "create-index-artifacts-build",
+		stmt: createIndexArtifactsBuild,
+	},
+	{
+		name: "create-table-artifact-blobs",
+		stmt: createTableArtifactBlobs,
+	},
+	{
+		name: "create-table-tests",
+		stmt: createTableTests,
+	},
+	{
+		name: "create-index-tests-build",
+		stmt: createIndexTestsBuild,
+	},
+	{
+		name: "create-index-tests-step",
+		stmt: createIndexTestsStep,
+	},
+	{
+		name: "create-index-tests-name",
+		stmt: createIndexTestsName,
+	},
+	{
+		name: "create-table-artifact-chunks",
+		stmt: createTableArtifactChunks,
+	},
+	{
+		name: "drop-table-artifact-blobs",
+		stmt: dropTableArtifactBlobs,
+	},
 }
 
 // Migrate performs the database migration. If the migration fails
@@ -714,3 +938,455 @@ ALTER TABLE steps ADD COLUMN step_image VARCHAR(1000) NOT NULL DEFAULT '';
 var alterTableStepsAddColumnStepDetached = `
 ALTER TABLE steps ADD COLUMN step_detached BOOLEAN NOT NULL DEFAULT FALSE;
 `
+
+//
+// 017_add_column_stages_expires.sql
+//
+
+var alterTableStagesAddColumnExpires = `
+ALTER TABLE stages ADD COLUMN stage_expires INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 018_add_columns_priority.sql
+//
+
+var alterTableStagesAddColumnPriority = `
+ALTER TABLE stages ADD COLUMN stage_priority INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableReposAddColumnPriority = `
+ALTER TABLE repos ADD COLUMN repo_priority INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 019_create_table_quotas.sql
+//
+
+var createTableQuotas = `
+CREATE TABLE IF NOT EXISTS quotas (
+ quota_id      INTEGER PRIMARY KEY AUTO_INCREMENT
+,quota_kind    VARCHAR(50)
+,quota_name    VARCHAR(250)
+,quota_limit   INTEGER
+,quota_created INTEGER
+,quota_updated INTEGER
+,UNIQUE(quota_kind, quota_name)
+);
+`
+
+var alterTableStagesAddColumnReason = `
+ALTER TABLE stages ADD COLUMN stage_reason VARCHAR(500) NOT NULL DEFAULT '';
+`
+
+//
+// 020_create_table_retention.sql
+//
+
+var createTableRetention = `
+CREATE TABLE IF NOT EXISTS retention (
+ retention_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,retention_kind     VARCHAR(50)
+,retention_name     VARCHAR(250)
+,retention_builds   INTEGER
+,retention_days     INTEGER
+,retention_log_days INTEGER
+,retention_created  INTEGER
+,retention_updated  INTEGER
+,UNIQUE(retention_kind, retention_name)
+);
+`
+
+//
+// 021_create_table_deliveries.sql
+//
+
+var createTableDeliveries = `
+CREATE TABLE IF NOT EXISTS deliveries (
+ delivery_id         INTEGER PRIMARY KEY AUTO_INCREMENT
+,delivery_endpoint   VARCHAR(2000)
+,delivery_event      VARCHAR(50)
+,delivery_action     VARCHAR(50)
+,delivery_status     VARCHAR(50)
+,delivery_attempts   INTEGER
+,delivery_code       INTEGER
+,delivery_error      VARCHAR(500)
+,delivery_latency    INTEGER
+,delivery_payload    MEDIUMTEXT
+,delivery_next_retry INTEGER
+,delivery_created    INTEGER
+,delivery_updated    INTEGER
+);
+`
+
+var createIndexDeliveriesStatus = `
+CREATE INDEX ix_delivery_status ON deliveries (delivery_status, delivery_next_retry);
+`
+
+//
+// 022_create_table_webhooks.sql
+//
+
+var createTableWebhooks = `
+CREATE TABLE IF NOT EXISTS webhooks (
+ webhook_id          INTEGER PRIMARY KEY AUTO_INCREMENT
+,webhook_repo_id     INTEGER
+,webhook_endpoint    VARCHAR(2000)
+,webhook_signer      BLOB
+,webhook_skip_verify BOOLEAN
+,webhook_events      VARCHAR(1000)
+,webhook_created     INTEGER
+,webhook_updated     INTEGER
+,FOREIGN KEY(webhook_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexWebhooksRepo = `
+CREATE INDEX ix_webhook_repo ON webhooks (webhook_repo_id);
+`
+
+var alterTableDeliveriesAddColumnWebhookId = `
+ALTER TABLE deliveries ADD COLUMN delivery_webhook_id INTEGER NOT NULL DEFAULT 0;
+`
+
+var createIndexDeliveriesWebhook = `
+CREATE INDEX ix_delivery_webhook ON deliveries (delivery_webhook_id);
+`
+
+//
+// 023_create_table_secret_versions.sql
+//
+
+var alterTableSecretsAddColumnVersion = `
+ALTER TABLE secrets ADD COLUMN secret_version INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableOrgsecretsAddColumnVersion = `
+ALTER TABLE orgsecrets ADD COLUMN secret_version INTEGER NOT NULL DEFAULT 0;
+`
+
+var createTableSecretVersions = `
+CREATE TABLE IF NOT EXISTS secret_versions (
+ version_id         INTEGER PRIMARY KEY AUTO_INCREMENT
+,version_kind       VARCHAR(50)
+,version_secret_id  INTEGER
+,version_number     INTEGER
+,version_data       BLOB
+,version_created_by VARCHAR(250)
+,version_created    INTEGER
+,UNIQUE(version_kind, version_secret_id, version_number)
+);
+`
+
+var populateSecretVersions = `
+INSERT INTO secret_versions (
+ version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+)
+SELECT 'repository', secret_id, 1, secret_data, '', UNIX_TIMESTAMP()
+FROM secrets;
+`
+
+var populateOrgsecretVersions = `
+INSERT INTO secret_versions (
+ version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+)
+SELECT 'organization', secret_id, 1, secret_data, '', UNIX_TIMESTAMP()
+FROM orgsecrets;
+`
+
+var updateSecretsVersion = `
+UPDATE secrets SET secret_version = 1;
+`
+
+var updateOrgsecretsVersion = `
+UPDATE orgsecrets SET secret_version = 1;
+`
+
+var createTableSecretUsage = `
+CREATE TABLE IF NOT EXISTS secret_usage (
+ usage_id           INTEGER PRIMARY KEY AUTO_INCREMENT
+,usage_repo_id      INTEGER
+,usage_build_id     INTEGER
+,usage_build_number INTEGER
+,usage_stage_id     INTEGER
+,usage_kind         VARCHAR(50)
+,usage_secret_id    INTEGER
+,usage_name         VARCHAR(500)
+,usage_version      INTEGER
+,usage_created      INTEGER
+,FOREIGN KEY(usage_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+`
+
+var createIndexSecretUsageBuild = `
+CREATE INDEX ix_secret_usage_build ON secret_usage (usage_build_id);
+`
+
+var createIndexSecretUsageVersion = `
+CREATE INDEX ix_secret_usage_version ON secret_usage (usage_kind, usage_secret_id, usage_version);
+`
+
+//
+// 024_add_columns_secret_restrictions.sql
+//
+
+var alterTableSecretsAddColumnBranches = `
+ALTER TABLE secrets ADD COLUMN secret_branches VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableSecretsAddColumnRefs = `
+ALTER TABLE secrets ADD COLUMN secret_refs VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableSecretsAddColumnEvents = `
+ALTER TABLE secrets ADD COLUMN secret_events VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableSecretsAddColumnImages = `
+ALTER TABLE secrets ADD COLUMN secret_images VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableOrgsecretsAddColumnBranches = `
+ALTER TABLE orgsecrets ADD COLUMN secret_branches VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableOrgsecretsAddColumnRefs = `
+ALTER TABLE orgsecrets ADD COLUMN secret_refs VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableOrgsecretsAddColumnEvents = `
+ALTER TABLE orgsecrets ADD COLUMN secret_events VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableOrgsecretsAddColumnImages = `
+ALTER TABLE orgsecrets ADD COLUMN secret_images VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+//
+// 025_create_table_audit_log.sql
+//
+
+var createTableAuditLog = `
+CREATE TABLE IF NOT EXISTS audit_log (
+ audit_id      INTEGER PRIMARY KEY AUTO_INCREMENT
+,audit_actor   VARCHAR(250)
+,audit_action  VARCHAR(50)
+,audit_target  VARCHAR(500)
+,audit_diff    TEXT
+,audit_ip      VARCHAR(100)
+,audit_created INTEGER
+);
+`
+
+var createIndexAuditLogCreated = `
+CREATE INDEX ix_audit_created ON audit_log (audit_created);
+`
+
+var createIndexAuditLogActor = `
+CREATE INDEX ix_audit_actor ON audit_log (audit_actor);
+`
+
+var createIndexAuditLogTarget = `
+CREATE INDEX ix_audit_target ON audit_log (audit_target);
+`
+
+//
+// 026_create_table_tokens.sql
+//
+
+var createTableTokens = `
+CREATE TABLE IF NOT EXISTS tokens (
+ token_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,token_user_id   INTEGER
+,token_name      VARCHAR(250)
+,token_scopes    VARCHAR(500)
+,token_repo      VARCHAR(500)
+,token_hash      VARCHAR(64)
+,token_expires   INTEGER
+,token_last_used INTEGER
+,token_created   INTEGER
+,UNIQUE(token_hash)
+,UNIQUE(token_user_id, token_name)
+);
+`
+
+var createIndexTokensUser = `
+CREATE INDEX ix_tokens_user ON tokens (token_user_id);
+`
+
+//
+// 027_create_table_environments.sql
+//
+
+var createTableEnvironments = `
+CREATE TABLE IF NOT EXISTS environments (
+ env_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,env_repo_id   INTEGER
+,env_name      VARCHAR(250)
+,env_branches  VARCHAR(2000)
+,env_approvers VARCHAR(2000)
+,env_wait      INTEGER
+,env_created   INTEGER
+,env_updated   INTEGER
+,UNIQUE(env_repo_id, env_name)
+,FOREIGN KEY(env_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexEnvironmentsRepo = `
+CREATE INDEX ix_environments_repo ON environments (env_repo_id);
+`
+
+//
+// 028_create_index_builds_deploy.sql
+//
+
+var createIndexBuildsDeploy = `
+CREATE INDEX ix_build_deploy ON builds (build_repo_id, build_deploy);
+`
+
+//
+// 029_create_table_retries.sql
+//
+
+var alterTableStagesAddColumnAttempt = `
+ALTER TABLE stages ADD COLUMN stage_attempt INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableStagesAddColumnRetry = `
+ALTER TABLE stages ADD COLUMN stage_retry VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+var alterTableStepsAddColumnAttempt = `
+ALTER TABLE steps ADD COLUMN step_attempt INTEGER NOT NULL DEFAULT 0;
+`
+
+var createTableRetries = `
+CREATE TABLE IF NOT EXISTS retries (
+ retry_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,retry_repo_id   INTEGER
+,retry_build_id  INTEGER
+,retry_stage_id  INTEGER
+,retry_stage     VARCHAR(250)
+,retry_step      VARCHAR(250)
+,retry_attempt   INTEGER
+,retry_exit_code INTEGER
+,retry_error     VARCHAR(500)
+,retry_passed    BOOLEAN
+,retry_created   INTEGER
+,FOREIGN KEY(retry_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+`
+
+var createIndexRetriesRepo = `
+CREATE INDEX ix_retries_repo ON retries (retry_repo_id);
+`
+
+var createIndexRetriesStage = `
+CREATE INDEX ix_retries_stage ON retries (retry_stage_id);
+`
+
+//
+// 030_add_columns_timeouts.sql
+//
+
+var alterTableBuildsAddColumnTimeout = `
+ALTER TABLE builds ADD COLUMN build_timeout INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableStagesAddColumnTimeout = `
+ALTER TABLE stages ADD COLUMN stage_timeout VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+//
+// 031_create_table_artifacts.sql
+//
+
+var createTableArtifacts = `
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,artifact_repo_id  INTEGER
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     VARCHAR(500)
+,artifact_size     BIGINT
+,artifact_created  INTEGER
+,artifact_updated  INTEGER
+,UNIQUE(artifact_stage_id, artifact_name)
+,FOREIGN KEY(artifact_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+`
+
+var createIndexArtifactsBuild = `
+CREATE INDEX ix_artifacts_build ON artifacts (artifact_build_id);
+`
+
+var createTableArtifactBlobs = `
+CREATE TABLE IF NOT EXISTS artifact_blobs (
+ blob_id    INTEGER PRIMARY KEY
+,blob_data  LONGBLOB
+,FOREIGN KEY(blob_id) REFERENCES artifacts(artifact_id) ON DELETE CASCADE
+);
+`
+
+//
+// 032_create_table_tests.sql
+//
+
+var createTableTests = `
+CREATE TABLE IF NOT EXISTS tests (
+ test_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,test_repo_id   INTEGER
+,test_build_id  INTEGER
+,test_stage_id  INTEGER
+,test_step_id   INTEGER
+,test_suite     VARCHAR(500)
+,test_name      VARCHAR(500)
+,test_status    VARCHAR(50)
+,test_duration  INTEGER
+,test_message   TEXT
+,test_created   INTEGER
+,FOREIGN KEY(test_step_id) REFERENCES steps(step_id) ON DELETE CASCADE
+);
+`
+
+var createIndexTestsBuild = `
+CREATE INDEX ix_tests_build ON tests (test_build_id);
+`
+
+var createIndexTestsStep = `
+CREATE INDEX ix_tests_step ON tests (test_step_id);
+`
+
+var createIndexTestsName = `
+CREATE INDEX ix_tests_name ON tests (test_repo_id, test_name);
+`
+
+//
+// 033_create_table_artifact_chunks.sql
+//
+
+var createTableArtifactChunks = `
+CREATE TABLE IF NOT EXISTS artifact_chunks (
+ chunk_artifact_id INTEGER
+,chunk_number      INTEGER
+,chunk_data        MEDIUMBLOB
+,PRIMARY KEY(chunk_artifact_id, chunk_number)
+,FOREIGN KEY(chunk_artifact_id) REFERENCES artifacts(artifact_id) ON DELETE CASCADE
+);
+`
+
+var dropTableArtifactBlobs = `
+DROP TABLE IF EXISTS artifact_blobs;
+`
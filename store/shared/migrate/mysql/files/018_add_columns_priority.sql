@@ -0,0 +1,7 @@
+-- name: alter-table-stages-add-column-priority
+
+ALTER TABLE stages ADD COLUMN stage_priority INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-repos-add-column-priority
+
+ALTER TABLE repos ADD COLUMN repo_priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+-- name: create-table-quotas
+
+CREATE TABLE IF NOT EXISTS quotas (
+ quota_id      INTEGER PRIMARY KEY AUTO_INCREMENT
+,quota_kind    VARCHAR(50)
+,quota_name    VARCHAR(250)
+,quota_limit   INTEGER
+,quota_created INTEGER
+,quota_updated INTEGER
+,UNIQUE(quota_kind, quota_name)
+);
+
+-- name: alter-table-stages-add-column-reason
+
+ALTER TABLE stages ADD COLUMN stage_reason VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,36 @@
+-- name: alter-table-stages-add-column-attempt
+
+ALTER TABLE stages ADD COLUMN stage_attempt INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-stages-add-column-retry
+
+ALTER TABLE stages ADD COLUMN stage_retry VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-steps-add-column-attempt
+
+ALTER TABLE steps ADD COLUMN step_attempt INTEGER NOT NULL DEFAULT 0;
+
+-- name: create-table-retries
+
+CREATE TABLE IF NOT EXISTS retries (
+ retry_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,retry_repo_id   INTEGER
+,retry_build_id  INTEGER
+,retry_stage_id  INTEGER
+,retry_stage     VARCHAR(250)
+,retry_step      VARCHAR(250)
+,retry_attempt   INTEGER
+,retry_exit_code INTEGER
+,retry_error     VARCHAR(500)
+,retry_passed    BOOLEAN
+,retry_created   INTEGER
+,FOREIGN KEY(retry_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-retries-repo
+
+CREATE INDEX ix_retries_repo ON retries (retry_repo_id);
+
+-- name: create-index-retries-stage
+
+CREATE INDEX ix_retries_stage ON retries (retry_stage_id);
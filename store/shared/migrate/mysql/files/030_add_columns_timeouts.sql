@@ -0,0 +1,7 @@
+-- name: alter-table-builds-add-column-timeout
+
+ALTER TABLE builds ADD COLUMN build_timeout INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-stages-add-column-timeout
+
+ALTER TABLE stages ADD COLUMN stage_timeout VARCHAR(2000) NOT NULL DEFAULT '';
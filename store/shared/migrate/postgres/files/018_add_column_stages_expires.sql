@@ -0,0 +1,3 @@
+-- name: alter-table-stages-add-column-expires
+
+ALTER TABLE stages ADD COLUMN stage_expires INTEGER NOT NULL DEFAULT 0;
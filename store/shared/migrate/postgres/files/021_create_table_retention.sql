@@ -0,0 +1,13 @@
+-- name: create-table-retention
+
+CREATE TABLE IF NOT EXISTS retention (
+ retention_id       SERIAL PRIMARY KEY
+,retention_kind     VARCHAR(50)
+,retention_name     VARCHAR(250)
+,retention_builds   INTEGER
+,retention_days     INTEGER
+,retention_log_days INTEGER
+,retention_created  INTEGER
+,retention_updated  INTEGER
+,UNIQUE(retention_kind, retention_name)
+);
@@ -0,0 +1,21 @@
+-- name: create-table-deliveries
+
+CREATE TABLE IF NOT EXISTS deliveries (
+ delivery_id         SERIAL PRIMARY KEY
+,delivery_endpoint   VARCHAR(2000)
+,delivery_event      VARCHAR(50)
+,delivery_action     VARCHAR(50)
+,delivery_status     VARCHAR(50)
+,delivery_attempts   INTEGER
+,delivery_code       INTEGER
+,delivery_error      VARCHAR(500)
+,delivery_latency    INTEGER
+,delivery_payload    TEXT
+,delivery_next_retry INTEGER
+,delivery_created    INTEGER
+,delivery_updated    INTEGER
+);
+
+-- name: create-index-deliveries-status
+
+CREATE INDEX IF NOT EXISTS ix_delivery_status ON deliveries (delivery_status, delivery_next_retry);
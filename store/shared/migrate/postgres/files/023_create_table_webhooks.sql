@@ -0,0 +1,25 @@
+-- name: create-table-webhooks
+
+CREATE TABLE IF NOT EXISTS webhooks (
+ webhook_id          SERIAL PRIMARY KEY
+,webhook_repo_id     INTEGER
+,webhook_endpoint    VARCHAR(2000)
+,webhook_signer      BYTEA
+,webhook_skip_verify BOOLEAN
+,webhook_events      VARCHAR(1000)
+,webhook_created     INTEGER
+,webhook_updated     INTEGER
+,FOREIGN KEY(webhook_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-webhooks-repo
+
+CREATE INDEX IF NOT EXISTS ix_webhook_repo ON webhooks (webhook_repo_id);
+
+-- name: alter-table-deliveries-add-column-webhook-id
+
+ALTER TABLE deliveries ADD COLUMN delivery_webhook_id INTEGER NOT NULL DEFAULT 0;
+
+-- name: create-index-deliveries-webhook
+
+CREATE INDEX IF NOT EXISTS ix_delivery_webhook ON deliveries (delivery_webhook_id);
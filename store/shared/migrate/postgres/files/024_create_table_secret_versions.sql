@@ -0,0 +1,78 @@
+-- name: alter-table-secrets-add-column-version
+
+ALTER TABLE secrets ADD COLUMN secret_version INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-orgsecrets-add-column-version
+
+ALTER TABLE orgsecrets ADD COLUMN secret_version INTEGER NOT NULL DEFAULT 0;
+
+-- name: create-table-secret-versions
+
+CREATE TABLE IF NOT EXISTS secret_versions (
+ version_id         SERIAL PRIMARY KEY
+,version_kind       VARCHAR(50)
+,version_secret_id  INTEGER
+,version_number     INTEGER
+,version_data       BYTEA
+,version_created_by VARCHAR(250)
+,version_created    INTEGER
+,UNIQUE(version_kind, version_secret_id, version_number)
+);
+
+-- name: populate-secret-versions
+
+INSERT INTO secret_versions (
+ version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+)
+SELECT 'repository', secret_id, 1, secret_data, '', CAST(EXTRACT(EPOCH FROM NOW()) AS INTEGER)
+FROM secrets;
+
+-- name: populate-orgsecret-versions
+
+INSERT INTO secret_versions (
+ version_kind
+,version_secret_id
+,version_number
+,version_data
+,version_created_by
+,version_created
+)
+SELECT 'organization', secret_id, 1, secret_data, '', CAST(EXTRACT(EPOCH FROM NOW()) AS INTEGER)
+FROM orgsecrets;
+
+-- name: update-secrets-version
+
+UPDATE secrets SET secret_version = 1;
+
+-- name: update-orgsecrets-version
+
+UPDATE orgsecrets SET secret_version = 1;
+
+-- name: create-table-secret-usage
+
+CREATE TABLE IF NOT EXISTS secret_usage (
+ usage_id           SERIAL PRIMARY KEY
+,usage_repo_id      INTEGER
+,usage_build_id     INTEGER
+,usage_build_number INTEGER
+,usage_stage_id     INTEGER
+,usage_kind         VARCHAR(50)
+,usage_secret_id    INTEGER
+,usage_name         VARCHAR(500)
+,usage_version      INTEGER
+,usage_created      INTEGER
+,FOREIGN KEY(usage_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+
+-- name: create-index-secret-usage-build
+
+CREATE INDEX IF NOT EXISTS ix_secret_usage_build ON secret_usage (usage_build_id);
+
+-- name: create-index-secret-usage-version
+
+CREATE INDEX IF NOT EXISTS ix_secret_usage_version ON secret_usage (usage_kind, usage_secret_id, usage_version);
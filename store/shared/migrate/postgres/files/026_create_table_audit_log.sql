@@ -0,0 +1,23 @@
+-- name: create-table-audit-log
+
+CREATE TABLE IF NOT EXISTS audit_log (
+ audit_id      SERIAL PRIMARY KEY
+,audit_actor   VARCHAR(250)
+,audit_action  VARCHAR(50)
+,audit_target  VARCHAR(500)
+,audit_diff    TEXT
+,audit_ip      VARCHAR(100)
+,audit_created INTEGER
+);
+
+-- name: create-index-audit-log-created
+
+CREATE INDEX IF NOT EXISTS ix_audit_created ON audit_log (audit_created);
+
+-- name: create-index-audit-log-actor
+
+CREATE INDEX IF NOT EXISTS ix_audit_actor ON audit_log (audit_actor);
+
+-- name: create-index-audit-log-target
+
+CREATE INDEX IF NOT EXISTS ix_audit_target ON audit_log (audit_target);
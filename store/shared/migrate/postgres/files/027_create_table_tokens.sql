@@ -0,0 +1,19 @@
+-- name: create-table-tokens
+
+CREATE TABLE IF NOT EXISTS tokens (
+ token_id        SERIAL PRIMARY KEY
+,token_user_id   INTEGER
+,token_name      VARCHAR(250)
+,token_scopes    VARCHAR(500)
+,token_repo      VARCHAR(500)
+,token_hash      VARCHAR(64)
+,token_expires   INTEGER
+,token_last_used INTEGER
+,token_created   INTEGER
+,UNIQUE(token_hash)
+,UNIQUE(token_user_id, token_name)
+);
+
+-- name: create-index-tokens-user
+
+CREATE INDEX IF NOT EXISTS ix_tokens_user ON tokens (token_user_id);
@@ -0,0 +1,3 @@
+-- name: create-index-builds-deploy
+
+CREATE INDEX IF NOT EXISTS ix_build_deploy ON builds (build_repo_id, build_deploy);
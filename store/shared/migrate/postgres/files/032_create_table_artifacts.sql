@@ -0,0 +1,27 @@
+-- name: create-table-artifacts
+
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       SERIAL PRIMARY KEY
+,artifact_repo_id  INTEGER
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     VARCHAR(500)
+,artifact_size     BIGINT
+,artifact_created  INTEGER
+,artifact_updated  INTEGER
+,UNIQUE(artifact_stage_id, artifact_name)
+,FOREIGN KEY(artifact_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-artifacts-build
+
+CREATE INDEX IF NOT EXISTS ix_artifacts_build ON artifacts (artifact_build_id);
+
+-- name: create-table-artifact-blobs
+
+CREATE TABLE IF NOT EXISTS artifact_blobs (
+ blob_id    INTEGER PRIMARY KEY
+,blob_data  BYTEA
+,FOREIGN KEY(blob_id) REFERENCES artifacts(artifact_id) ON DELETE CASCADE
+);
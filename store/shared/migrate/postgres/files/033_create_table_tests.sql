@@ -0,0 +1,28 @@
+-- name: create-table-tests
+
+CREATE TABLE IF NOT EXISTS tests (
+ test_id        SERIAL PRIMARY KEY
+,test_repo_id   INTEGER
+,test_build_id  INTEGER
+,test_stage_id  INTEGER
+,test_step_id   INTEGER
+,test_suite     VARCHAR(500)
+,test_name      VARCHAR(500)
+,test_status    VARCHAR(50)
+,test_duration  INTEGER
+,test_message   TEXT
+,test_created   INTEGER
+,FOREIGN KEY(test_step_id) REFERENCES steps(step_id) ON DELETE CASCADE
+);
+
+-- name: create-index-tests-build
+
+CREATE INDEX IF NOT EXISTS ix_tests_build ON tests (test_build_id);
+
+-- name: create-index-tests-step
+
+CREATE INDEX IF NOT EXISTS ix_tests_step ON tests (test_step_id);
+
+-- name: create-index-tests-name
+
+CREATE INDEX IF NOT EXISTS ix_tests_name ON tests (test_repo_id, test_name);
@@ -0,0 +1,13 @@
+-- name: create-table-artifact-chunks
+
+CREATE TABLE IF NOT EXISTS artifact_chunks (
+ chunk_artifact_id INTEGER
+,chunk_number      INTEGER
+,chunk_data        BYTEA
+,PRIMARY KEY(chunk_artifact_id, chunk_number)
+,FOREIGN KEY(chunk_artifact_id) REFERENCES artifacts(artifact_id) ON DELETE CASCADE
+);
+
+-- name: drop-table-artifact-blobs
+
+DROP TABLE IF EXISTS artifact_blobs;
@@ -0,0 +1,15 @@
+-- name: create-table-quotas
+
+CREATE TABLE IF NOT EXISTS quotas (
+ quota_id      INTEGER PRIMARY KEY AUTOINCREMENT
+,quota_kind    TEXT
+,quota_name    TEXT
+,quota_limit   INTEGER
+,quota_created INTEGER
+,quota_updated INTEGER
+,UNIQUE(quota_kind, quota_name)
+);
+
+-- name: alter-table-stages-add-column-reason
+
+ALTER TABLE stages ADD COLUMN stage_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+-- name: create-table-retention
+
+CREATE TABLE IF NOT EXISTS retention (
+ retention_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,retention_kind     TEXT
+,retention_name     TEXT
+,retention_builds   INTEGER
+,retention_days     INTEGER
+,retention_log_days INTEGER
+,retention_created  INTEGER
+,retention_updated  INTEGER
+,UNIQUE(retention_kind, retention_name)
+);
@@ -0,0 +1,21 @@
+-- name: create-table-deliveries
+
+CREATE TABLE IF NOT EXISTS deliveries (
+ delivery_id         INTEGER PRIMARY KEY AUTOINCREMENT
+,delivery_endpoint   TEXT
+,delivery_event      TEXT
+,delivery_action     TEXT
+,delivery_status     TEXT
+,delivery_attempts   INTEGER
+,delivery_code       INTEGER
+,delivery_error      TEXT
+,delivery_latency    INTEGER
+,delivery_payload    TEXT
+,delivery_next_retry INTEGER
+,delivery_created    INTEGER
+,delivery_updated    INTEGER
+);
+
+-- name: create-index-deliveries-status
+
+CREATE INDEX IF NOT EXISTS ix_delivery_status ON deliveries (delivery_status, delivery_next_retry);
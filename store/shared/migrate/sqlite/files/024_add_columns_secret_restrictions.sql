@@ -0,0 +1,31 @@
+-- name: alter-table-secrets-add-column-branches
+
+ALTER TABLE secrets ADD COLUMN secret_branches VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-secrets-add-column-refs
+
+ALTER TABLE secrets ADD COLUMN secret_refs VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-secrets-add-column-events
+
+ALTER TABLE secrets ADD COLUMN secret_events VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-secrets-add-column-images
+
+ALTER TABLE secrets ADD COLUMN secret_images VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-orgsecrets-add-column-branches
+
+ALTER TABLE orgsecrets ADD COLUMN secret_branches VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-orgsecrets-add-column-refs
+
+ALTER TABLE orgsecrets ADD COLUMN secret_refs VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-orgsecrets-add-column-events
+
+ALTER TABLE orgsecrets ADD COLUMN secret_events VARCHAR(2000) NOT NULL DEFAULT '';
+
+-- name: alter-table-orgsecrets-add-column-images
+
+ALTER TABLE orgsecrets ADD COLUMN secret_images VARCHAR(2000) NOT NULL DEFAULT '';
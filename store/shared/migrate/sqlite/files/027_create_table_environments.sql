@@ -0,0 +1,18 @@
+-- name: create-table-environments
+
+CREATE TABLE IF NOT EXISTS environments (
+ env_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,env_repo_id   INTEGER
+,env_name      VARCHAR(250)
+,env_branches  VARCHAR(2000)
+,env_approvers VARCHAR(2000)
+,env_wait      INTEGER
+,env_created   INTEGER
+,env_updated   INTEGER
+,UNIQUE(env_repo_id, env_name)
+,FOREIGN KEY(env_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-environments-repo
+
+CREATE INDEX IF NOT EXISTS ix_environments_repo ON environments (env_repo_id);
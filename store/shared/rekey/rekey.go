@@ -0,0 +1,228 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rekey re-encrypts the encrypted database columns with
+// the active key of the encryption keyring.
+package rekey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// batchSize defines the number of rows loaded from the
+// database in each batch.
+const batchSize = 100
+
+// Table defines a database table with encrypted columns.
+type Table struct {
+	Name    string
+	Key     string
+	Columns []string
+}
+
+// Secrets defines the tables that store encrypted secrets.
+var Secrets = []Table{
+	{Name: "secrets", Key: "secret_id", Columns: []string{"secret_data"}},
+	{Name: "orgsecrets", Key: "secret_id", Columns: []string{"secret_data"}},
+	{Name: "secret_versions", Key: "version_id", Columns: []string{"version_data"}},
+	{Name: "webhooks", Key: "webhook_id", Columns: []string{"webhook_signer"}},
+}
+
+// Users defines the user table, which stores encrypted oauth
+// tokens if user table encryption is enabled.
+var Users = Table{
+	Name:    "users",
+	Key:     "user_id",
+	Columns: []string{"user_oauth_token", "user_oauth_refresh"},
+}
+
+// Result provides the results of re-encrypting a table.
+type Result struct {
+	// Rotated is the number of rows re-encrypted.
+	Rotated int
+
+	// Skipped is the number of rows already encrypted
+	// with the active key.
+	Skipped int
+
+	// Failed is the number of rows that could not be
+	// decrypted or updated.
+	Failed int
+}
+
+// Rotate re-encrypts the table columns with the active key.
+// Rows that are already encrypted with the active key are
+// skipped, which means the migration can be stopped and
+// resumed at any time.
+func Rotate(ctx context.Context, conn *db.DB, keyring *encrypt.Keyring, table Table) (*Result, error) {
+	result := new(Result)
+	var last int64
+	for {
+		rows, err := list(conn, table, last)
+		if err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			last = row.id
+			rotate(conn, keyring, table, row, result)
+		}
+	}
+}
+
+type row struct {
+	id     int64
+	values [][]byte
+}
+
+// helper function re-encrypts a single row.
+func rotate(conn *db.DB, keyring *encrypt.Keyring, table Table, row *row, result *Result) {
+	logger := logrus.
+		WithField("table", table.Name).
+		WithField("id", row.id)
+
+	var changed bool
+	var columns []string
+	params := map[string]interface{}{table.Key: row.id}
+	for i, column := range table.Columns {
+		ciphertext := row.values[i]
+		if ciphertext == nil {
+			continue
+		}
+		columns = append(columns, column)
+		params[column] = ciphertext
+		params["prev_"+column] = ciphertext
+		if keyring.IsActive(ciphertext) {
+			continue
+		}
+		plaintext, err := keyring.Decrypt(ciphertext)
+		if err != nil {
+			logger.WithError(err).Warnln("rekey: cannot decrypt row")
+			result.Failed++
+			return
+		}
+		params[column], err = keyring.Encrypt(plaintext)
+		if err != nil {
+			logger.WithError(err).Warnln("rekey: cannot encrypt row")
+			result.Failed++
+			return
+		}
+		changed = true
+	}
+	if !changed {
+		result.Skipped++
+		return
+	}
+	var affected int64
+	err := conn.Lock(func(execer db.Execer, binder db.Binder) error {
+		stmt, args, err := binder.BindNamed(updateStmt(table.Name, table.Key, columns), params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		logger.WithError(err).Warnln("rekey: cannot update row")
+		result.Failed++
+		return
+	}
+	// the row is not updated if it was modified after it was
+	// read, in which case it is left for the next migration.
+	if affected == 0 {
+		logger.Debugln("rekey: row modified, skipping")
+		result.Skipped++
+		return
+	}
+	logger.Debugln("rekey: re-encrypted row")
+	result.Rotated++
+}
+
+// helper function returns the next batch of rows with an
+// identifier greater than the given identifier.
+func list(conn *db.DB, table Table, after int64) ([]*row, error) {
+	var out []*row
+	err := conn.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"id":    after,
+			"limit": batchSize,
+		}
+		stmt, args, err := binder.BindNamed(selectStmt(table), params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			dst := &row{values: make([][]byte, len(table.Columns))}
+			fields := []interface{}{&dst.id}
+			for i := range dst.values {
+				fields = append(fields, &dst.values[i])
+			}
+			if err := rows.Scan(fields...); err != nil {
+				return err
+			}
+			out = append(out, dst)
+		}
+		return rows.Err()
+	})
+	return out, err
+}
+
+// helper function returns the statement to select a batch
+// of encrypted columns from the table.
+func selectStmt(table Table) string {
+	return fmt.Sprintf(`
+SELECT %s, %s
+FROM %s
+WHERE %s > :id
+ORDER BY %s ASC
+LIMIT :limit
+`, table.Key, strings.Join(table.Columns, ", "), table.Name, table.Key, table.Key)
+}
+
+// helper function returns the statement to update the
+// encrypted columns of a single row. The row is only updated
+// if the columns have not been modified since they were read.
+func updateStmt(name, key string, columns []string) string {
+	var set, where []string
+	for _, column := range columns {
+		set = append(set, column+" = :"+column)
+		where = append(where, column+" = :prev_"+column)
+	}
+	return fmt.Sprintf(`
+UPDATE %s SET %s
+WHERE %s = :%s
+  AND %s
+`, name, strings.Join(set, ", "), key, key, strings.Join(where, "\n  AND "))
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rekey
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+	"github.com/drone/drone/store/user"
+)
+
+var noContext = context.TODO()
+
+func TestRotate(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a user encrypted with the
+	// legacy encryption key.
+	legacy, _ := encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	item := &core.User{Login: "octocat", Token: "ab7c9d", Refresh: "d6e5f4"}
+	if err := user.New(conn, legacy).Create(noContext, item); err != nil {
+		t.Error(err)
+		return
+	}
+
+	keyring, err := encrypt.NewKeyring("2020", map[string]string{
+		"2020": "ea1c5a9145c8a5ce8231f8b186dbcabc",
+	}, "fb4b4d6267c8a5ce8231f8b186dbca92")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	result, err := Rotate(noContext, conn, keyring, Users)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := result.Rotated, 1; got != want {
+		t.Errorf("Want %d rotated rows, got %d", want, got)
+	}
+
+	// the legacy key is removed from the keyring to verify
+	// the row is encrypted with the active key.
+	keyring, _ = encrypt.NewKeyring("2020", map[string]string{
+		"2020": "ea1c5a9145c8a5ce8231f8b186dbcabc",
+	}, "")
+	got, err := user.New(conn, keyring).Find(noContext, item.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got.Token != item.Token || got.Refresh != item.Refresh {
+		t.Errorf("Want oauth tokens decrypted with the active key")
+	}
+
+	result, err = Rotate(noContext, conn, keyring, Users)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := result.Skipped, 1; got != want {
+		t.Errorf("Want %d skipped rows, got %d", want, got)
+	}
+}
@@ -50,11 +50,17 @@ func toParams(stage *core.Stage) map[string]interface{} {
 		"stage_stopped":    stage.Stopped,
 		"stage_created":    stage.Created,
 		"stage_updated":    stage.Updated,
+		"stage_expires":    stage.Expires,
+		"stage_priority":   stage.Priority,
+		"stage_reason":     stage.Reason,
 		"stage_version":    stage.Version,
 		"stage_on_success": stage.OnSuccess,
 		"stage_on_failure": stage.OnFailure,
 		"stage_depends_on": encodeSlice(stage.DependsOn),
 		"stage_labels":     encodeParams(stage.Labels),
+		"stage_attempt":    stage.Attempt,
+		"stage_retry":      encodeRetry(stage.Retry),
+		"stage_timeout":    encodeTimeout(stage.Timeout),
 	}
 }
 
@@ -68,11 +74,23 @@ func encodeParams(v map[string]string) types.JSONText {
 	return types.JSONText(raw)
 }
 
+func encodeRetry(v *core.RetryPolicy) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+func encodeTimeout(v *core.TimeoutPolicy) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
 // helper function scans the sql.Row and copies the column
 // values to the destination object.
 func scanRow(scanner db.Scanner, dest *core.Stage) error {
 	depJSON := types.JSONText{}
 	labJSON := types.JSONText{}
+	retJSON := types.JSONText{}
+	timJSON := types.JSONText{}
 	err := scanner.Scan(
 		&dest.ID,
 		&dest.RepoID,
@@ -96,14 +114,22 @@ func scanRow(scanner db.Scanner, dest *core.Stage) error {
 		&dest.Stopped,
 		&dest.Created,
 		&dest.Updated,
+		&dest.Expires,
+		&dest.Priority,
+		&dest.Reason,
 		&dest.Version,
 		&dest.OnSuccess,
 		&dest.OnFailure,
 		&depJSON,
 		&labJSON,
+		&dest.Attempt,
+		&retJSON,
+		&timJSON,
 	)
 	json.Unmarshal(depJSON, &dest.DependsOn)
 	json.Unmarshal(labJSON, &dest.Labels)
+	json.Unmarshal(retJSON, &dest.Retry)
+	json.Unmarshal(timJSON, &dest.Timeout)
 	return err
 }
 
@@ -112,6 +138,8 @@ func scanRow(scanner db.Scanner, dest *core.Stage) error {
 func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 	depJSON := types.JSONText{}
 	labJSON := types.JSONText{}
+	retJSON := types.JSONText{}
+	timJSON := types.JSONText{}
 	stepDepJSON := types.JSONText{}
 	err := scanner.Scan(
 		&stage.ID,
@@ -136,11 +164,17 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&stage.Stopped,
 		&stage.Created,
 		&stage.Updated,
+		&stage.Expires,
+		&stage.Priority,
+		&stage.Reason,
 		&stage.Version,
 		&stage.OnSuccess,
 		&stage.OnFailure,
 		&depJSON,
 		&labJSON,
+		&stage.Attempt,
+		&retJSON,
+		&timJSON,
 		&step.ID,
 		&step.StageID,
 		&step.Number,
@@ -155,9 +189,12 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&stepDepJSON,
 		&step.Image,
 		&step.Detached,
+		&step.Attempt,
 	)
 	json.Unmarshal(depJSON, &stage.DependsOn)
 	json.Unmarshal(labJSON, &stage.Labels)
+	json.Unmarshal(retJSON, &stage.Retry)
+	json.Unmarshal(timJSON, &stage.Timeout)
 	json.Unmarshal(stepDepJSON, &step.DependsOn)
 	return err
 }
@@ -239,11 +239,17 @@ SELECT
 ,stage_stopped
 ,stage_created
 ,stage_updated
+,stage_expires
+,stage_priority
+,stage_reason
 ,stage_version
 ,stage_on_success
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_attempt
+,stage_retry
+,stage_timeout
 FROM stages
 `
 
@@ -307,11 +313,17 @@ SELECT
 ,stage_stopped
 ,stage_created
 ,stage_updated
+,stage_expires
+,stage_priority
+,stage_reason
 ,stage_version
 ,stage_on_success
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_attempt
+,stage_retry
+,stage_timeout
 ,step_id
 ,step_stage_id
 ,step_number
@@ -326,6 +338,7 @@ SELECT
 ,step_depends_on
 ,step_image
 ,step_detached
+,step_attempt
 FROM stages
   LEFT JOIN steps
 	ON stages.stage_id=steps.step_stage_id
@@ -351,11 +364,17 @@ SET
 ,stage_stopped = :stage_stopped
 ,stage_created = :stage_created
 ,stage_updated = :stage_updated
+,stage_expires = :stage_expires
+,stage_priority = :stage_priority
+,stage_reason = :stage_reason
 ,stage_version = :stage_version_new
 ,stage_on_success = :stage_on_success
 ,stage_on_failure = :stage_on_failure
 ,stage_depends_on = :stage_depends_on
 ,stage_labels = :stage_labels
+,stage_attempt = :stage_attempt
+,stage_retry = :stage_retry
+,stage_timeout = :stage_timeout
 WHERE stage_id = :stage_id
   AND stage_version = :stage_version_old
 `
@@ -383,11 +402,17 @@ INSERT INTO stages (
 ,stage_stopped
 ,stage_created
 ,stage_updated
+,stage_expires
+,stage_priority
+,stage_reason
 ,stage_version
 ,stage_on_success
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_attempt
+,stage_retry
+,stage_timeout
 ) VALUES (
  :stage_repo_id
 ,:stage_build_id
@@ -410,11 +435,17 @@ INSERT INTO stages (
 ,:stage_stopped
 ,:stage_created
 ,:stage_updated
+,:stage_expires
+,:stage_priority
+,:stage_reason
 ,:stage_version
 ,:stage_on_success
 ,:stage_on_failure
 ,:stage_depends_on
 ,:stage_labels
+,:stage_attempt
+,:stage_retry
+,:stage_timeout
 )
 `
 
@@ -437,6 +468,7 @@ INSERT INTO steps (
 ,step_depends_on
 ,step_image
 ,step_detached
+,step_attempt
 ) VALUES (
  :step_stage_id
 ,:step_number
@@ -451,5 +483,6 @@ INSERT INTO steps (
 ,:step_depends_on
 ,:step_image
 ,:step_detached
+,:step_attempt
 )
 `
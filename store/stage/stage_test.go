@@ -56,6 +56,9 @@ func testStageCreate(store *stageStore, build *core.Build) func(t *testing.T) {
 			ExitCode: 0,
 			Started:  1522878684,
 			Stopped:  0,
+			Attempt:  1,
+			Retry:    &core.RetryPolicy{Attempts: 3, ExitCodes: []int{137}},
+			Timeout:  &core.TimeoutPolicy{Queue: 1800},
 		}
 		err := store.Create(noContext, item)
 		if err != nil {
@@ -247,5 +250,14 @@ func testStage(item *core.Stage) func(t *testing.T) {
 		if got, want := item.RepoID, int64(42); got != want {
 			t.Errorf("Want RepoID %d, got %d", want, got)
 		}
+		if got, want := item.Attempt, 1; got != want {
+			t.Errorf("Want Attempt %d, got %d", want, got)
+		}
+		if item.Retry == nil || item.Retry.Attempts != 3 {
+			t.Errorf("Want Retry policy with 3 attempts, got %v", item.Retry)
+		}
+		if item.Timeout == nil || item.Timeout.Queue != 1800 {
+			t.Errorf("Want Timeout policy with queue timeout, got %v", item.Timeout)
+		}
 	}
 }
@@ -37,6 +37,7 @@ type nullStep struct {
 	DependsOn types.JSONText
 	Image     sql.NullString
 	Detached  sql.NullBool
+	Attempt   sql.NullInt64
 }
 
 func (s *nullStep) value() *core.Step {
@@ -58,6 +59,7 @@ func (s *nullStep) value() *core.Step {
 		DependsOn: dependsOn,
 		Image:     s.Image.String,
 		Detached:  s.Detached.Bool,
+		Attempt:   int(s.Attempt.Int64),
 	}
 
 	return step
@@ -41,6 +41,7 @@ func toParams(from *core.Step) map[string]interface{} {
 		"step_depends_on": encodeSlice(from.DependsOn),
 		"step_image":      from.Image,
 		"step_detached":   from.Detached,
+		"step_attempt":    from.Attempt,
 	}
 }
 
@@ -68,6 +69,7 @@ func scanRow(scanner db.Scanner, dest *core.Step) error {
 		&depJSON,
 		&dest.Image,
 		&dest.Detached,
+		&dest.Attempt,
 	)
 	json.Unmarshal(depJSON, &dest.DependsOn)
 	return err
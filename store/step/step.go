@@ -143,6 +143,19 @@ func (s *stepStore) Update(ctx context.Context, step *core.Step) error {
 	return err
 }
 
+// Purge deletes all steps for the stage from the datastore.
+func (s *stepStore) Purge(ctx context.Context, id int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{"step_stage_id": id}
+		stmt, args, err := binder.BindNamed(stmtPurge, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
 const queryBase = `
 SELECT
  step_id
@@ -159,6 +172,7 @@ SELECT
 ,step_depends_on
 ,step_image
 ,step_detached
+,step_attempt
 `
 
 const queryKey = queryBase + `
@@ -191,6 +205,7 @@ SET
 ,step_depends_on = :step_depends_on
 ,step_image = :step_image
 ,step_detached = :step_detached
+,step_attempt = :step_attempt
 WHERE step_id = :step_id
   AND step_version = :step_version_old
 `
@@ -210,6 +225,7 @@ INSERT INTO steps (
 ,step_depends_on
 ,step_image
 ,step_detached
+,step_attempt
 ) VALUES (
  :step_stage_id
 ,:step_number
@@ -224,9 +240,15 @@ INSERT INTO steps (
 ,:step_depends_on
 ,:step_image
 ,:step_detached
+,:step_attempt
 )
 `
 
 const stmtInsertPg = stmtInsert + `
 RETURNING step_id
 `
+
+const stmtPurge = `
+DELETE FROM steps
+WHERE step_stage_id = :step_stage_id
+`
@@ -61,6 +61,7 @@ func testStepCreate(store *stepStore, stage *core.Stage) func(t *testing.T) {
 			DependsOn: []string{"backend", "frontend"},
 			Image:     "ubuntu",
 			Detached:  false,
+			Attempt:   2,
 		}
 		err := store.Create(noContext, item)
 		if err != nil {
@@ -78,6 +79,25 @@ func testStepCreate(store *stepStore, stage *core.Stage) func(t *testing.T) {
 		t.Run("List", testStepList(store, stage))
 		t.Run("Update", testStepUpdate(store, item))
 		t.Run("Locking", testStepLocking(store, item))
+		t.Run("Purge", testStepPurge(store, stage))
+	}
+}
+
+func testStepPurge(store *stepStore, stage *core.Stage) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Purge(noContext, stage.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, stage.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
 	}
 }
 
@@ -195,5 +215,8 @@ func testStep(item *core.Step) func(t *testing.T) {
 		if got, want := item.Started, int64(1522878684); got != want {
 			t.Errorf("Want Started %d, got %d", want, got)
 		}
+		if got, want := item.Attempt, 2; got != want {
+			t.Errorf("Want Attempt %d, got %d", want, got)
+		}
 	}
 }
@@ -0,0 +1,232 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new TestStore.
+func New(db *db.DB) core.TestStore {
+	return &testStore{db}
+}
+
+type testStore struct {
+	db *db.DB
+}
+
+func (s *testStore) List(ctx context.Context, id int64) ([]*core.TestCase, error) {
+	var out []*core.TestCase
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"test_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *testStore) ListFailed(ctx context.Context, id int64) ([]*core.TestCase, error) {
+	var out []*core.TestCase
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"test_build_id": id,
+			"status_failed": core.StatusFailing,
+			"status_error":  core.StatusError,
+		}
+		stmt, args, err := binder.BindNamed(queryFailed, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *testStore) Summary(ctx context.Context, id int64) (*core.TestSummary, error) {
+	out := new(core.TestSummary)
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"test_build_id":  id,
+			"status_passed":  core.StatusPassing,
+			"status_failed":  core.StatusFailing,
+			"status_error":   core.StatusError,
+			"status_skipped": core.StatusSkipped,
+		}
+		stmt, args, err := binder.BindNamed(querySummary, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(stmt, args...)
+		return scanSummary(row, out)
+	})
+	return out, err
+}
+
+func (s *testStore) History(ctx context.Context, repo int64, branch, suite, name string, limit int) ([]*core.TestHistory, error) {
+	var out []*core.TestHistory
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"test_repo_id": repo,
+			"test_suite":   suite,
+			"test_name":    name,
+			"build_source": branch,
+			"limit":        limit,
+		}
+		stmt, args, err := binder.BindNamed(queryHistory, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanHistoryRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *testStore) Create(ctx context.Context, tests []*core.TestCase) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		for _, test := range tests {
+			params := toParams(test)
+			stmt, args, err := binder.BindNamed(stmtInsert, params)
+			if err != nil {
+				return err
+			}
+			if _, err := execer.Exec(stmt, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *testStore) Delete(ctx context.Context, step int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{"test_step_id": step}
+		stmt, args, err := binder.BindNamed(stmtDeleteStep, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ test_id
+,test_repo_id
+,test_build_id
+,test_stage_id
+,test_step_id
+,test_suite
+,test_name
+,test_status
+,test_duration
+,test_message
+,test_created
+`
+
+const queryBuild = queryBase + `
+FROM tests
+WHERE test_build_id = :test_build_id
+ORDER BY test_stage_id ASC, test_id ASC
+`
+
+const queryFailed = queryBase + `
+FROM tests
+WHERE test_build_id = :test_build_id
+  AND test_status IN (:status_failed, :status_error)
+ORDER BY test_stage_id ASC, test_id ASC
+`
+
+const querySummary = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(CASE WHEN test_status = :status_passed THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN test_status = :status_failed THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN test_status = :status_error THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN test_status = :status_skipped THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(test_duration), 0)
+FROM tests
+WHERE test_build_id = :test_build_id
+`
+
+const queryHistory = `
+SELECT
+ build_id
+,build_number
+,test_status
+,test_duration
+,test_message
+,build_created
+FROM tests
+INNER JOIN builds ON test_build_id = build_id
+WHERE test_repo_id = :test_repo_id
+  AND test_suite = :test_suite
+  AND test_name = :test_name
+  AND build_source = :build_source
+ORDER BY build_number DESC, test_id DESC
+LIMIT :limit
+`
+
+const stmtDeleteStep = `
+DELETE FROM tests
+WHERE test_step_id = :test_step_id
+`
+
+const stmtInsert = `
+INSERT INTO tests (
+ test_repo_id
+,test_build_id
+,test_stage_id
+,test_step_id
+,test_suite
+,test_name
+,test_status
+,test_duration
+,test_message
+,test_created
+) VALUES (
+ :test_repo_id
+,:test_build_id
+,:test_stage_id
+,:test_step_id
+,:test_suite
+,:test_name
+,:test_status
+,:test_duration
+,:test_message
+,:test_created
+)
+`
@@ -0,0 +1,202 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+)
+
+var noContext = context.TODO()
+
+func TestTest(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1, Name: "default"}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID, Source: "master"}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	// seed with a dummy step
+	astep := &core.Step{StageID: stage.ID, Number: 1, Name: "test"}
+	step.New(conn).Create(noContext, astep)
+
+	store := New(conn).(*testStore)
+	tests := []*core.TestCase{
+		{Suite: "core", Name: "TestA", Status: core.StatusPassing, Duration: 10},
+		{Suite: "core", Name: "TestB", Status: core.StatusFailing, Duration: 20, Message: "expected true"},
+		{Suite: "core", Name: "TestC", Status: core.StatusError, Duration: 30},
+		{Suite: "core", Name: "TestD", Status: core.StatusSkipped},
+	}
+	for _, test := range tests {
+		test.RepoID = arepo.ID
+		test.BuildID = abuild.ID
+		test.StageID = stage.ID
+		test.StepID = astep.ID
+		test.Created = 1522878684
+	}
+
+	t.Run("Create", testCreate(store, tests[:2], 2))
+	t.Run("CreateAppend", testCreate(store, tests[2:], 4))
+	t.Run("List", testList(store, abuild.ID))
+	t.Run("ListFailed", testListFailed(store, abuild.ID))
+	t.Run("Summary", testSummary(store, abuild.ID))
+	t.Run("History", testHistory(store, arepo.ID))
+	t.Run("Delete", testDelete(store, astep.ID))
+}
+
+func testCreate(store *testStore, tests []*core.TestCase, want int) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, tests)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got := countTests(store); got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testDelete(store *testStore, step int64) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, step)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := countTests(store), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+// helper function returns the number of persisted test cases.
+func countTests(store *testStore) int {
+	var count int
+	store.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		return queryer.QueryRow("SELECT COUNT(*) FROM tests").Scan(&count)
+	})
+	return count
+}
+
+func testList(store *testStore, build int64) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, build)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 4; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Name, "TestA"; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+		if got, want := list[0].Duration, int64(10); got != want {
+			t.Errorf("Want duration %d, got %d", want, got)
+		}
+	}
+}
+
+func testListFailed(store *testStore, build int64) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListFailed(noContext, build)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Name, "TestB"; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+		if got, want := list[0].Message, "expected true"; got != want {
+			t.Errorf("Want message %q, got %q", want, got)
+		}
+		if got, want := list[1].Name, "TestC"; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+	}
+}
+
+func testSummary(store *testStore, build int64) func(t *testing.T) {
+	return func(t *testing.T) {
+		summary, err := store.Summary(noContext, build)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := &core.TestSummary{
+			Total:    4,
+			Passed:   1,
+			Failed:   1,
+			Errored:  1,
+			Skipped:  1,
+			Duration: 60,
+		}
+		if *summary != *want {
+			t.Errorf("Want summary %+v, got %+v", want, summary)
+		}
+	}
+}
+
+func testHistory(store *testStore, repo int64) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.History(noContext, repo, "master", "core", "TestB", 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Number, int64(1); got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+		if got, want := list[0].Status, core.StatusFailing; got != want {
+			t.Errorf("Want status %q, got %q", want, got)
+		}
+
+		list, err = store.History(noContext, repo, "develop", "core", "TestB", 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package token
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestToken(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*tokenStore)
+	t.Run("Create", testTokenCreate(store))
+}
+
+func testTokenCreate(store *tokenStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.AccessToken{
+			UserID:  1,
+			Name:    "deploy",
+			Scopes:  []string{core.ScopeRead, core.ScopeBuildsWrite},
+			Repo:    "octocat/hello-world",
+			Hash:    "7c4a8d09ca3762af61e59520943dc26494f8941b",
+			Expires: 1600000000,
+			Created: 1500000000,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want token ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testTokenFind(store, item))
+		t.Run("FindHash", testTokenFindHash(store, item))
+		t.Run("List", testTokenList(store, item))
+		t.Run("Update", testTokenUpdate(store, item))
+		t.Run("Touch", testTokenTouch(store, item))
+		t.Run("Delete", testTokenDelete(store, item))
+	}
+}
+
+func testTokenFind(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testToken(item))
+		}
+	}
+}
+
+func testTokenFindHash(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.FindHash(noContext, token.Hash)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testToken(item))
+		}
+	}
+}
+
+func testTokenList(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, token.UserID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testToken(list[0]))
+		}
+		list, err = store.List(noContext, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testTokenUpdate(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Name = "deploy"
+		before.LastUsed = 1550000000
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Name, before.Name; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+		// the last used timestamp is only updated by touch.
+		if got, want := after.LastUsed, token.LastUsed; got != want {
+			t.Errorf("Want last used %d, got %d", want, got)
+		}
+	}
+}
+
+func testTokenTouch(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.LastUsed = 1550000000
+		err = store.Touch(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.LastUsed, before.LastUsed; got != want {
+			t.Errorf("Want last used %d, got %d", want, got)
+		}
+	}
+}
+
+func testTokenDelete(store *tokenStore, token *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, token)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, token.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testToken(item *core.AccessToken) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.UserID, int64(1); got != want {
+			t.Errorf("Want user id %d, got %d", want, got)
+		}
+		if got, want := item.Name, "deploy"; got != want {
+			t.Errorf("Want name %q, got %q", want, got)
+		}
+		if got, want := len(item.Scopes), 2; got != want {
+			t.Errorf("Want %d scopes, got %d", want, got)
+		} else if got, want := item.Scopes[1], core.ScopeBuildsWrite; got != want {
+			t.Errorf("Want scope %q, got %q", want, got)
+		}
+		if got, want := item.Repo, "octocat/hello-world"; got != want {
+			t.Errorf("Want repo %q, got %q", want, got)
+		}
+		if got, want := item.Expires, int64(1600000000); got != want {
+			t.Errorf("Want expires %d, got %d", want, got)
+		}
+		if got, want := item.Created, int64(1500000000); got != want {
+			t.Errorf("Want created %d, got %d", want, got)
+		}
+	}
+}
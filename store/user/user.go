@@ -176,13 +176,21 @@ func (s *userStore) Update(ctx context.Context, user *core.User) error {
 
 // Delete deletes a user from the datastore.
 func (s *userStore) Delete(ctx context.Context, user *core.User) error {
-	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
 		params := map[string]interface{}{"user_id": user.ID}
 		stmt, args, err := binder.BindNamed(stmtDelete, params)
 		if err != nil {
 			return err
 		}
 		_, err = execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		stmt, args, err = binder.BindNamed(stmtDeleteTokens, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
 		return err
 	})
 }
@@ -291,6 +299,10 @@ const stmtDelete = `
 DELETE FROM users WHERE user_id = :user_id
 `
 
+const stmtDeleteTokens = `
+DELETE FROM tokens WHERE token_user_id = :user_id
+`
+
 const stmtInsert = `
 INSERT INTO users (
  user_login
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Webhook structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, hook *core.Webhook) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(hook.Signer)
+	if err != nil {
+		return nil, err
+	}
+	events, _ := json.Marshal(hook.Events)
+	return map[string]interface{}{
+		"webhook_id":          hook.ID,
+		"webhook_repo_id":     hook.RepoID,
+		"webhook_endpoint":    hook.Endpoint,
+		"webhook_signer":      ciphertext,
+		"webhook_skip_verify": hook.SkipVerify,
+		"webhook_events":      string(events),
+		"webhook_created":     hook.Created,
+		"webhook_updated":     hook.Updated,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Webhook) error {
+	var ciphertext []byte
+	var events string
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Endpoint,
+		&ciphertext,
+		&dst.SkipVerify,
+		&events,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return err
+	}
+	plaintext, err := encrypt.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	dst.Signer = plaintext
+	dst.Events = nil
+	json.Unmarshal([]byte(events), &dst.Events)
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Webhook, error) {
+	defer rows.Close()
+
+	hooks := []*core.Webhook{}
+	for rows.Next() {
+		hook := new(core.Webhook)
+		err := scanRow(encrypt, rows, hook)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
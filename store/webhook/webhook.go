@@ -0,0 +1,193 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new Webhook database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.WebhookStore {
+	return &webhookStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type webhookStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *webhookStore) List(ctx context.Context, id int64) ([]*core.Webhook, error) {
+	var out []*core.Webhook
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"webhook_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *webhookStore) Find(ctx context.Context, id int64) (*core.Webhook, error) {
+	out := &core.Webhook{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(s.enc, row, out)
+	})
+	return out, err
+}
+
+func (s *webhookStore) Create(ctx context.Context, hook *core.Webhook) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, hook)
+	}
+	return s.create(ctx, hook)
+}
+
+func (s *webhookStore) create(ctx context.Context, hook *core.Webhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, hook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		hook.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *webhookStore) createPostgres(ctx context.Context, hook *core.Webhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, hook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPostgres, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&hook.ID)
+	})
+}
+
+func (s *webhookStore) Update(ctx context.Context, hook *core.Webhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, hook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *webhookStore) Delete(ctx context.Context, hook *core.Webhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{"webhook_id": hook.ID}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ webhook_id
+,webhook_repo_id
+,webhook_endpoint
+,webhook_signer
+,webhook_skip_verify
+,webhook_events
+,webhook_created
+,webhook_updated
+`
+
+const queryKey = queryBase + `
+FROM webhooks
+WHERE webhook_id = :webhook_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM webhooks
+WHERE webhook_repo_id = :webhook_repo_id
+ORDER BY webhook_id
+`
+
+const stmtInsert = `
+INSERT INTO webhooks (
+ webhook_repo_id
+,webhook_endpoint
+,webhook_signer
+,webhook_skip_verify
+,webhook_events
+,webhook_created
+,webhook_updated
+) VALUES (
+ :webhook_repo_id
+,:webhook_endpoint
+,:webhook_signer
+,:webhook_skip_verify
+,:webhook_events
+,:webhook_created
+,:webhook_updated
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING webhook_id
+`
+
+const stmtUpdate = `
+UPDATE webhooks SET
+ webhook_endpoint = :webhook_endpoint
+,webhook_signer = :webhook_signer
+,webhook_skip_verify = :webhook_skip_verify
+,webhook_events = :webhook_events
+,webhook_updated = :webhook_updated
+WHERE webhook_id = :webhook_id
+`
+
+const stmtDelete = `
+DELETE FROM webhooks
+WHERE webhook_id = :webhook_id
+`
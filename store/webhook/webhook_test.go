@@ -0,0 +1,140 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestWebhook(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn, nil).(*webhookStore)
+	store.enc, _ = encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	t.Run("Create", testWebhookCreate(store, repo))
+}
+
+func testWebhookCreate(store *webhookStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Webhook{
+			RepoID:     repo.ID,
+			Endpoint:   "https://company.com/hooks",
+			Signer:     "correct-horse-battery-staple",
+			SkipVerify: true,
+			Events:     []string{"build:updated"},
+			Created:    1,
+			Updated:    2,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want webhook ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testWebhookFind(store, item))
+		t.Run("List", testWebhookList(store, repo))
+		t.Run("Update", testWebhookUpdate(store, item))
+		t.Run("Delete", testWebhookDelete(store, item))
+	}
+}
+
+func testWebhookFind(store *webhookStore, hook *core.Webhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, hook.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testWebhookFields(item, hook))
+		}
+	}
+}
+
+func testWebhookList(store *webhookStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testWebhookUpdate(store *webhookStore, hook *core.Webhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, hook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Endpoint = "https://company.com/hooks/v2"
+		before.Signer = "password"
+		before.Events = nil
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, hook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		t.Run("Fields", testWebhookFields(after, before))
+	}
+}
+
+func testWebhookDelete(store *webhookStore, hook *core.Webhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, hook)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, hook.ID)
+		if err == nil {
+			t.Errorf("Want error deleting webhook")
+		}
+	}
+}
+
+func testWebhookFields(got, want *core.Webhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
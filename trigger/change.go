@@ -14,59 +14,51 @@
 
 package trigger
 
-// import (
-// 	"context"
-// 	"regexp"
-// 	"strconv"
+import (
+	"context"
 
-// 	"github.com/drone/drone/core"
-// 	"github.com/drone/go-scm/scm"
-// )
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+)
 
-// func listChanges(client *scm.Client, repo *core.Repository, build *core.Build) ([]string, error) {
-// 	switch build.Event {
-// 	case core.EventPullRequest:
-// 		return listChangesPullRequest(client, repo, build)
-// 	case core.EventPush:
-// 		return listChangesPush(client, repo, build)
-// 	default:
-// 		return nil, nil
-// 	}
-// }
+// listChanges returns the list of files changed by the push
+// or pull request. Changed files are not available for other
+// event types, in which case an empty list is returned.
+func listChanges(ctx context.Context, commits core.CommitService, user *core.User, repo *core.Repository, hook *core.Hook) ([]string, error) {
+	switch hook.Event {
+	case core.EventPush, core.EventPullRequest:
+	default:
+		return nil, nil
+	}
+	// TODO (bradrydzewski) some tag hooks provide the tag but do
+	// not provide the sha, in which case we should use the ref
+	// instead of the sha.
+	if hook.After == "" {
+		return nil, nil
+	}
+	changes, err := commits.ListChanges(ctx, user, repo.Slug, hook.After, hook.Ref)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths, nil
+}
 
-// func listChangesPullRequest(client *scm.Client, repo *core.Repository, build *core.Build) ([]string, error) {
-// 	var paths []string
-// 	pr, err := parsePullRequest(build.Ref)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	change, _, err := client.PullRequests.ListChanges(context.Background(), repo.Slug, pr, scm.ListOptions{})
-// 	if err == nil {
-// 		for _, file := range change {
-// 			paths = append(paths, file.Path)
-// 		}
-// 	}
-// 	return paths, err
-// }
-
-// func listChangesPush(client *scm.Client, repo *core.Repository, build *core.Build) ([]string, error) {
-// 	var paths []string
-// 	// TODO (bradrydzewski) some tag hooks provide the tag but do
-// 	// not provide the sha, in which case we should use the ref
-// 	// instead of the sha.
-// 	change, _, err := client.Git.ListChanges(context.Background(), repo.Slug, build.After, scm.ListOptions{})
-// 	if err == nil {
-// 		for _, file := range change {
-// 			paths = append(paths, file.Path)
-// 		}
-// 	}
-// 	return paths, err
-// }
-
-// func parsePullRequest(ref string) (int, error) {
-// 	return strconv.Atoi(
-// 		pre.FindString(ref),
-// 	)
-// }
-
-// var pre = regexp.MustCompile("\\d+")
+// hasPaths returns true if any pipeline in the manifest
+// defines a paths trigger condition.
+func hasPaths(manifest *yaml.Manifest) bool {
+	for _, document := range manifest.Resources {
+		pipeline, ok := document.(*yaml.Pipeline)
+		if !ok {
+			continue
+		}
+		paths := pipeline.Trigger.Paths
+		if len(paths.Include)+len(paths.Exclude) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// eventPriority returns the default stage priority for the
+// build event. Deployments are prioritized over pushes and
+// pull requests, which are prioritized over cron jobs.
+func eventPriority(event string) int {
+	switch event {
+	case core.EventPromote, core.EventRollback:
+		return core.PriorityHigh
+	case core.EventCron:
+		return core.PriorityLow
+	default:
+		return core.PriorityNormal
+	}
+}
+
+// parsePriority returns the priority defined in the pipeline
+// documents, keyed by pipeline name. The priority attribute is
+// not part of the yaml specification and is therefore parsed
+// from the raw documents. The priority is limited to the range
+// of the low and high priorities, since the pipeline documents
+// can be edited by any author, including pull requests.
+func parsePriority(data string) map[string]int {
+	resources, err := yaml.ParseRawString(data)
+	if err != nil {
+		return nil
+	}
+	priority := map[string]int{}
+	for _, resource := range resources {
+		if resource.Kind != "pipeline" && resource.Kind != "" {
+			continue
+		}
+		out := struct {
+			Name     string
+			Priority *int
+		}{}
+		if err := yamlv2.Unmarshal(resource.Data, &out); err != nil {
+			continue
+		}
+		if out.Priority != nil {
+			priority[out.Name] = clampPriority(*out.Priority)
+		}
+	}
+	return priority
+}
+
+// helper function limits the priority to the range of the low
+// and high priorities.
+func clampPriority(priority int) int {
+	switch {
+	case priority < core.PriorityLow:
+		return core.PriorityLow
+	case priority > core.PriorityHigh:
+		return core.PriorityHigh
+	default:
+		return priority
+	}
+}
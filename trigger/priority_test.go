@@ -0,0 +1,69 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_eventPriority(t *testing.T) {
+	tests := []struct {
+		event string
+		want  int
+	}{
+		{core.EventPromote, core.PriorityHigh},
+		{core.EventRollback, core.PriorityHigh},
+		{core.EventPush, core.PriorityNormal},
+		{core.EventPullRequest, core.PriorityNormal},
+		{core.EventTag, core.PriorityNormal},
+		{core.EventCron, core.PriorityLow},
+	}
+	for _, test := range tests {
+		if got, want := eventPriority(test.event), test.want; got != want {
+			t.Errorf("Want priority %d for event %s, got %d", want, test.event, got)
+		}
+	}
+}
+
+func Test_parsePriority(t *testing.T) {
+	data := `
+kind: pipeline
+name: build
+priority: 5
+
+---
+kind: pipeline
+name: test
+
+---
+kind: pipeline
+name: deploy
+priority: 1000000
+
+---
+kind: pipeline
+name: cron
+priority: -1000000
+
+---
+kind: secret
+name: password
+priority: 20
+`
+	got := parsePriority(data)
+	want := map[string]int{
+		"build":  5,
+		"deploy": core.PriorityHigh,
+		"cron":   core.PriorityLow,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// parseRetry returns the retry policy defined in the pipeline
+// documents, keyed by pipeline name. The retry attribute is not
+// part of the yaml specification and is therefore parsed from
+// the raw documents.
+func parseRetry(data string) map[string]*core.RetryPolicy {
+	resources, err := yaml.ParseRawString(data)
+	if err != nil {
+		return nil
+	}
+	policies := map[string]*core.RetryPolicy{}
+	for _, resource := range resources {
+		if resource.Kind != "pipeline" && resource.Kind != "" {
+			continue
+		}
+		out := struct {
+			Name  string
+			Retry *struct {
+				Attempts  int      `yaml:"attempts"`
+				ExitCodes []int    `yaml:"exit_codes"`
+				Errors    []string `yaml:"errors"`
+			}
+		}{}
+		if err := yamlv2.Unmarshal(resource.Data, &out); err != nil {
+			continue
+		}
+		if out.Retry == nil || out.Retry.Attempts < 2 {
+			continue
+		}
+		policy := &core.RetryPolicy{
+			Attempts:  out.Retry.Attempts,
+			ExitCodes: out.Retry.ExitCodes,
+			Errors:    out.Retry.Errors,
+		}
+		if policy.Attempts > core.RetryLimit {
+			policy.Attempts = core.RetryLimit
+		}
+		policies[out.Name] = policy
+	}
+	return policies
+}
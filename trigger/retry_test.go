@@ -0,0 +1,55 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseRetry(t *testing.T) {
+	data := `
+kind: pipeline
+name: build
+retry:
+  attempts: 3
+  exit_codes: [ 137, 143 ]
+  errors: [ "connection reset by peer" ]
+
+---
+kind: pipeline
+name: test
+retry:
+  attempts: 20
+
+---
+kind: pipeline
+name: deploy
+retry:
+  attempts: 1
+
+---
+kind: pipeline
+name: lint
+`
+	got := parseRetry(data)
+	want := map[string]*core.RetryPolicy{
+		"build": {
+			Attempts:  3,
+			ExitCodes: []int{137, 143},
+			Errors:    []string{"connection reset by peer"},
+		},
+		"test": {
+			Attempts: core.RetryLimit,
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
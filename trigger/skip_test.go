@@ -83,56 +83,72 @@ func Test_skipEvent(t *testing.T) {
 	}
 }
 
-// func Test_skipPath(t *testing.T) {
-// 	tests := []struct {
-// 		config string
-// 		paths  []string
-// 		want   bool
-// 	}{
-// 		{
-// 			config: "trigger: { }",
-// 			paths:  []string{},
-// 			want:   false,
-// 		},
-// 		{
-// 			config: "trigger: { }",
-// 			paths:  []string{"README.md"},
-// 			want:   false,
-// 		},
-// 		{
-// 			config: "trigger: { paths: foo/* }",
-// 			paths:  []string{"foo/README"},
-// 			want:   false,
-// 		},
-// 		{
-// 			config: "trigger: { paths: foo/* }",
-// 			paths:  []string{"bar/README"},
-// 			want:   true,
-// 		},
-// 		// if empty changeset, never skip the pipeline
-// 		{
-// 			config: "trigger: { paths: foo/* }",
-// 			paths:  []string{},
-// 			want:   false,
-// 		},
-// 		// if max changeset, never skip the pipeline
-// 		{
-// 			config: "trigger: { paths: foo/* }",
-// 			paths:  make([]string, 400),
-// 			want:   false,
-// 		},
-// 	}
-// 	for i, test := range tests {
-// 		document, err := config.ParseString(test.config)
-// 		if err != nil {
-// 			t.Error(err)
-// 		}
-// 		got, want := skipPaths(document, test.paths), test.want
-// 		if got != want {
-// 			t.Errorf("Want test %d to return %v", i, want)
-// 		}
-// 	}
-// }
+func Test_skipPaths(t *testing.T) {
+	tests := []struct {
+		config string
+		paths  []string
+		want   bool
+	}{
+		{
+			config: "kind: pipeline\ntrigger: { }",
+			paths:  []string{},
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { }",
+			paths:  []string{"README.md"},
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { paths: foo/* }",
+			paths:  []string{"foo/README"},
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { paths: foo/* }",
+			paths:  []string{"bar/README"},
+			want:   true,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { paths: foo/* }",
+			paths:  []string{"bar/README", "foo/README"},
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { paths: { exclude: [ docs/* ] } }",
+			paths:  []string{"docs/README"},
+			want:   true,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { paths: { exclude: [ docs/* ] } }",
+			paths:  []string{"docs/README", "main.go"},
+			want:   false,
+		},
+		// if empty changeset, never skip the pipeline
+		{
+			config: "kind: pipeline\ntrigger: { paths: foo/* }",
+			paths:  []string{},
+			want:   false,
+		},
+		// if max changeset, never skip the pipeline
+		{
+			config: "kind: pipeline\ntrigger: { paths: foo/* }",
+			paths:  make([]string, 400),
+			want:   false,
+		},
+	}
+	for i, test := range tests {
+		manifest, err := yaml.ParseString(test.config)
+		if err != nil {
+			t.Error(err)
+		}
+		pipeline := manifest.Resources[0].(*yaml.Pipeline)
+		got, want := skipPaths(pipeline, test.paths), test.want
+		if got != want {
+			t.Errorf("Want test %d to return %v", i, want)
+		}
+	}
+}
 
 func Test_skipMessage(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"time"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// parseTimeout returns the timeout policy defined in the
+// pipeline documents, and the build timeout, keyed by pipeline
+// name. The timeout attributes are not part of the yaml
+// specification and are therefore parsed from the raw documents.
+func parseTimeout(data string) (map[string]*core.TimeoutPolicy, map[string]int64) {
+	resources, err := yaml.ParseRawString(data)
+	if err != nil {
+		return nil, nil
+	}
+	policies := map[string]*core.TimeoutPolicy{}
+	builds := map[string]int64{}
+	for _, resource := range resources {
+		if resource.Kind != "pipeline" && resource.Kind != "" {
+			continue
+		}
+		out := struct {
+			Name    string
+			Timeout struct {
+				Queue string `yaml:"queue"`
+				Build string `yaml:"build"`
+			}
+			Steps []struct {
+				Name    string
+				Timeout string
+			}
+		}{}
+		if err := yamlv2.Unmarshal(resource.Data, &out); err != nil {
+			continue
+		}
+		policy := &core.TimeoutPolicy{
+			Queue: parseSeconds(out.Timeout.Queue),
+			Steps: map[string]int64{},
+		}
+		for _, step := range out.Steps {
+			if timeout := parseSeconds(step.Timeout); timeout > 0 {
+				policy.Steps[step.Name] = timeout
+			}
+		}
+		if len(policy.Steps) == 0 {
+			policy.Steps = nil
+		}
+		if policy.Queue > 0 || policy.Steps != nil {
+			policies[out.Name] = policy
+		}
+		if timeout := parseSeconds(out.Timeout.Build); timeout > 0 {
+			builds[out.Name] = timeout
+		}
+	}
+	return policies, builds
+}
+
+// helper function parses the duration string and returns
+// the duration in seconds.
+func parseSeconds(s string) int64 {
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return int64(d / time.Second)
+}
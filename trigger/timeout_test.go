@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseTimeout(t *testing.T) {
+	data := `
+kind: pipeline
+name: build
+timeout:
+  queue: 30m
+  build: 2h
+steps:
+- name: test
+  timeout: 10m
+- name: lint
+
+---
+kind: pipeline
+name: deploy
+timeout:
+  build: 1h30m
+  queue: invalid
+
+---
+kind: pipeline
+name: notify
+`
+	policies, builds := parseTimeout(data)
+	wantPolicies := map[string]*core.TimeoutPolicy{
+		"build": {
+			Queue: 1800,
+			Steps: map[string]int64{"test": 600},
+		},
+	}
+	if diff := cmp.Diff(policies, wantPolicies); diff != "" {
+		t.Errorf(diff)
+	}
+	wantBuilds := map[string]int64{
+		"build":  7200,
+		"deploy": 5400,
+	}
+	if diff := cmp.Diff(builds, wantBuilds); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -275,12 +275,17 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		verified = false
 	}
 
-	// var paths []string
-	// paths, err := listChanges(t.client, repo, base)
-	// if err != nil {
-	// 	logger.Warn().Err(err).
-	// 		Msg("cannot fetch changeset")
-	// }
+	// the changeset is only fetched from the remote system
+	// when one or more pipelines define a paths condition,
+	// to avoid an unnecessary api call for every hook.
+	var paths []string
+	if hasPaths(manifest) {
+		paths, err = listChanges(ctx, t.commits, user, repo, base)
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Warnln("trigger: cannot fetch changeset")
+		}
+	}
 
 	var matched []*yaml.Pipeline
 	var dag = dag.New()
@@ -321,6 +326,9 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		} else if skipCron(pipeline, base.Cron) {
 			logger = logger.WithField("pipeline", pipeline.Name)
 			logger.Infoln("trigger: skipping pipeline, does not match cron job")
+		} else if skipPaths(pipeline, paths) {
+			logger = logger.WithField("pipeline", pipeline.Name)
+			logger.Infoln("trigger: skipping pipeline, does not match changed paths")
 		} else {
 			matched = append(matched, pipeline)
 			node.Skip = false
@@ -375,6 +383,13 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		Updated:      time.Now().Unix(),
 	}
 
+	// the stage priority defaults to the event priority, unless
+	// the pipeline defines a priority, and is offset by the
+	// repository priority.
+	priorities := parsePriority(raw.Data)
+	policies := parseRetry(raw.Data)
+	timeouts, deadlines := parseTimeout(raw.Data)
+
 	stages := make([]*core.Stage, len(matched))
 	for i, match := range matched {
 		onSuccess := match.Trigger.Status.Match(core.StatusPassing)
@@ -395,11 +410,15 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			Kernel:    match.Platform.Version,
 			Limit:     match.Concurrency.Limit,
 			LimitRepo: int(repo.Throttle),
+			Priority:  eventPriority(base.Event),
 			Status:    core.StatusWaiting,
 			DependsOn: match.DependsOn,
 			OnSuccess: onSuccess,
 			OnFailure: onFailure,
 			Labels:    match.Node,
+			Attempt:   1,
+			Retry:     policies[match.Name],
+			Timeout:   timeouts[match.Name],
 			Created:   time.Now().Unix(),
 			Updated:   time.Now().Unix(),
 		}
@@ -416,7 +435,18 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		if stage.Name == "" {
 			stage.Name = "default"
 		}
-		if verified == false {
+		if priority, ok := priorities[match.Name]; ok {
+			stage.Priority = priority
+		}
+		// the build timeout is the shortest timeout defined
+		// by the matching pipelines.
+		if timeout, ok := deadlines[match.Name]; ok {
+			if build.Timeout == 0 || timeout < build.Timeout {
+				build.Timeout = timeout
+			}
+		}
+		stage.Priority += int(repo.Priority)
+		if verified == false || base.Blocked {
 			stage.Status = core.StatusBlocked
 		} else if len(stage.DependsOn) == 0 {
 			stage.Status = core.StatusPending
@@ -485,13 +515,6 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		go t.canceler.CancelPending(ctx, repo, build)
 	}
 
-	// err = t.hooks.SendEndpoint(ctx, payload, repo.Endpoints.Webhook)
-	// if err != nil {
-	// 	logger.Warn().Err(err).
-	// 		Int64("build", build.Number).
-	// 		Msg("cannot send user-defined webhook")
-	// }
-
 	// // we should only synchronize the cronjob list on push
 	// // events to the default branch.
 	// if build.Event == core.EventPush &&
@@ -275,6 +275,50 @@ func TestTrigger_SkipBranch(t *testing.T) {
 	}
 }
 
+// this test verifies that no build should be scheduled if the
+// changed files do not match the paths defined in the yaml.
+func TestTrigger_SkipPaths(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipPaths, nil)
+
+	mockConvertService := mock.NewMockConvertService(controller)
+	mockConvertService.EXPECT().Convert(gomock.Any(), gomock.Any()).Return(dummyYamlSkipPaths, nil)
+
+	mockValidateService := mock.NewMockValidateService(controller)
+	mockValidateService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return([]*core.Change{{Path: "docs/README.md"}}, nil)
+
+	triggerer := New(
+		nil,
+		mockConfigService,
+		mockConvertService,
+		mockCommits,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		mockValidateService,
+		nil,
+	)
+
+	build, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Errorf("Expect build silently skipped if paths do not match")
+	}
+	if build != nil {
+		t.Errorf("Expect build skipped if paths do not match")
+	}
+}
+
 // this test verifies that no build should be scheduled if the
 // hook event does not match the events defined in the yaml.
 func TestTrigger_SkipEvent(t *testing.T) {
@@ -552,6 +596,7 @@ var (
 		OnSuccess: true,
 		OnFailure: false,
 		Status:    core.StatusPending,
+		Attempt:   1,
 	}
 
 	dummyStages = []*core.Stage{
@@ -590,6 +635,15 @@ trigger:
     - push`,
 	}
 
+	dummyYamlSkipPaths = &core.Config{
+		Data: `
+kind: pipeline
+trigger:
+  paths:
+    include:
+    - src/*`,
+	}
+
 	dummyYamlSkipAction = &core.Config{
 		Data: `
 kind: pipeline